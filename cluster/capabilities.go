@@ -0,0 +1,53 @@
+package cluster
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Capabilities is what a worker advertises when it joins. Zero fields are
+// unknown. The solver runs on the CPU only, so GPUs are reported but not
+// used for scheduling.
+type Capabilities struct {
+	Cores  int    `json:"cores"`
+	Memory uint64 `json:"memory"`
+	GPUs   int    `json:"gpus"`
+}
+
+// LocalCapabilities returns the cores the process may use, the memory the
+// system has available and the number of NVIDIA GPUs, where it can tell.
+func LocalCapabilities() Capabilities {
+	gpus, _ := filepath.Glob("/dev/nvidia[0-9]*")
+	return Capabilities{
+		Cores:  runtime.GOMAXPROCS(0),
+		Memory: AvailableMemory(),
+		GPUs:   len(gpus),
+	}
+}
+
+// AvailableMemory reads MemAvailable from /proc/meminfo, or returns 0 where
+// there is no such file.
+func AvailableMemory() uint64 {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "MemAvailable:" && fields[2] == "kB" {
+			kilobytes, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kilobytes * 1024
+		}
+	}
+	return 0
+}
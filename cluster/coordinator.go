@@ -0,0 +1,266 @@
+// Package cluster spreads one run over worker processes as the islands of an
+// island model: every worker evolves its own population and the coordinator
+// passes the best chromosomes of each island on to the next one in a ring.
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// Assignment is what a worker gets when it joins: the graph in DIMACS format
+// and the islands it is to run.
+type Assignment struct {
+	DIMACS       string   `json:"dimacs"`
+	Islands      []Island `json:"islands"`
+	MigrateEvery int      `json:"migrate_every"`
+	Migrants     int      `json:"migrants"`
+}
+
+// Island is one island of an assignment with its solver config, seeded
+// differently for every island.
+type Island struct {
+	Index  int             `json:"index"`
+	Config ga.SolverConfig `json:"config"`
+}
+
+// minPopSize is the smallest population an island is shrunk to when a worker
+// is short of memory.
+const minPopSize = 10
+
+type exchangeRequest struct {
+	Island     int           `json:"island"`
+	Generation int           `json:"generation"`
+	BestScore  int           `json:"best_score"`
+	Emigrants  ga.Population `json:"emigrants"`
+}
+
+type exchangeResponse struct {
+	Immigrants ga.Population `json:"immigrants"`
+	Stop       bool          `json:"stop"`
+}
+
+type finishRequest struct {
+	Island   int                      `json:"island"`
+	Solution ga.GraphColoringSolution `json:"solution"`
+}
+
+// Coordinator hands out a fixed number of islands and tells every worker to
+// stop once any island has found a proper coloring. Done is closed when all
+// islands have reported their solution.
+type Coordinator struct {
+	Logger *slog.Logger
+
+	dimacs       string
+	nodes        int
+	config       ga.SolverConfig
+	islands      int
+	migrateEvery int
+	migrants     int
+
+	mutex     sync.Mutex
+	joined    int
+	pending   []ga.Population
+	solutions []*ga.GraphColoringSolution
+	finished  int
+	stop      bool
+	done      chan struct{}
+}
+
+// NewCoordinator splits a run of config on g into islands that exchange
+// migrants best chromosomes every migrateEvery generations. A zero seed in
+// config is replaced by one from the clock so that the islands still differ.
+func NewCoordinator(g *graph.Graph, config ga.SolverConfig, islands int, migrateEvery int, migrants int) (*Coordinator, error) {
+	_, err := config.Options()
+	if err != nil {
+		return nil, err
+	}
+	config.Defaults()
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+
+	dimacs := bytes.Buffer{}
+	err = encoding.WriteDIMACS(&dimacs, g)
+	if err != nil {
+		return nil, err
+	}
+	return &Coordinator{
+		Logger:       slog.Default(),
+		dimacs:       dimacs.String(),
+		nodes:        g.NodeCount(),
+		config:       config,
+		islands:      islands,
+		migrateEvery: migrateEvery,
+		migrants:     migrants,
+		pending:      make([]ga.Population, islands),
+		solutions:    make([]*ga.GraphColoringSolution, islands),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+func (coordinator *Coordinator) Done() <-chan struct{} {
+	return coordinator.done
+}
+
+// Best returns the best solution reported so far, or nil if there is none.
+func (coordinator *Coordinator) Best() *ga.GraphColoringSolution {
+	coordinator.mutex.Lock()
+	defer coordinator.mutex.Unlock()
+
+	var best *ga.GraphColoringSolution
+	for _, solution := range coordinator.solutions {
+		if solution != nil && (best == nil || solution.Score < best.Score) {
+			best = solution
+		}
+	}
+	return best
+}
+
+// share returns how many of the remaining islands a worker with caps runs and
+// the population of each: an island per core, as many as fit into half of the
+// advertised memory, and smaller populations if even one does not fit.
+func (coordinator *Coordinator) share(caps Capabilities, remaining int) (int, int) {
+	islands := min(max(caps.Cores, 1), remaining)
+	popSize := coordinator.config.PopSize
+	if caps.Memory == 0 {
+		return islands, popSize
+	}
+
+	// A solver holds its population and as many children, one int per node.
+	chromosomeBytes := uint64(8*coordinator.nodes + 24)
+	budget := caps.Memory / 2
+	islandBytes := 2 * uint64(popSize) * chromosomeBytes
+	islands = min(islands, max(int(budget/islandBytes), 1))
+	if islandBytes > budget {
+		popSize = max(int(budget/(2*chromosomeBytes)), minPopSize)
+	}
+	return islands, popSize
+}
+
+// Handler serves the worker protocol: POST /join, /exchange and /finish.
+func (coordinator *Coordinator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /join", coordinator.handleJoin)
+	mux.HandleFunc("POST /exchange", coordinator.handleExchange)
+	mux.HandleFunc("POST /finish", coordinator.handleFinish)
+	return mux
+}
+
+func (coordinator *Coordinator) handleJoin(w http.ResponseWriter, r *http.Request) {
+	caps := Capabilities{}
+	if !coordinator.readJSON(w, r, &caps) {
+		return
+	}
+
+	coordinator.mutex.Lock()
+	if coordinator.joined == coordinator.islands {
+		coordinator.mutex.Unlock()
+		http.Error(w, "all islands are taken", http.StatusConflict)
+		return
+	}
+	first := coordinator.joined
+	count, popSize := coordinator.share(caps, coordinator.islands-first)
+	coordinator.joined += count
+	coordinator.mutex.Unlock()
+
+	assignment := Assignment{
+		DIMACS:       coordinator.dimacs,
+		MigrateEvery: coordinator.migrateEvery,
+		Migrants:     coordinator.migrants,
+	}
+	for island := first; island < first+count; island++ {
+		config := coordinator.config
+		config.Seed += int64(island)
+		config.PopSize = popSize
+		assignment.Islands = append(assignment.Islands, Island{Index: island, Config: config})
+	}
+	coordinator.Logger.Info("worker joined",
+		"islands", fmt.Sprintf("%d-%d", first, first+count-1),
+		"popsize", popSize,
+		"cores", caps.Cores,
+		"memory", caps.Memory,
+		"gpus", caps.GPUs,
+		"remote", r.RemoteAddr,
+	)
+	coordinator.writeJSON(w, assignment)
+}
+
+func (coordinator *Coordinator) handleExchange(w http.ResponseWriter, r *http.Request) {
+	request := exchangeRequest{}
+	if !coordinator.readJSON(w, r, &request) || !coordinator.knownIsland(w, request.Island) {
+		return
+	}
+
+	coordinator.mutex.Lock()
+	if request.BestScore == 0 && !coordinator.stop {
+		coordinator.stop = true
+		coordinator.Logger.Info("island found a proper coloring, stopping all islands", "island", request.Island, "generation", request.Generation)
+	}
+	coordinator.pending[(request.Island+1)%coordinator.islands] = request.Emigrants
+	response := exchangeResponse{
+		Immigrants: coordinator.pending[request.Island],
+		Stop:       coordinator.stop,
+	}
+	coordinator.pending[request.Island] = nil
+	coordinator.mutex.Unlock()
+
+	coordinator.writeJSON(w, response)
+}
+
+func (coordinator *Coordinator) handleFinish(w http.ResponseWriter, r *http.Request) {
+	request := finishRequest{}
+	if !coordinator.readJSON(w, r, &request) || !coordinator.knownIsland(w, request.Island) {
+		return
+	}
+
+	coordinator.mutex.Lock()
+	defer coordinator.mutex.Unlock()
+	// A repeated report replaces the solution but finishes nothing more.
+	if coordinator.solutions[request.Island] == nil {
+		coordinator.finished++
+		if coordinator.finished == coordinator.islands {
+			defer close(coordinator.done)
+		}
+	}
+	coordinator.solutions[request.Island] = &request.Solution
+	if request.Solution.Score == 0 {
+		coordinator.stop = true
+	}
+	coordinator.Logger.Info("island finished", "island", request.Island, "score", request.Solution.Score, "generations", request.Solution.Metadata.Generations)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (coordinator *Coordinator) readJSON(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (coordinator *Coordinator) knownIsland(w http.ResponseWriter, island int) bool {
+	if island < 0 || island >= coordinator.islands {
+		http.Error(w, "unknown island", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (coordinator *Coordinator) writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		coordinator.Logger.Warn("failed to write response", "err", err)
+	}
+}
@@ -0,0 +1,148 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// RunWorker joins the coordinator at url advertising caps, evolves the islands
+// it is assigned side by side and reports the best solution of each. It
+// returns the best of them, and stops early when the coordinator says so or
+// ctx is done. A nil logger logs to slog.Default().
+func RunWorker(ctx context.Context, url string, caps Capabilities, logger *slog.Logger) (ga.GraphColoringSolution, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	url = strings.TrimSuffix(url, "/")
+
+	assignment := Assignment{}
+	err := post(ctx, url+"/join", caps, &assignment)
+	if err != nil {
+		return ga.GraphColoringSolution{}, err
+	}
+	g, err := encoding.ReadDIMACS(strings.NewReader(assignment.DIMACS))
+	if err != nil {
+		return ga.GraphColoringSolution{}, err
+	}
+	logger.Info("joined coordinator", "url", url, "nodes", g.NodeCount(), "islands", len(assignment.Islands))
+
+	// The islands share one compact copy of the graph rather than each
+	// making its own.
+	compact := graph.Compact(g)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	solutions := make([]ga.GraphColoringSolution, len(assignment.Islands))
+	errs := make([]error, len(assignment.Islands))
+	wait := sync.WaitGroup{}
+	for i, island := range assignment.Islands {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			solutions[i], errs[i] = runIsland(ctx, cancel, url, compact, assignment, island, logger.With("island", island.Index))
+		}()
+	}
+	wait.Wait()
+
+	best := 0
+	for i := range solutions {
+		if solutions[i].Score < solutions[best].Score {
+			best = i
+		}
+	}
+	return solutions[best], errors.Join(errs...)
+}
+
+// runIsland evolves one island and reports its solution. cancel stops every
+// island of the worker.
+func runIsland(ctx context.Context, cancel context.CancelFunc, url string, g graph.Interface, assignment Assignment, island Island, logger *slog.Logger) (ga.GraphColoringSolution, error) {
+	options, err := island.Config.Options()
+	if err != nil {
+		return ga.GraphColoringSolution{}, err
+	}
+	logger.Info("island started", "seed", island.Config.Seed, "popsize", island.Config.PopSize)
+
+	// The final population need not hold the best coloring ever found, so
+	// the worker keeps it and reports it if it is better.
+	var best *ga.NewBest
+	bestScore := -1
+	track := ga.SubscriberFunc(func(event ga.Event) {
+		improvement, ok := event.(ga.NewBest)
+		if ok {
+			improvement.Coloring = append(ga.Chromosome(nil), improvement.Coloring...)
+			best = &improvement
+			bestScore = improvement.Score
+		}
+	})
+	exchange := func(generation int, emigrants ga.Population) ga.Population {
+		response := exchangeResponse{}
+		err := post(ctx, url+"/exchange", exchangeRequest{
+			Island:     island.Index,
+			Generation: generation,
+			BestScore:  bestScore,
+			Emigrants:  emigrants,
+		}, &response)
+		if err != nil {
+			logger.Warn("migration failed", "generation", generation, "err", err)
+			return nil
+		}
+		if response.Stop {
+			cancel()
+		}
+		return response.Immigrants
+	}
+	solver := ga.NewSolver(g, append(
+		options,
+		ga.WithLogger(logger),
+		ga.WithSubscriber(track),
+		ga.WithMigration(ga.Migration{Every: assignment.MigrateEvery, Count: assignment.Migrants, Exchange: exchange}),
+	)...)
+
+	solution := solver.SolveContext(ctx)
+	if best != nil && best.Score < solution.Score {
+		solution.Coloring = best.Coloring
+		solution.Score = best.Score
+	}
+	logger.Info("island finished", "score", solution.Score, "generations", solution.Metadata.Generations)
+
+	// The run context may be cancelled by now, so report with the caller's.
+	err = post(context.WithoutCancel(ctx), url+"/finish", finishRequest{Island: island.Index, Solution: solution}, nil)
+	return solution, err
+}
+
+func post(ctx context.Context, url string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpResponse, err := http.DefaultClient.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode >= 300 {
+		message, _ := io.ReadAll(httpResponse.Body)
+		return fmt.Errorf("%s: %s: %s", url, httpResponse.Status, strings.TrimSpace(string(message)))
+	}
+	if response == nil {
+		return nil
+	}
+	return json.NewDecoder(httpResponse.Body).Decode(response)
+}
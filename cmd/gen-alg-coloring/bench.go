@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/experiment"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+func runBench(args []string) {
+	if len(args) == 0 || args[0] != "dimacs" {
+		fmt.Fprintf(os.Stderr, "Usage: %s bench dimacs [flags] [instance ...]\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("bench dimacs", flag.ExitOnError)
+	dir := flags.String("dir", "dataset/data", "directory holding the DIMACS .col files")
+	suite := flags.String("suite", "quick", "instances to run when none are named: quick, or all that have a best-known result")
+	configFile := flags.String("config", "", "read solver settings from this JSON or YAML file; the color count is set by the bench")
+	seeds := flags.Int("seeds", 1, "seeds to try at every color count before giving up on it")
+	parallelism := flags.Int("parallel", 0, "number of instances to bench at once (defaults to the number of CPUs)")
+	timeLimit := flags.Duration("time-limit", 0, "stop every attempt after this long")
+	reportOut := flags.String("report", "", "write the gap report to this file (Markdown for .md, plain text otherwise)")
+	resultsOut := flags.String("out", "", "write the results as JSON to this file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s bench dimacs [-dir dataset/data] [-suite quick|all] [-config config.json] [instance ...]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args[1:])
+
+	names := flags.Args()
+	if len(names) == 0 {
+		switch *suite {
+		case "quick":
+			names = experiment.QuickSuite
+		case "all":
+			for name := range experiment.BestKnown {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		default:
+			Fatal("unknown suite, expected quick or all", "suite", *suite)
+		}
+	}
+	bench := experiment.Bench{Parallelism: *parallelism, TimeLimit: *timeLimit}
+	for _, name := range names {
+		filename := filepath.Join(*dir, name+".col")
+		if _, err := os.Stat(filename); err != nil {
+			logger.Warn("skipping missing instance", "file", filename)
+			continue
+		}
+		bench.Instances = append(bench.Instances, filename)
+	}
+	if len(bench.Instances) == 0 {
+		Fatal("no instances to bench, run download.sh or pass -dir", "dir", *dir)
+	}
+	if *configFile != "" {
+		loaded, err := encoding.LoadConfig(*configFile)
+		ExpectOk(err)
+		bench.Base = *loaded
+	}
+	for i := 0; i < max(*seeds, 1); i++ {
+		bench.Seeds = append(bench.Seeds, int64(i+1))
+	}
+
+	results := bench.Run(func(result experiment.BenchResult) {
+		if result.Err != "" {
+			logger.Error("bench failed", "instance", result.Instance, "err", result.Err)
+			return
+		}
+		logger.Info("benched", "instance", result.Instance, "colors", result.Colors, "best_known", result.BestKnown, "elapsed", result.Elapsed.Round(time.Millisecond))
+	})
+	if *resultsOut != "" {
+		bytes, err := json.MarshalIndent(results, "", "\t")
+		ExpectOk(err)
+		ExpectOk(os.WriteFile(*resultsOut, bytes, 0600))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, renderGapReport(results, bench.Base, false))
+	w.Flush()
+	if *reportOut != "" {
+		ExpectOk(os.WriteFile(*reportOut, []byte(renderGapReport(results, bench.Base, strings.HasSuffix(*reportOut, ".md"))), 0600))
+	}
+}
+
+// renderGapReport lists, for every instance, the colors the bench reached
+// against the best known count, as a Markdown table or as tab separated
+// columns for a tabwriter.
+func renderGapReport(results []experiment.BenchResult, base ga.SolverConfig, markdown bool) string {
+	sb := strings.Builder{}
+	row := func(cells ...string) {
+		if markdown {
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		} else {
+			sb.WriteString(strings.Join(cells, "\t") + "\n")
+		}
+	}
+	if markdown {
+		sb.WriteString("# DIMACS gap report\n\n")
+	}
+	row("INSTANCE", "NODES", "BEST KNOWN", "GREEDY", "COLORS", "GAP", "ATTEMPTS", "TIME")
+	if markdown {
+		row("---", "---:", "---:", "---:", "---:", "---:", "---:", "---:")
+	}
+
+	matched, known, totalGap := 0, 0, 0
+	for _, result := range results {
+		if result.Err != "" {
+			row(result.Instance, "-", "-", "-", "-", "-", "-", "error: "+result.Err)
+			continue
+		}
+		bestKnown, gap := "?", "?"
+		if result.Gap() >= 0 {
+			known++
+			totalGap += result.Gap()
+			bestKnown, gap = fmt.Sprint(result.BestKnown), fmt.Sprintf("+%d", result.Gap())
+			if result.Gap() == 0 {
+				matched++
+			}
+		}
+		row(
+			result.Instance,
+			fmt.Sprint(result.Nodes),
+			bestKnown,
+			fmt.Sprint(result.Greedy),
+			fmt.Sprint(result.Colors),
+			gap,
+			fmt.Sprint(result.Attempts),
+			result.Elapsed.Round(time.Millisecond).String(),
+		)
+	}
+
+	sb.WriteString("\n")
+	summary := fmt.Sprintf("%d of %d instances at the best known color count", matched, known)
+	if known > 0 {
+		summary += fmt.Sprintf(", mean gap %.2f colors", float64(totalGap)/float64(known))
+	}
+	sb.WriteString(summary + "\n")
+	config := base
+	config.Defaults()
+	sb.WriteString(fmt.Sprintf("solver: %d iterations, popsize %d, %s/%s/%s\n", config.Iterations, config.PopSize, config.Selector, config.Crossover, config.Mutator))
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/packedbread/gen-alg-graph-coloring/cluster"
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+func runCoordinate(args []string) {
+	flags := flag.NewFlagSet("coordinate", flag.ExitOnError)
+	addr := flags.String("addr", ":7070", "address to accept workers on")
+	graphFilename := flags.String("graph", "", "DIMACS graph to color")
+	configFile := flags.String("config", "", "JSON or YAML solver config every island uses")
+	islands := flags.Int("islands", 4, "number of islands to hand out to the workers, one per advertised core")
+	migrateEvery := flags.Int("migrate-every", 100, "generations between migrations")
+	migrants := flags.Int("migrants", 5, "chromosomes every island sends to the next one")
+	outputFilename := flags.String("out", "result.json", "file to save the best solution to")
+	timeout := flags.Duration("timeout", 0, "stop waiting for workers after this long, such as 2h, and save the best solution reported so far (0 waits for every island)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s coordinate -graph g.col [-config config.json] [-islands N]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *graphFilename == "" || *islands < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	g, err := encoding.LoadGraph(*graphFilename)
+	ExpectOk(err)
+	config := ga.DefaultConfig()
+	if *configFile != "" {
+		loaded, err := encoding.LoadConfig(*configFile)
+		ExpectOk(err)
+		config = *loaded
+	}
+	coordinator, err := cluster.NewCoordinator(g, config, *islands, *migrateEvery, *migrants)
+	ExpectOk(err)
+	coordinator.Logger = logger
+
+	logger.Info("waiting for workers", "addr", *addr, "islands", *islands)
+	go func() {
+		ExpectOk(http.ListenAndServe(*addr, coordinator.Handler()))
+	}()
+	// An interrupt or the timeout also ends the wait, for workers that never
+	// report back.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	select {
+	case <-coordinator.Done():
+	case <-ctx.Done():
+		logger.Warn("stopped waiting for workers, saving the best solution so far", "err", ctx.Err())
+	}
+
+	best := coordinator.Best()
+	if best == nil {
+		Fatal("no island reported a solution")
+	}
+	ExpectOk(encoding.SaveSolution(*outputFilename, best))
+	logger.Info("best coloring saved", "score", best.Score, "file", *outputFilename)
+}
+
+func runWorker(args []string) {
+	flags := flag.NewFlagSet("worker", flag.ExitOnError)
+	coordinatorURL := flags.String("coordinator", "", "URL of the coordinator (e.g. http://host:7070)")
+	cores := flags.Int("cores", 0, "cores to advertise, one island each (0 uses every core)")
+	memory := flags.Int("memory-mb", 0, "memory in MiB to advertise (0 uses the available memory)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s worker -coordinator http://host:7070\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *coordinatorURL == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	caps := cluster.LocalCapabilities()
+	if *cores > 0 {
+		caps.Cores = *cores
+	}
+	if *memory > 0 {
+		caps.Memory = uint64(*memory) << 20
+	}
+	solution, err := cluster.RunWorker(context.Background(), *coordinatorURL, caps, logger)
+	ExpectOk(err)
+	logger.Info("worker finished", "score", solution.Score, "generations", solution.Metadata.Generations)
+}
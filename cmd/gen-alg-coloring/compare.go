@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/viz"
+)
+
+func formatEdges(edges [][2]int) string {
+	var parts []string
+	for _, edge := range edges {
+		parts = append(parts, fmt.Sprintf("%d-%d", edge[0], edge[1]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatNodes(nodes []int) string {
+	var parts []string
+	for _, node := range nodes {
+		parts = append(parts, fmt.Sprint(node))
+	}
+	return strings.Join(parts, " ")
+}
+
+func runCompare(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	graphFilename := flags.String("graph", "", "DIMACS graph both colorings belong to")
+	output := flags.String("out", "compare.svg", "side-by-side SVG rendering of both colorings")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s compare --graph g.col a.json b.json\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *graphFilename == "" || flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	g, err := encoding.LoadGraph(*graphFilename)
+	ExpectOk(err)
+	a, err := encoding.LoadSolution(flags.Arg(0))
+	ExpectOk(err)
+	b, err := encoding.LoadSolution(flags.Arg(1))
+	ExpectOk(err)
+	if len(a.Coloring) != g.NodeCount() || len(b.Coloring) != g.NodeCount() {
+		Fatal("colorings and graph differ in size", "a", len(a.Coloring), "b", len(b.Coloring), "graph", g.NodeCount())
+	}
+
+	comparison := graph.CompareColorings(g, a.Coloring, b.Coloring)
+
+	fmt.Printf("a: %s (%d conflicting edges)\n", flags.Arg(0), len(comparison.ConflictsOnlyA)+len(comparison.ConflictsBoth))
+	fmt.Printf("b: %s (%d conflicting edges)\n", flags.Arg(1), len(comparison.ConflictsOnlyB)+len(comparison.ConflictsBoth))
+	fmt.Printf("nodes with different colors: %d\n", len(comparison.RawDifferences))
+	fmt.Printf("nodes with different colors after relabeling a: %d\n", len(comparison.RelabeledDifference))
+	if len(comparison.RelabeledDifference) > 0 {
+		fmt.Printf("  %s\n", formatNodes(comparison.RelabeledDifference))
+	}
+	fmt.Printf("conflicts only in a: %d\n", len(comparison.ConflictsOnlyA))
+	if len(comparison.ConflictsOnlyA) > 0 {
+		fmt.Printf("  %s\n", formatEdges(comparison.ConflictsOnlyA))
+	}
+	fmt.Printf("conflicts only in b: %d\n", len(comparison.ConflictsOnlyB))
+	if len(comparison.ConflictsOnlyB) > 0 {
+		fmt.Printf("  %s\n", formatEdges(comparison.ConflictsOnlyB))
+	}
+	fmt.Printf("conflicts in both: %d\n", len(comparison.ConflictsBoth))
+	if len(comparison.ConflictsBoth) > 0 {
+		fmt.Printf("  %s\n", formatEdges(comparison.ConflictsBoth))
+	}
+
+	ExpectOk(viz.SaveComparisonSVG(*output, g, a.Coloring, b.Coloring, flags.Arg(0), flags.Arg(1), comparison.RelabeledDifference))
+	fmt.Printf("rendering saved in file %s\n", *output)
+}
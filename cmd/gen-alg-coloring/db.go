@@ -0,0 +1,222 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+const runsSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at   TEXT NOT NULL,
+	instance     TEXT NOT NULL,
+	graph_hash   TEXT NOT NULL,
+	nodes        INTEGER NOT NULL,
+	edges        INTEGER NOT NULL,
+	num_colors   INTEGER NOT NULL,
+	iterations   INTEGER NOT NULL,
+	pop_size     INTEGER NOT NULL,
+	seed         INTEGER NOT NULL,
+	best_score   INTEGER NOT NULL,
+	colors_used  INTEGER NOT NULL,
+	generations  INTEGER NOT NULL,
+	evaluations  INTEGER NOT NULL,
+	wall_time    REAL NOT NULL,
+	git_revision TEXT NOT NULL
+)`
+
+type RunRecord struct {
+	ID            int64
+	StartedAt     time.Time
+	Instance      string
+	GraphHash     string
+	Nodes         int
+	Edges         int
+	NumColors     int
+	NumIterations int
+	PopSize       int
+	Seed          int64
+	BestScore     int
+	ColorsUsed    int
+	Generations   int
+	Evaluations   int
+	WallTime      time.Duration
+	GitRevision   string
+}
+
+func NewRunRecord(g *graph.Graph, solution ga.GraphColoringSolution) RunRecord {
+	edgeCount := 0
+	for _, neighbours := range g.AdjecencyList {
+		edgeCount += len(neighbours)
+	}
+
+	metadata := solution.Metadata
+	return RunRecord{
+		StartedAt:     metadata.StartedAt,
+		Instance:      metadata.Instance,
+		GraphHash:     metadata.GraphHash,
+		Nodes:         g.NodeCount(),
+		Edges:         edgeCount,
+		NumColors:     metadata.Config.Colors,
+		NumIterations: metadata.Config.Iterations,
+		PopSize:       metadata.Config.PopSize,
+		Seed:          metadata.Config.Seed,
+		BestScore:     solution.Score,
+		ColorsUsed:    g.ColorsUsed(),
+		Generations:   metadata.Generations,
+		Evaluations:   metadata.Evaluations,
+		WallTime:      metadata.FinishedAt.Sub(metadata.StartedAt),
+		GitRevision:   metadata.GitRevision,
+	}
+}
+
+type RunDatabase struct {
+	db *sql.DB
+}
+
+func OpenRunDatabase(filename string) (*RunDatabase, error) {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(runsSchema)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &RunDatabase{db: db}, nil
+}
+
+func (database *RunDatabase) Close() error {
+	return database.db.Close()
+}
+
+func (database *RunDatabase) Insert(record RunRecord) (int64, error) {
+	result, err := database.db.Exec(
+		`INSERT INTO runs (
+			started_at, instance, graph_hash, nodes, edges, num_colors, iterations, pop_size,
+			seed, best_score, colors_used, generations, evaluations, wall_time, git_revision
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.StartedAt.UTC().Format(time.RFC3339),
+		record.Instance,
+		record.GraphHash,
+		record.Nodes,
+		record.Edges,
+		record.NumColors,
+		record.NumIterations,
+		record.PopSize,
+		record.Seed,
+		record.BestScore,
+		record.ColorsUsed,
+		record.Generations,
+		record.Evaluations,
+		record.WallTime.Seconds(),
+		record.GitRevision,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (database *RunDatabase) Runs(instance string, limit int) ([]RunRecord, error) {
+	rows, err := database.db.Query(
+		`SELECT
+			id, started_at, instance, graph_hash, nodes, edges, num_colors, iterations, pop_size,
+			seed, best_score, colors_used, generations, evaluations, wall_time, git_revision
+		FROM runs
+		WHERE ? = '' OR instance = ?
+		ORDER BY id DESC
+		LIMIT ?`,
+		instance, instance, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []RunRecord
+	for rows.Next() {
+		record := RunRecord{}
+		var startedAt string
+		var wallTime float64
+		err = rows.Scan(
+			&record.ID,
+			&startedAt,
+			&record.Instance,
+			&record.GraphHash,
+			&record.Nodes,
+			&record.Edges,
+			&record.NumColors,
+			&record.NumIterations,
+			&record.PopSize,
+			&record.Seed,
+			&record.BestScore,
+			&record.ColorsUsed,
+			&record.Generations,
+			&record.Evaluations,
+			&wallTime,
+			&record.GitRevision,
+		)
+		if err != nil {
+			return nil, err
+		}
+		record.StartedAt, err = time.Parse(time.RFC3339, startedAt)
+		if err != nil {
+			return nil, err
+		}
+		record.WallTime = time.Duration(wallTime * float64(time.Second))
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func runHistory(args []string) {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	dbFilename := flags.String("db", "runs.sqlite", "results database to query")
+	instance := flags.String("instance", "", "only show runs on this instance")
+	limit := flags.Int("limit", 20, "maximum number of runs to show")
+	flags.Parse(args)
+
+	database, err := OpenRunDatabase(*dbFilename)
+	ExpectOk(err)
+	defer database.Close()
+
+	records, err := database.Runs(*instance, *limit)
+	ExpectOk(err)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTARTED\tINSTANCE\tCOLORS\tPOP\tSEED\tSCORE\tUSED\tGENERATIONS\tTIME\tREVISION")
+	for _, record := range records {
+		revision := record.GitRevision
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			record.ID,
+			record.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			record.Instance,
+			record.NumColors,
+			record.PopSize,
+			record.Seed,
+			record.BestScore,
+			record.ColorsUsed,
+			record.Generations,
+			record.WallTime.Round(time.Millisecond),
+			revision,
+		)
+	}
+	w.Flush()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/experiment"
+)
+
+func runExperiment(args []string) {
+	flags := flag.NewFlagSet("experiment", flag.ExitOnError)
+	parallelism := flags.Int("parallel", 0, "number of runs to execute at once (overrides the manifest)")
+	resultsOut := flags.String("out", "", "write every individual run result as JSON to this file")
+	runsOut := flags.String("runs-out", "", "write a row per run as CSV, or as Parquet for .parquet, to this file")
+	generationsOut := flags.String("generations-out", "", "write a row per generation of every run as CSV, or as Parquet for .parquet, to this file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s experiment [-parallel N] [-out results.json] [-runs-out runs.parquet] manifest.json\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	manifest, err := experiment.LoadManifest(flags.Arg(0))
+	ExpectOk(err)
+	if *parallelism > 0 {
+		manifest.Parallelism = *parallelism
+	}
+
+	if manifest.Results != "" {
+		remaining, err := manifest.Remaining()
+		ExpectOk(err)
+		total := len(manifest.Instances) * len(manifest.Configurations) * len(manifest.Seeds)
+		logger.Info("running experiment", "runs", total, "remaining", remaining, "results", manifest.Results)
+	}
+	manifest.KeepHistory = *generationsOut != ""
+	results, err := manifest.Run()
+	ExpectOk(err)
+	if *resultsOut != "" {
+		bytes, err := json.MarshalIndent(results, "", "\t")
+		ExpectOk(err)
+		ExpectOk(os.WriteFile(*resultsOut, bytes, 0600))
+	}
+	saveTables(results, *runsOut, *generationsOut)
+
+	summaries := experiment.Summarize(results)
+	if manifest.Summary != "" {
+		ExpectOk(experiment.SaveSummaries(manifest.Summary, summaries))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE\tCONFIGURATION\tRUNS\tSOLVED\tBEST\tMEAN\tTIME")
+	for _, summary := range summaries {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%d\t%d\t%d\t%.2f\t%s\n",
+			summary.Instance,
+			summary.Configuration,
+			summary.Runs,
+			summary.Solved,
+			summary.BestScore,
+			summary.MeanScore,
+			summary.MeanElapsed.Round(time.Millisecond),
+		)
+	}
+	w.Flush()
+}
+
+// saveTables writes a row per run of results to runsOut and a row per
+// generation to generationsOut, skipping either if it is empty.
+func saveTables(results []experiment.Result, runsOut string, generationsOut string) {
+	if runsOut != "" {
+		ExpectOk(encoding.SaveTable(runsOut, experiment.RunsTable(results)))
+	}
+	if generationsOut != "" {
+		table := experiment.GenerationsTable(results)
+		if len(table.Rows) == 0 {
+			logger.Warn("no generations to write, the runs were all read back from the results file", "file", generationsOut)
+		}
+		ExpectOk(encoding.SaveTable(generationsOut, table))
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/viz"
+)
+
+// graphFormats lists the extensions saveGraphFile picks a format by.
+const graphFormats = ".col (DIMACS), .json, .bin, .graphml, .gexf, .dot or .tex (TikZ)"
+
+// loadGraphFile reads a graph as JSON or binary by extension, and as DIMACS
+// otherwise.
+func loadGraphFile(filename string) (*graph.Graph, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return encoding.LoadGraphJSON(filename)
+	case ".bin":
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return encoding.ReadGraph(file)
+	}
+	return encoding.LoadGraph(filename)
+}
+
+// saveGraphFile writes g in the format the extension of filename names, one
+// of graphFormats.
+func saveGraphFile(filename string, g *graph.Graph) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".col", ".dimacs":
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return encoding.WriteDIMACS(file, g)
+	case ".json":
+		return encoding.SaveGraph(filename, g)
+	case ".bin":
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return encoding.WriteGraph(file, g)
+	case ".graphml":
+		return encoding.SaveGraphML(filename, g)
+	case ".gexf":
+		return encoding.SaveGEXF(filename, g)
+	case ".dot", ".gv":
+		return viz.SaveGraphViz(filename, g, viz.GraphVizOptions{})
+	case ".tex":
+		return viz.SaveTikZ(filename, g)
+	}
+	return fmt.Errorf("%s: unknown graph format, expected %s", filename, graphFormats)
+}
+
+func runGenerate(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	nodes := flags.Int("nodes", 100, "number of nodes")
+	density := flags.Float64("density", 0, "probability of every edge (0 uses -degree)")
+	degree := flags.Float64("degree", 3, "expected degree of every node, when -density is 0")
+	seed := flags.Int64("seed", 0, "random seed to reproduce the graph (0 picks one at random)")
+	output := flags.String("out", "graph.col", "file to save the graph to: "+graphFormats)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s generate [-nodes 100] [-density p | -degree 3] [-seed N] [-out graph.col]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 || *nodes < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	probability := *density
+	if probability == 0 && *nodes > 1 {
+		probability = *degree / float64(*nodes-1)
+	}
+	if probability < 0 || probability > 1 {
+		Fatal("the edge probability must be between 0 and 1", "probability", probability)
+	}
+	if *seed == 0 {
+		*seed = rand.Int63()
+	}
+
+	g := graph.NewRandomGraph(rand.New(rand.NewSource(*seed)), *nodes, float32(probability))
+	ExpectOk(saveGraphFile(*output, &g))
+	logger.Info("graph saved", "nodes", g.NodeCount(), "seed", *seed, "file", *output)
+}
+
+func runConvert(args []string) {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	coloringFilename := flags.String("coloring", "", "solution whose coloring to store in, or draw on, the converted graph")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s convert [-coloring result.json] in.col out.graphml\n", os.Args[0])
+		fmt.Fprintf(flags.Output(), "Graphs are read as .json, .bin or DIMACS and written as %s.\n", graphFormats)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	g, err := loadGraphFile(flags.Arg(0))
+	ExpectOk(err)
+	if *coloringFilename != "" {
+		solution, err := encoding.LoadSolution(*coloringFilename)
+		ExpectOk(err)
+		if len(solution.Edges) > 0 || len(solution.Coloring) != g.NodeCount() {
+			Fatal("the coloring does not color the nodes of the graph", "coloring", len(solution.Coloring), "graph", g.NodeCount())
+		}
+		g.Colors = solution.Coloring
+	}
+	ExpectOk(saveGraphFile(flags.Arg(1), g))
+	logger.Info("graph converted", "nodes", g.NodeCount(), "file", flags.Arg(1))
+}
+
+func runVerify(args []string) {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	graphFilename := flags.String("graph", "", "graph the coloring belongs to")
+	colors := flags.Int("colors", 0, "also fail if the coloring uses colors outside 0 to N-1 (0 uses the colors of the run)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s verify -graph g.col [-colors N] result.json\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *graphFilename == "" || flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	g, err := loadGraphFile(*graphFilename)
+	ExpectOk(err)
+	solution, err := encoding.LoadSolution(flags.Arg(0))
+	ExpectOk(err)
+
+	// Edge and total colorings are verified as colorings of the line or
+	// total graph, which their edges tell apart.
+	colored := g
+	if len(solution.Edges) > 0 {
+		colored, _ = graph.LineGraph(g)
+		if len(solution.Coloring) != len(solution.Edges) {
+			colored, _ = graph.TotalGraph(g)
+		}
+	}
+	if len(solution.Coloring) != colored.NodeCount() {
+		Fatal("coloring and graph differ in size", "coloring", len(solution.Coloring), "graph", colored.NodeCount())
+	}
+	if solution.Metadata.GraphHash != "" && solution.Metadata.GraphHash != graph.Hash(colored) {
+		logger.Warn("the coloring was found for a different graph", "hash", solution.Metadata.GraphHash, "graph_hash", graph.Hash(colored))
+	}
+
+	limit := cmp.Or(*colors, solution.Metadata.Config.Colors)
+	outOfRange := 0
+	for _, color := range solution.Coloring {
+		if color < 0 || (limit > 0 && color >= limit) {
+			outOfRange++
+		}
+	}
+	var conflicts [][2]int
+	for i, j := range graph.AllEdges(colored) {
+		if solution.Coloring[i] == solution.Coloring[j] {
+			conflicts = append(conflicts, [2]int{i, j})
+		}
+	}
+	colored.Colors = solution.Coloring
+
+	fmt.Printf("nodes: %d\n", colored.NodeCount())
+	fmt.Printf("colors used: %d\n", colored.ColorsUsed())
+	if limit > 0 {
+		fmt.Printf("colors outside 0 to %d: %d\n", limit-1, outOfRange)
+	}
+	fmt.Printf("conflicting edges: %d\n", len(conflicts))
+	if len(conflicts) > 0 {
+		fmt.Printf("  %s\n", formatEdges(conflicts))
+	}
+	// A bandwidth coloring is only valid if no edge falls short of its weight.
+	shortfall := 0
+	if solution.Metadata.Config.Fitness == "bandwidth" {
+		shortfall = ga.BandwidthFitness{}.Evaluate(colored, solution.Coloring)
+		fmt.Printf("bandwidth shortfall: %d\n", shortfall)
+	}
+	if solution.Metadata.Config.Fitness == "sum" {
+		fmt.Printf("chromatic sum: %d\n", ga.ChromaticSum(solution.Coloring))
+	}
+
+	if len(conflicts) > 0 || outOfRange > 0 || shortfall > 0 {
+		fmt.Println("invalid coloring")
+		os.Exit(1)
+	}
+	fmt.Println("valid coloring")
+}
+
+func runStats(args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s stats g.col\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	g, err := loadGraphFile(flags.Arg(0))
+	ExpectOk(err)
+
+	nodeCount := g.NodeCount()
+	edgeCount, loops := 0, 0
+	for i, j := range graph.AllEdges(g) {
+		edgeCount++
+		if i == j {
+			loops++
+		}
+	}
+	degrees := g.Degrees()
+	minDegree, totalDegree := 0, 0
+	for i, degree := range degrees {
+		if i == 0 || degree < minDegree {
+			minDegree = degree
+		}
+		totalDegree += degree
+	}
+	density := 0.0
+	if nodeCount > 1 {
+		density = 2 * float64(edgeCount) / float64(nodeCount) / float64(nodeCount-1)
+	}
+	bounds := graph.ColorBounds(g)
+	greedy := 0
+	for _, color := range graph.Greedy(g) {
+		greedy = max(greedy, color+1)
+	}
+
+	fmt.Printf("nodes: %d\n", nodeCount)
+	fmt.Printf("edges: %d\n", edgeCount)
+	if loops > 0 {
+		fmt.Printf("self-loops: %d\n", loops)
+	}
+	fmt.Printf("density: %.4f\n", density)
+	if nodeCount > 0 {
+		fmt.Printf("degree: min %d, mean %.2f, max %d\n", minDegree, float64(totalDegree)/float64(nodeCount), g.MaxDegree())
+	}
+	fmt.Printf("degeneracy: %d\n", bounds.Degeneracy)
+	fmt.Printf("greedy clique: %d\n", bounds.Lower)
+	fmt.Printf("greedy colors: %d\n", greedy)
+	fmt.Printf("DSATUR colors: %d\n", bounds.DSatur)
+	fmt.Printf("chromatic number: between %d and %d\n", bounds.Lower, bounds.Upper)
+	if g.Weights != nil {
+		fmt.Printf("max edge weight: %d\n", graph.MaxWeight(g))
+	}
+	fmt.Printf("hash: %s\n", graph.Hash(g))
+}
@@ -0,0 +1,662 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/packedbread/gen-alg-graph-coloring/cluster"
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+	"github.com/packedbread/gen-alg-graph-coloring/viz"
+)
+
+var logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+func Fatal(msg string, args ...any) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+func ExpectOk(err error) {
+	if err != nil {
+		Fatal("unexpected fatal error", "err", err)
+	}
+}
+
+func NewLogger(format string, level string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	err := logLevel.UnmarshalText([]byte(level))
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: logLevel}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, options)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q, expected text or json", format)
+}
+
+func SaveResolvedConfig(filename string, parameters map[string]interface{}) error {
+	config := map[string]interface{}{}
+	flag.VisitAll(func(f *flag.Flag) {
+		config[f.Name] = f.Value.String()
+	})
+	for name, value := range parameters {
+		config[name] = value
+	}
+
+	bytes, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, bytes, 0600)
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "compare":
+			runCompare(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		case "experiment":
+			runExperiment(os.Args[2:])
+			return
+		case "tune":
+			runTune(os.Args[2:])
+			return
+		case "bench":
+			runBench(os.Args[2:])
+			return
+		case "selftest":
+			runSelftest(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "coordinate":
+			runCoordinate(os.Args[2:])
+			return
+		case "worker":
+			runWorker(os.Args[2:])
+			return
+		case "generate":
+			runGenerate(os.Args[2:])
+			return
+		case "convert":
+			runConvert(os.Args[2:])
+			return
+		case "verify":
+			runVerify(os.Args[2:])
+			return
+		case "stats":
+			runStats(os.Args[2:])
+			return
+		case "solve":
+			// solve is the default command, named for symmetry with the others.
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+	}
+
+	inputFile := flag.String("input", "dataset/data/queen7_7.col", "DIMACS graph to color, a path or an s3:// or gs:// URI")
+	indexBase := flag.Int("index-base", 1, "number of the first vertex in the DIMACS file, 1 as the format has it or 0")
+	memoryLimit := flag.Int("memory-mb", 0, "refuse DIMACS files declaring a graph estimated to need more than this many MiB (0 uses the available memory, -1 never refuses)")
+	strictInput := flag.Bool("strict-dimacs", false, "reject a DIMACS file that strays from the format, or has vertex IDs beyond its node count, instead of repairing it")
+	outputFile := flag.String("out", "result.json", "file to save the best solution to, a path or an s3:// or gs:// URI")
+	flag.StringVar(outputFile, "output", "result.json", "same as -out")
+	vizOut := flag.String("viz", "solution-viz.dot", "file to save the graphviz drawing of the best solution to")
+	iterationsFlag := flag.Int("iterations", 0, "number of generations to run (defaults to 100000 without -config)")
+	maxTime := flag.Duration("max-time", 0, "stop after this long, e.g. 5m, and keep the best coloring found so far (0 runs all generations)")
+	targetScore := flag.Int("target-score", 0, "stop once the best score is this low (0 waits for a proper coloring)")
+	popSizeFlag := flag.Int("popsize", 0, "population size (defaults to 200 without -config)")
+	configFile := flag.String("config", "", "read solver settings from this JSON or YAML file, chosen by extension; solver flags given override it")
+	colorsFlag := flag.Int("colors", 0, "number of colors to color with (0 picks one from greedy upper and clique lower bounds)")
+	minimizeColors := flag.Bool("minimize-colors", false, "search for the fewest colors with a proper coloring within the iteration budget instead of coloring with -colors")
+	minimizeFromRandom := flag.Bool("minimize-from-random", false, "with -minimize-colors, start every color count from a random population instead of from the previous coloring")
+	precoloringFile := flag.String("precoloring", "", "keep the nodes colored in this JSON array of colors, or solution, at their colors and color only the rest (null or -1)")
+	preferencesFile := flag.String("preferences", "", "bias the coloring towards the colors this JSON file prefers for some vertices, a list of {\"vertex\", \"color\", \"weight\"} objects")
+	conflictWeight := flag.Int("conflict-weight", 0, "preference weight one conflict costs with -preferences (0 makes it cost more than all preferences together)")
+	problem := flag.String("problem", "coloring", "problem to solve: coloring; edge-coloring, which colors the edges by coloring the line graph; total-coloring, which colors the vertices and edges by coloring the total graph; or bandwidth, where the colors of adjacent vertices differ by at least the edge weight, the fourth field of DIMACS edge lines, which defaults -fitness to bandwidth")
+	objective := flag.String("objective", "conflicts", "what to minimize: conflicts with -colors colors, or sum, the sum of the colors as in minimum sum coloring, which defaults -fitness to sum and -mutator to lowest")
+	seedFlag := flag.Int64("seed", 0, "random seed to reproduce a run (0 picks one at random)")
+	repeats := flag.Int("repeats", 1, "run N times with consecutive seeds from -seed, in parallel, and report statistics over the runs")
+	islands := flag.Int("islands", 1, "evolve N populations in parallel with consecutive seeds from -seed, migrating chromosomes around a ring of them")
+	migrateEvery := flag.Int("migrate-every", 100, "with -islands, generations between migrations")
+	migrants := flag.Int("migrants", 5, "with -islands, chromosomes every island sends to the next one")
+	statsOut := flag.String("stats-out", "", "write per-generation statistics as CSV, or as Parquet for .parquet, to this file")
+	historyOut := flag.String("history-out", "", "write the convergence history of the run as JSON to this file")
+	chartOut := flag.String("chart-out", "", "draw the convergence chart of the run as SVG to this file")
+	progressOut := flag.String("progress", "", "stream JSON Lines progress to stdout (-) or a Unix socket (unix:/path/to.sock)")
+	progressEvery := flag.Int("progress-every", 100, "generations between progress reports")
+	vizLayout := flag.String("viz-layout", "", "graphviz layout engine for the solution visualization (dot, neato, sfdp, ...)")
+	vizNodeShape := flag.String("viz-node-shape", "", "graphviz node shape")
+	vizNodeSize := flag.Float64("viz-node-size", 0, "fixed graphviz node size in inches")
+	vizPenWidth := flag.Float64("viz-penwidth", 0, "graphviz edge pen width")
+	vizCluster := flag.Bool("viz-cluster", false, "group nodes into one graphviz cluster per color class")
+	animateDir := flag.String("animate-dir", "", "save the best coloring as numbered graphviz frames into this directory")
+	animateEvery := flag.Int("animate-every", 100, "generations between animation frames")
+	tui := flag.Bool("tui", false, "show a live terminal dashboard instead of log lines")
+	plain := flag.Bool("plain", false, "log progress lines even when attached to a terminal")
+	graphMLOut := flag.String("graphml", "", "export the colored graph as GraphML to this file")
+	gexfOut := flag.String("gexf", "", "export the colored graph as GEXF to this file")
+	tikzOut := flag.String("tikz", "", "export the colored graph as a TikZ picture to this file")
+	paletteFile := flag.String("palette-file", "", "JSON list of graphviz color names to use when there are more than 8 colors")
+	reportOut := flag.String("report", "", "write a run summary to this file (Markdown for .md, plain text otherwise)")
+	mutationRate := flag.Float64("mutation-rate", 0, "probability of recoloring each gene of a child (0 recolors one gene on average)")
+	crossoverRate := flag.Float64("crossover-rate", 0, "share of children bred by crossover rather than cloned from a parent (0 recombines all)")
+	adaptMutation := flag.Bool("adapt-mutation", false, "adapt the mutation rate during the run by -mutation-policy")
+	mutationPolicy := flag.String("mutation-policy", "", "how -adapt-mutation adapts the mutation rate: "+strings.Join(ga.MutationPolicies, ", ")+" (defaults to success, the 1/5 success rule)")
+	elitism := flag.Int("elitism", 0, "carry the N best chromosomes of every generation into the next one unchanged")
+	truncation := flag.String("truncation", "", "how to pick the children that survive a generation, which only changes the speed: "+strings.Join(ga.Truncations, ", ")+" (defaults to select)")
+	steadyState := flag.Bool("steady-state", false, "replace the worst chromosome with every child as it is bred rather than the whole population every generation")
+	crowding := flag.Int("crowding", 0, "replace by deterministic crowding: every child takes the place of the closest of its parents and N-1 random chromosomes if no worse (0 disables it)")
+	fitnessCache := flag.Bool("fitness-cache", false, "skip scoring chromosomes already scored in the current or the previous generation")
+	deduplicate := flag.Bool("deduplicate", false, "replace the chromosomes that repeat another in the population with random ones every generation")
+	repair := flag.Bool("repair", false, "greedily recolor the conflicting vertices of every child bred by crossover before mutating it")
+	tabuIterations := flag.Int("tabu-iterations", 0, "improve every child with up to N moves of TabuCol tabu search on its conflicting vertices (0 runs a pure GA)")
+	stagnationLimit := flag.Int("stagnation", 0, "act on the population once the best score has not improved for N generations (0 never does)")
+	stagnationAction := flag.String("stagnation-action", "", "what to do on stagnation, keeping the -elitism best: "+strings.Join(ga.StagnationActions, ", ")+" (defaults to immigrants)")
+	stagnationShare := flag.Float64("stagnation-share", 0, "share of the population, worst first, that immigrants and burst replace or mutate (0 means half)")
+	adaptCrossover := flag.Bool("adapt-crossover", false, "adapt the crossover rate during the run to how well crossed children survive")
+	checkpointEvery := flag.Int("checkpoint-every", 0, "save a checkpoint of the solver state every N generations")
+	checkpointFile := flag.String("checkpoint-file", "checkpoint.bin", "file or s3:// or gs:// URI to save checkpoints to (JSON if it ends in .json, binary otherwise)")
+	resume := flag.String("resume", "", "continue the run saved in this checkpoint file")
+	dbFilename := flag.String("db", "", "record the run in this SQLite results database")
+	outDir := flag.String("out-dir", "", "write all outputs into a new timestamped per-run directory under this directory")
+	traceFile := flag.String("trace", "", "record how every child is bred to this gzip-compressed trace file")
+	serve := flag.String("serve", "", "serve a live web dashboard on this address (e.g. :8080)")
+	logFormat := flag.String("log-format", "text", "log line format: text or json")
+	logLevel := flag.String("log-level", "info", "minimum level of log lines: debug, info, warn or error")
+	remoteURL := flag.String("remote", "", "submit the run to the job server at this URL (e.g. http://solver:8080) and only save the solution")
+	apiKey := flag.String("api-key", os.Getenv("GEN_ALG_API_KEY"), "API key to send with -remote (defaults to $GEN_ALG_API_KEY)")
+	otelEndpoint := flag.String("otel-endpoint", "", "export OpenTelemetry spans over OTLP/gRPC to this host:port (env reads OTEL_EXPORTER_OTLP_ENDPOINT)")
+	operators := ga.Operators()
+	var operatorNames ga.OperatorNames
+	flag.StringVar(&operatorNames.Selector, "selector", "", "parent selection operator: "+strings.Join(operators["selector"], ", "))
+	flag.StringVar(&operatorNames.Crossover, "crossover", "", "crossover operator: "+strings.Join(operators["crossover"], ", "))
+	flag.StringVar(&operatorNames.Mutator, "mutator", "", "mutation operator: "+strings.Join(operators["mutator"], ", "))
+	flag.StringVar(&operatorNames.Fitness, "fitness", "", "fitness function: "+strings.Join(operators["fitness"], ", "))
+	flag.Parse()
+
+	configuredLogger, err := NewLogger(*logFormat, *logLevel)
+	ExpectOk(err)
+	logger = configuredLogger
+
+	numIterations := 100000
+	popSize := 200
+
+	config := ga.SolverConfig{Iterations: numIterations, PopSize: popSize}
+	if *configFile != "" {
+		loaded, err := encoding.LoadConfig(*configFile)
+		ExpectOk(err)
+		config = *loaded
+	}
+	if *colorsFlag != 0 {
+		config.Colors = *colorsFlag
+	}
+	if *iterationsFlag != 0 {
+		config.Iterations = *iterationsFlag
+	}
+	if *popSizeFlag != 0 {
+		config.PopSize = *popSizeFlag
+	}
+	if *maxTime != 0 {
+		config.MaxTime = maxTime.String()
+	}
+	if *targetScore != 0 {
+		config.TargetScore = *targetScore
+	}
+	if *seedFlag != 0 {
+		config.Seed = *seedFlag
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	if *checkpointEvery > 0 {
+		config.CheckpointEvery = *checkpointEvery
+	}
+	if *mutationRate != 0 {
+		config.MutationRate = *mutationRate
+	}
+	if *crossoverRate != 0 {
+		config.CrossoverRate = *crossoverRate
+	}
+	if *elitism != 0 {
+		config.Elitism = *elitism
+	}
+	if *truncation != "" {
+		config.Truncation = *truncation
+	}
+	if *steadyState {
+		config.SteadyState = true
+	}
+	if *crowding != 0 {
+		config.Crowding = *crowding
+	}
+	if *fitnessCache {
+		config.FitnessCache = true
+	}
+	if *deduplicate {
+		config.Deduplicate = true
+	}
+	if *repair {
+		config.Repair = true
+	}
+	if *tabuIterations != 0 {
+		config.TabuIterations = *tabuIterations
+	}
+	if *stagnationLimit != 0 {
+		config.StagnationLimit = *stagnationLimit
+	}
+	if *stagnationAction != "" {
+		config.StagnationAction = *stagnationAction
+	}
+	if *stagnationShare != 0 {
+		config.StagnationShare = *stagnationShare
+	}
+	config.AdaptMutation = config.AdaptMutation || *adaptMutation
+	if *mutationPolicy != "" {
+		config.MutationPolicy = *mutationPolicy
+	}
+	config.AdaptCrossover = config.AdaptCrossover || *adaptCrossover
+	for _, name := range []struct{ flag, config *string }{
+		{&operatorNames.Selector, &config.Selector},
+		{&operatorNames.Crossover, &config.Crossover},
+		{&operatorNames.Mutator, &config.Mutator},
+		{&operatorNames.Fitness, &config.Fitness},
+	} {
+		if *name.flag != "" {
+			*name.config = *name.flag
+		}
+	}
+	switch *problem {
+	case "coloring":
+	case "bandwidth":
+		if *objective != "conflicts" || *minimizeColors {
+			Fatal("-problem bandwidth cannot be combined with -objective sum or -minimize-colors")
+		}
+		config.Fitness = cmp.Or(config.Fitness, "bandwidth")
+	case "edge-coloring", "total-coloring":
+		if *remoteURL != "" || *repeats > 1 || *graphMLOut != "" || *gexfOut != "" || *tikzOut != "" || *animateDir != "" {
+			Fatal("-problem " + *problem + " cannot be combined with -remote, -repeats, -graphml, -gexf, -tikz or -animate-dir")
+		}
+	default:
+		Fatal("unknown problem, expected coloring, edge-coloring, total-coloring or bandwidth", "problem", *problem)
+	}
+	switch *objective {
+	case "conflicts":
+	case "sum":
+		config.Fitness = cmp.Or(config.Fitness, "sum")
+		config.Mutator = cmp.Or(config.Mutator, "lowest")
+	default:
+		Fatal("unknown objective, expected conflicts or sum", "objective", *objective)
+	}
+	config.Defaults()
+	options, err := config.Options()
+	ExpectOk(err)
+
+	if *paletteFile != "" {
+		ExpectOk(viz.LoadColorList(*paletteFile))
+	}
+
+	if *otelEndpoint != "" {
+		shutdown, err := SetupTracing(*otelEndpoint)
+		ExpectOk(err)
+		defer shutdown()
+	}
+	ctx, span := otel.Tracer(tracerName).Start(context.Background(), "run")
+	defer span.End()
+
+	if *minimizeColors && (*remoteURL != "" || *repeats > 1 || *resume != "" || *precoloringFile != "" || *preferencesFile != "") {
+		Fatal("-minimize-colors cannot be combined with -remote, -repeats, -resume, -precoloring or -preferences")
+	}
+	if *islands > 1 && (*minimizeColors || *remoteURL != "" || *repeats > 1 || *resume != "" || *traceFile != "" || config.CheckpointEvery > 0) {
+		Fatal("-islands cannot be combined with -minimize-colors, -remote, -repeats, -resume, -trace or checkpoints")
+	}
+	if (*precoloringFile != "" || *preferencesFile != "") && (*remoteURL != "" || *repeats > 1) {
+		Fatal("-precoloring and -preferences cannot be combined with -remote or -repeats")
+	}
+	// Traces do not record the fitness, and replays score children by
+	// conflicts.
+	if *traceFile != "" && (config.Fitness != "conflicts" || *minimizeColors || *precoloringFile != "" || *preferencesFile != "") {
+		Fatal("-trace only records runs scored by conflicts, so it cannot be combined with -objective sum, -problem bandwidth, another -fitness, -minimize-colors, -precoloring or -preferences")
+	}
+	// Tabu search and repair minimize conflicts alone, which may miss more
+	// preferences.
+	if *preferencesFile != "" && (config.TabuIterations > 0 || config.Repair) {
+		Fatal("-preferences cannot be combined with -tabu-iterations or -repair")
+	}
+	if *remoteURL != "" {
+		var progress ga.Subscriber = &remoteLog{}
+		if !*plain && IsTerminal(os.Stderr) {
+			progress = NewProgressBar(os.Stderr, config.Iterations)
+		}
+		run := RemoteRun{
+			URL:      *remoteURL,
+			APIKey:   *apiKey,
+			Name:     strings.TrimSuffix(filepath.Base(*inputFile), filepath.Ext(*inputFile)),
+			Input:    *inputFile,
+			Output:   *outputFile,
+			Config:   config,
+			Progress: progress,
+		}
+		solution, err := run.Run(ctx)
+		ExpectOk(err)
+		logger.Info("best coloring saved", "score", solution.Score, "file", *outputFile)
+		return
+	}
+
+	if *repeats > 1 {
+		runRepeats(*inputFile, *outputFile, config, *repeats)
+		return
+	}
+
+	inputFilename := *inputFile
+	_, loadSpan := otel.Tracer(tracerName).Start(ctx, "load graph", trace.WithAttributes(attribute.String("file", inputFilename)))
+	if *indexBase != 0 && *indexBase != 1 {
+		Fatal("the index base must be 0 or 1", "index_base", *indexBase)
+	}
+	dimacsOptions := encoding.DIMACSOptions{Strict: *strictInput, ZeroBased: *indexBase == 0, Remap: !*strictInput}
+	switch {
+	case *memoryLimit == 0:
+		dimacsOptions.MemoryLimit = cluster.AvailableMemory()
+	case *memoryLimit > 0:
+		dimacsOptions.MemoryLimit = uint64(*memoryLimit) << 20
+	}
+	g, warnings, err := encoding.LoadGraphOptions(inputFilename, dimacsOptions)
+	loadSpan.End()
+	ExpectOk(err)
+	for _, warning := range warnings {
+		logger.Warn(warning, "file", inputFilename)
+	}
+
+	instance := strings.TrimSuffix(filepath.Base(inputFilename), filepath.Ext(inputFilename))
+	logger = logger.With("instance", instance)
+	logger.Info("loaded graph", "file", inputFilename, "nodes", g.NodeCount(), "seed", config.Seed)
+	// Edge and total colorings color the line or total graph instead, and
+	// edges maps its nodes back to the edges of original for the outputs.
+	original := g
+	var edges [][2]int
+	switch *problem {
+	case "edge-coloring":
+		g, edges = graph.LineGraph(original)
+		logger.Info("coloring the edges as nodes of the line graph", "edges", len(edges), "max_degree", graph.MaxDegree(original))
+	case "total-coloring":
+		g, edges = graph.TotalGraph(original)
+		logger.Info("coloring the vertices and edges as nodes of the total graph", "edges", len(edges), "max_degree", graph.MaxDegree(original))
+	}
+	var precoloring ga.Chromosome
+	if *precoloringFile != "" {
+		precoloring, err = encoding.LoadPrecoloring(*precoloringFile)
+		ExpectOk(err)
+	}
+	if config.Colors == 0 && *problem == "bandwidth" {
+		span := 0
+		for _, color := range graph.GreedyBandwidth(g) {
+			span = max(span, color+1)
+		}
+		config.Colors = max(span-1, graph.MaxWeight(g)+1, ga.PrecoloredColors(precoloring))
+		options = append(options, ga.WithColors(config.Colors))
+		logger.Info("picked colors", "colors", config.Colors, "greedy_span", span, "max_weight", graph.MaxWeight(g))
+	}
+	if config.Colors == 0 && !*minimizeColors {
+		bounds := graph.ColorBounds(g)
+		config.Colors = max(bounds.Pick(), ga.PrecoloredColors(precoloring))
+		if *objective == "sum" {
+			// Sum colorings may need more colors than proper ones do, so
+			// the search keeps all the colors DSATUR needed.
+			config.Colors = max(bounds.DSatur, ga.PrecoloredColors(precoloring))
+		}
+		options = append(options, ga.WithColors(config.Colors))
+		logger.Info("picked colors", "colors", config.Colors, "lower", bounds.Lower, "upper", bounds.Upper, "dsatur", bounds.DSatur, "degeneracy", bounds.Degeneracy)
+	}
+	if precoloring != nil {
+		warnings, err := ga.CheckPrecoloring(g, precoloring, config.Colors)
+		ExpectOk(err)
+		for _, warning := range warnings {
+			logger.Warn(warning, "precoloring", *precoloringFile)
+		}
+		fixed := 0
+		for _, color := range precoloring {
+			if color != ga.Unassigned {
+				fixed++
+			}
+		}
+		options = append(options, ga.WithPrecoloring(precoloring))
+		logger.Info("extending precoloring", "file", *precoloringFile, "fixed", fixed, "unassigned", len(precoloring)-fixed)
+	}
+	var preferences []ga.Preference
+	if *preferencesFile != "" {
+		preferences, err = encoding.LoadPreferences(*preferencesFile)
+		ExpectOk(err)
+		ExpectOk(ga.CheckPreferences(g, preferences, config.Colors))
+	}
+
+	outputFilename := *outputFile
+	vizFilename := *vizOut
+	if *outDir != "" {
+		runDir := filepath.Join(*outDir, fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), instance))
+		ExpectOk(os.MkdirAll(runDir, 0700))
+		if *statsOut == "" {
+			*statsOut = "stats.csv"
+		}
+		*historyOut = cmp.Or(*historyOut, "convergence.json")
+		*chartOut = cmp.Or(*chartOut, "convergence.svg")
+		for _, filename := range []*string{
+			&outputFilename, &vizFilename, historyOut, chartOut, statsOut, graphMLOut,
+			gexfOut, tikzOut, reportOut, checkpointFile, animateDir, traceFile,
+		} {
+			if *filename != "" && !filepath.IsAbs(*filename) && !storage.IsRemote(*filename) {
+				*filename = filepath.Join(runDir, *filename)
+			}
+		}
+		ExpectOk(SaveResolvedConfig(filepath.Join(runDir, "config.json"), map[string]interface{}{
+			"input":  inputFilename,
+			"solver": config,
+		}))
+		logger.Info("writing run outputs", "dir", runDir)
+	}
+
+	vizOptions := viz.GraphVizOptions{
+		Name:           instance,
+		Layout:         *vizLayout,
+		NodeShape:      *vizNodeShape,
+		NodeSize:       *vizNodeSize,
+		EdgePenWidth:   *vizPenWidth,
+		ClusterByColor: *vizCluster,
+	}
+
+	solver := ga.NewSolver(g, append(options, ga.WithLogger(logger))...)
+	var preferenceFitness ga.PreferenceFitness
+	if preferences != nil {
+		// The configured fitness still names the run, since it is what the
+		// preferences are weighed against.
+		preferenceFitness = ga.NewPreferenceFitness(solver.Fitness, preferences)
+		if *conflictWeight > 0 {
+			preferenceFitness.ConflictWeight = *conflictWeight
+		}
+		solver.Fitness = preferenceFitness
+		logger.Info("weighing preferences", "file", *preferencesFile, "preferences", len(preferences), "conflict_weight", preferenceFitness.ConflictWeight)
+	}
+	if *resume != "" {
+		checkpoint, err := encoding.LoadCheckpoint(*resume)
+		ExpectOk(err)
+		warnings, err := checkpoint.Validate(g, solver.Config())
+		for _, warning := range warnings {
+			logger.Warn(warning, "checkpoint", *resume)
+		}
+		ExpectOk(err)
+		solver.Restore(checkpoint)
+		logger.Info("resuming", "checkpoint", *resume, "generation", checkpoint.Generation)
+	}
+	switch {
+	case *tui:
+		solver.Subscribe(NewDashboard(os.Stderr, vizOptions.Name, solver.NumIterations))
+	case !*plain && IsTerminal(os.Stderr):
+		solver.Subscribe(NewProgressBar(os.Stderr, solver.NumIterations))
+	default:
+		solver.Subscribe(ga.SubscriberFunc(LogProgress))
+	}
+	if *progressOut != "" {
+		progress, err := OpenProgressStream(*progressOut, *progressEvery)
+		ExpectOk(err)
+		defer progress.Close()
+		solver.Subscribe(progress)
+	}
+	if *serve != "" {
+		webDashboard := NewWebDashboard(g, vizOptions.Name, solver.NumIterations)
+		webDashboard.Serve(*serve)
+		solver.Subscribe(webDashboard)
+	}
+	if *animateDir != "" {
+		frameOptions := vizOptions
+		frameOptions.Positions = viz.CircularLayout(g.NodeCount())
+		recorder, err := viz.NewAnimationRecorder(g, *animateDir, *animateEvery, frameOptions)
+		ExpectOk(err)
+		recorder.Logger = logger
+		solver.Subscribe(recorder)
+	}
+	if solver.CheckpointEvery > 0 {
+		solver.Subscribe(ga.SubscriberFunc(func(event ga.Event) {
+			checkpointed, ok := event.(ga.Checkpointed)
+			if !ok {
+				return
+			}
+			err := encoding.SaveCheckpoint(*checkpointFile, &checkpointed.Checkpoint)
+			if err != nil {
+				logger.Warn("failed to save checkpoint", "file", *checkpointFile, "err", err)
+			}
+		}))
+	}
+	if *traceFile != "" {
+		trace, err := ga.CreateTrace(*traceFile)
+		ExpectOk(err)
+		solver.Trace = trace
+	}
+	// An interrupt stops the run and saves the best coloring so far as if
+	// the run had ended; a second one exits at once, as stop hands signals
+	// back to the default handler as soon as the first cancels the run.
+	solveCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(solveCtx, stop)
+	var solution ga.GraphColoringSolution
+	if *minimizeColors {
+		solver.MinimizeFromRandom = *minimizeFromRandom
+		solution = solver.MinimizeColors(solveCtx)
+		logger.Info("fewest colors found", "colors", solution.Metadata.Config.Colors, "generations", solution.Metadata.Generations)
+	} else if *islands > 1 {
+		// The first island is the solver the outputs follow, the others
+		// only differ from it by their seeds.
+		solvers := []*ga.GraphColoringSolver{solver}
+		for i := 1; i < *islands; i++ {
+			island := ga.NewSolver(solver.Graph, append(options, ga.WithSeed(config.Seed+int64(i)), ga.WithLogger(logger))...)
+			island.Fitness = solver.Fitness
+			solvers = append(solvers, island)
+		}
+		logger.Info("evolving islands", "islands", *islands, "migrate_every", *migrateEvery, "migrants", *migrants)
+		solution = ga.SolveIslands(solveCtx, solvers, *migrateEvery, *migrants)
+	} else {
+		solution = solver.SolveContext(solveCtx)
+	}
+	if solveCtx.Err() != nil && ctx.Err() == nil {
+		logger.Warn("interrupted, saving the best coloring so far", "score", solution.Score, "generation", solution.Metadata.Generations)
+	}
+	stop()
+	if *problem == "bandwidth" {
+		span := 0
+		for _, color := range solution.Coloring {
+			span = max(span, color+1)
+		}
+		logger.Info("bandwidth coloring", "span", span, "shortfall", ga.BandwidthFitness{}.Evaluate(g, solution.Coloring))
+	}
+	if *objective == "sum" {
+		logger.Info("chromatic sum", "sum", ga.ChromaticSum(solution.Coloring), "conflicts", graph.CountConflicts(g, solution.Coloring))
+	}
+	if preferences != nil {
+		logger.Info("preferences weighed", "conflicts", graph.CountConflicts(g, solution.Coloring), "missed_weight", preferenceFitness.Missed(solution.Coloring))
+	}
+	if solver.Trace != nil {
+		ExpectOk(solver.Trace.Close())
+	}
+	solution.Metadata.Instance = vizOptions.Name
+	solution.Metadata.Input = inputFilename
+	if *problem != "coloring" {
+		solution.Metadata.Problem = *problem
+	}
+	solution.Edges = edges
+	solution.Metadata.GitRevision = GitRevision()
+
+	ExpectOk(encoding.SaveSolution(outputFilename, &solution))
+	g.Colors = solution.Coloring
+	switch *problem {
+	case "edge-coloring":
+		ExpectOk(viz.SaveEdgeColoringGraphViz(vizFilename, original, edges, solution.Coloring, vizOptions))
+	case "total-coloring":
+		ExpectOk(viz.SaveTotalColoringGraphViz(vizFilename, original, edges, solution.Coloring, vizOptions))
+	default:
+		ExpectOk(viz.SaveGraphViz(vizFilename, g, vizOptions))
+	}
+	if *graphMLOut != "" {
+		ExpectOk(encoding.SaveGraphML(*graphMLOut, g))
+	}
+	if *gexfOut != "" {
+		ExpectOk(encoding.SaveGEXF(*gexfOut, g))
+	}
+	if *tikzOut != "" {
+		if g.NodeCount() > viz.TikZMaxNodes {
+			logger.Warn("TikZ picture will be hard to read", "nodes", g.NodeCount(), "max", viz.TikZMaxNodes)
+		}
+		ExpectOk(viz.SaveTikZ(*tikzOut, g))
+	}
+
+	if *historyOut != "" {
+		ExpectOk(encoding.SaveHistory(*historyOut, solver.History))
+	}
+	if *chartOut != "" {
+		ExpectOk(viz.SaveConvergenceChart(*chartOut, solver.History))
+	}
+	if *statsOut != "" {
+		ExpectOk(encoding.SaveTable(*statsOut, encoding.HistoryTable(solver.History)))
+	}
+	if *reportOut != "" {
+		report := RunReport{
+			Graph:    g,
+			Solution: solution,
+		}
+		ExpectOk(report.Save(*reportOut))
+	}
+	if *dbFilename != "" {
+		database, err := OpenRunDatabase(*dbFilename)
+		ExpectOk(err)
+		id, err := database.Insert(NewRunRecord(g, solution))
+		ExpectOk(err)
+		ExpectOk(database.Close())
+		logger.Info("run recorded", "id", id, "db", *dbFilename)
+	}
+
+	logger.Info("best coloring saved", "score", solution.Score, "file", outputFilename)
+}
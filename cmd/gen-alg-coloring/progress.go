@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+const logEvery = 100
+
+func LogProgress(event ga.Event) {
+	generation, ok := event.(ga.GenerationCompleted)
+	if ok && generation.Stats.Generation%logEvery == 0 {
+		logger.Info("progress", "generation", generation.Stats.Generation, "best", generation.Stats.Best)
+	}
+}
+
+type progressEvent struct {
+	Generation int     `json:"generation"`
+	Score      int     `json:"score"`
+	Elapsed    float64 `json:"elapsed"`
+	Diversity  float64 `json:"diversity"`
+}
+
+type ProgressStream struct {
+	writer  io.Writer
+	closer  io.Closer
+	encoder *json.Encoder
+	every   int
+}
+
+func OpenProgressStream(target string, every int) (*ProgressStream, error) {
+	if every < 1 {
+		every = 1
+	}
+
+	stream := &ProgressStream{every: every}
+	switch {
+	case target == "-":
+		stream.writer = os.Stdout
+	case strings.HasPrefix(target, "unix:"):
+		conn, err := net.Dial("unix", strings.TrimPrefix(target, "unix:"))
+		if err != nil {
+			return nil, err
+		}
+		stream.writer = conn
+		stream.closer = conn
+	default:
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return nil, err
+		}
+		stream.writer = file
+		stream.closer = file
+	}
+	stream.encoder = json.NewEncoder(stream.writer)
+
+	return stream, nil
+}
+
+func (stream *ProgressStream) Report(stats ga.GenerationStats, best ga.Chromosome) {
+	if stream.encoder == nil {
+		return
+	}
+	if stats.Generation%stream.every != 0 && stats.Best != 0 {
+		return
+	}
+
+	err := stream.encoder.Encode(progressEvent{
+		Generation: stats.Generation,
+		Score:      stats.Best,
+		Elapsed:    stats.Elapsed.Seconds(),
+		Diversity:  stats.Diversity,
+	})
+	if err != nil {
+		logger.Warn("progress stream stopped", "err", err)
+		stream.encoder = nil
+	}
+}
+
+func (stream *ProgressStream) Notify(event ga.Event) {
+	generation, ok := event.(ga.GenerationCompleted)
+	if ok {
+		stream.Report(generation.Stats, generation.Best)
+	}
+}
+
+func (stream *ProgressStream) Close() error {
+	if stream.closer == nil {
+		return nil
+	}
+	return stream.closer.Close()
+}
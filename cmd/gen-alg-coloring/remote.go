@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/server"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+// RemoteRun submits a run to a job server instead of solving locally.
+type RemoteRun struct {
+	URL      string
+	APIKey   string
+	Name     string
+	Input    string
+	Output   string
+	Config   ga.SolverConfig
+	Progress ga.Subscriber
+}
+
+// Run submits the job, reports its progress until it has finished and saves
+// its solution. An interrupt cancels the job, whose best solution so far is
+// still saved.
+func (run RemoteRun) Run(ctx context.Context) (*ga.GraphColoringSolution, error) {
+	run.URL = strings.TrimSuffix(run.URL, "/")
+	request := server.Request{Name: run.Name, Config: run.Config}
+	if storage.IsRemote(run.Input) {
+		request.GraphURI = run.Input
+	} else {
+		data, err := storage.ReadFile(ctx, run.Input)
+		if err != nil {
+			return nil, err
+		}
+		request.DIMACS = string(data)
+	}
+
+	status := server.Status{}
+	err := run.call(ctx, http.MethodPost, "/jobs", request, &status)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("submitted job", "server", run.URL, "job", status.ID, "seed", status.Config.Seed)
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-interrupts:
+			logger.Info("cancelling job", "job", status.ID)
+			err := run.call(context.Background(), http.MethodDelete, "/jobs/"+status.ID, nil, nil)
+			if err != nil {
+				logger.Warn("failed to cancel job", "job", status.ID, "err", err)
+			}
+		case <-finished:
+		}
+	}()
+
+	status, err = run.follow(ctx, status.ID)
+	if err != nil {
+		return nil, err
+	}
+	solution := ga.GraphColoringSolution{}
+	err = run.call(ctx, http.MethodGet, "/jobs/"+status.ID+"/solution", nil, &solution)
+	if err != nil {
+		return nil, fmt.Errorf("job %s is %s: %w", status.ID, status.State, err)
+	}
+	if status.Error != "" {
+		logger.Warn("job failed", "job", status.ID, "err", status.Error)
+	}
+	return &solution, encoding.SaveSolution(run.Output, &solution)
+}
+
+// follow passes the progress of job id to run.Progress until the job has
+// finished, and returns its last status.
+func (run RemoteRun) follow(ctx context.Context, id string) (server.Status, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, run.URL+"/jobs/"+id+"/events", nil)
+	if err != nil {
+		return server.Status{}, err
+	}
+	run.authorize(request)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return server.Status{}, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return server.Status{}, responseError(response)
+	}
+
+	status := server.Status{}
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		err = json.Unmarshal([]byte(data), &status)
+		if err != nil {
+			return server.Status{}, err
+		}
+		if status.State.Finished() {
+			run.Progress.Notify(ga.Terminated{})
+			return status, nil
+		}
+		if status.Generation > 0 {
+			run.Progress.Notify(ga.GenerationCompleted{Stats: ga.GenerationStats{
+				Generation: status.Generation - 1,
+				Best:       status.BestScore,
+				Elapsed:    status.Elapsed,
+			}})
+		}
+	}
+	err = scanner.Err()
+	if err == nil {
+		err = errors.New("event stream ended before the job finished")
+	}
+	return server.Status{}, err
+}
+
+// remoteLog logs progress lines like LogProgress, but on the first status at
+// or past every logEvery generations, as the event stream may skip some.
+type remoteLog struct {
+	next int
+}
+
+func (log *remoteLog) Notify(event ga.Event) {
+	generation, ok := event.(ga.GenerationCompleted)
+	if ok && generation.Stats.Generation >= log.next {
+		logger.Info("progress", "generation", generation.Stats.Generation, "best", generation.Stats.Best)
+		log.next = (generation.Stats.Generation/logEvery + 1) * logEvery
+	}
+}
+
+func (run RemoteRun) call(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, run.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	run.authorize(request)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return responseError(response)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+func (run RemoteRun) authorize(request *http.Request) {
+	if run.APIKey != "" {
+		request.Header.Set("Authorization", "Bearer "+run.APIKey)
+	}
+}
+
+func responseError(response *http.Response) error {
+	message := struct {
+		Error string `json:"error"`
+	}{}
+	err := json.NewDecoder(response.Body).Decode(&message)
+	if err != nil || message.Error == "" {
+		return errors.New(response.Status)
+	}
+	return fmt.Errorf("%s: %s", response.Status, message.Error)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/experiment"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+// runRepeats solves input with config once for each of repeats consecutive
+// seeds from config.Seed, prints statistics over the runs and saves the best
+// solution to output.
+func runRepeats(input string, output string, config ga.SolverConfig, repeats int) {
+	seeds := make([]int64, repeats)
+	for i := range seeds {
+		seeds[i] = config.Seed + int64(i)
+	}
+	manifest := experiment.Experiment{
+		Instances:      []string{input},
+		Configurations: []experiment.Configuration{{Name: "repeat", SolverConfig: config}},
+		Seeds:          seeds,
+	}
+	logger.Info("repeating run", "file", input, "repeats", repeats, "first_seed", config.Seed)
+	results, err := manifest.Run()
+	ExpectOk(err)
+
+	best := results[0]
+	for _, result := range results[1:] {
+		if result.Solution.Score < best.Solution.Score ||
+			(result.Solution.Score == best.Solution.Score && result.Solution.Metadata.Elapsed < best.Solution.Metadata.Elapsed) {
+			best = result
+		}
+	}
+
+	statistics := experiment.Describe(results)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tRUNS\tMEAN\tMEDIAN\tSTDDEV\tMIN\tMAX")
+	for _, metric := range []struct {
+		name         string
+		distribution experiment.Distribution
+	}{
+		{"best score", statistics.Score},
+		{"time to feasible (s)", statistics.TimeToFeasible},
+		{"generations", statistics.Generations},
+	} {
+		d := metric.distribution
+		fmt.Fprintf(w, "%s\t%d\t%.4g\t%.4g\t%.4g\t%.4g\t%.4g\n", metric.name, d.N, d.Mean, d.Median, d.StdDev, d.Min, d.Max)
+	}
+	w.Flush()
+	fmt.Printf("\nsuccess rate: %d of %d runs (%.1f%%)\n", statistics.Solved, statistics.Runs, 100*statistics.SuccessRate)
+
+	ExpectOk(encoding.SaveSolution(output, &best.Solution))
+	logger.Info("best coloring saved", "score", best.Solution.Score, "seed", best.Seed, "file", output)
+}
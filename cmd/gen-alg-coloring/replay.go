@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+func runReplay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	graphFilename := flags.String("graph", "", "DIMACS graph the trace was recorded on")
+	verbose := flags.Bool("v", false, "print every replayed child")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s replay --graph g.col trace.gz\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *graphFilename == "" || flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	g, err := encoding.LoadGraph(*graphFilename)
+	ExpectOk(err)
+	file, err := os.Open(flags.Arg(0))
+	ExpectOk(err)
+	defer file.Close()
+
+	solver := ga.NewSolver(g)
+	generations, err := solver.Replay(file, os.Stdout, *verbose)
+	if err != nil {
+		Fatal("replay diverged", "generations", generations, "err", err)
+	}
+	fmt.Printf("replayed %d generations, all scores match the recording\n", generations)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+type RunReport struct {
+	Graph    *graph.Graph
+	Solution ga.GraphColoringSolution
+}
+
+type reportWriter struct {
+	sb       strings.Builder
+	markdown bool
+}
+
+func (w *reportWriter) title(text string) {
+	if w.markdown {
+		w.sb.WriteString(fmt.Sprintf("# %s\n", text))
+	} else {
+		w.sb.WriteString(fmt.Sprintf("%s\n%s\n", text, strings.Repeat("=", len(text))))
+	}
+}
+
+func (w *reportWriter) section(text string) {
+	if w.markdown {
+		w.sb.WriteString(fmt.Sprintf("\n## %s\n\n", text))
+	} else {
+		w.sb.WriteString(fmt.Sprintf("\n%s\n", text))
+	}
+}
+
+func (w *reportWriter) row(key string, format string, args ...interface{}) {
+	value := fmt.Sprintf(format, args...)
+	if w.markdown {
+		w.sb.WriteString(fmt.Sprintf("- **%s**: %s\n", key, value))
+	} else {
+		w.sb.WriteString(fmt.Sprintf("  %-16s %s\n", key+":", value))
+	}
+}
+
+func (report *RunReport) Render(markdown bool) string {
+	w := reportWriter{markdown: markdown}
+	g := report.Graph
+	metadata := report.Solution.Metadata
+
+	edgeCount := 0
+	for _, neighbours := range g.AdjecencyList {
+		edgeCount += len(neighbours)
+	}
+	maxDegree := 0
+	for _, degree := range g.Degrees() {
+		if degree > maxDegree {
+			maxDegree = degree
+		}
+	}
+	nodeCount := g.NodeCount()
+	density := 0.0
+	averageDegree := 0.0
+	if nodeCount > 1 {
+		density = 2 * float64(edgeCount) / float64(nodeCount*(nodeCount-1))
+		averageDegree = 2 * float64(edgeCount) / float64(nodeCount)
+	}
+
+	w.title(fmt.Sprintf("Run report: %s", metadata.Instance))
+
+	w.section("Instance")
+	w.row("name", "%s", metadata.Instance)
+	w.row("nodes", "%d", nodeCount)
+	w.row("edges", "%d", edgeCount)
+	w.row("density", "%.4f", density)
+	w.row("max degree", "%d", maxDegree)
+	w.row("average degree", "%.2f", averageDegree)
+
+	w.section("Parameters")
+	w.row("colors", "%d", metadata.Config.Colors)
+	w.row("iterations", "%d", metadata.Config.Iterations)
+	w.row("population", "%d", metadata.Config.PopSize)
+	w.row("seed", "%d", metadata.Config.Seed)
+	if metadata.GitRevision != "" {
+		w.row("revision", "%s", metadata.GitRevision)
+	}
+
+	w.section("Result")
+	w.row("score", "%d", report.Solution.Score)
+	w.row("conflicts", "%d", g.Conflicts())
+	w.row("colors used", "%d", g.ColorsUsed())
+	classSizes := make([]int, g.PaletteSize())
+	for _, color := range g.Colors {
+		classSizes[color]++
+	}
+	var classes []string
+	for color, size := range classSizes {
+		classes = append(classes, fmt.Sprintf("%d:%d", color, size))
+	}
+	w.row("class sizes", "%s", strings.Join(classes, " "))
+
+	w.row("generations", "%d", metadata.Generations)
+	w.row("evaluations", "%d", metadata.Evaluations)
+	w.row("runtime", "%s", metadata.Elapsed.Round(time.Millisecond))
+	w.row("started", "%s", metadata.StartedAt.Format(time.RFC3339))
+	w.row("finished", "%s", metadata.FinishedAt.Format(time.RFC3339))
+
+	return w.sb.String()
+}
+
+func (report *RunReport) Save(filename string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	markdown := ext == ".md" || ext == ".markdown"
+
+	return os.WriteFile(filename, []byte(report.Render(markdown)), 0600)
+}
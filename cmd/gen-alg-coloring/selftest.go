@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/instances"
+)
+
+// runSelftest colors every embedded instance with its chromatic number and
+// exits with status 1 if any of them stays in conflict, so that it can serve
+// as an acceptance test of a build.
+func runSelftest(args []string) {
+	flags := flag.NewFlagSet("selftest", flag.ExitOnError)
+	configFile := flags.String("config", "", "read solver settings from this JSON or YAML file; the color count and seed are set by the test")
+	seeds := flags.Int("seeds", 3, "seeds to try on every instance before it fails")
+	timeLimit := flags.Duration("time-limit", 30*time.Second, "stop every attempt after this long")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s selftest [-config config.json] [-seeds N] [-time-limit 30s]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	config := ga.SolverConfig{}
+	if *configFile != "" {
+		loaded, err := encoding.LoadConfig(*configFile)
+		ExpectOk(err)
+		config = *loaded
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE\tNODES\tCOLORS\tRESULT\tSEED\tCONFLICTS\tTIME")
+	for _, instance := range instances.Known {
+		g, err := instance.Graph()
+		ExpectOk(err)
+		config.Colors = instance.Chromatic
+
+		result, seed, conflicts, elapsed := "FAIL", 0, 0, time.Duration(0)
+		for seed = 1; seed <= max(*seeds, 1); seed++ {
+			config.Seed = int64(seed)
+			options, err := config.Options()
+			ExpectOk(err)
+			started := time.Now()
+			ctx, cancel := context.WithTimeout(context.Background(), *timeLimit)
+			solution := ga.NewSolver(g, options...).SolveContext(ctx)
+			cancel()
+			elapsed += time.Since(started)
+			// The coloring is checked edge by edge rather than trusting the score.
+			conflicts = graph.CountConflicts(g, solution.Coloring)
+			if conflicts == 0 {
+				result = "PASS"
+				break
+			}
+		}
+		if result == "FAIL" {
+			failed++
+			seed--
+		}
+		logger.Info("tested", "instance", instance.Name, "result", result, "elapsed", elapsed.Round(time.Millisecond))
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%d\t%d\t%s\n", instance.Name, g.NodeCount(), instance.Chromatic, result, seed, conflicts, elapsed.Round(time.Millisecond))
+	}
+	w.Flush()
+
+	if failed > 0 {
+		fmt.Printf("\n%d of %d instances not colored with their chromatic number\n", failed, len(instances.Known))
+		os.Exit(1)
+	}
+	fmt.Printf("\nall %d instances colored with their chromatic number\n", len(instances.Known))
+}
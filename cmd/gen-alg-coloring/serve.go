@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+
+	"github.com/packedbread/gen-alg-graph-coloring/server"
+)
+
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to serve the HTTP API on")
+	grpcAddr := flags.String("grpc-addr", "", "also serve the gRPC API on this address (e.g. :9090)")
+	parallelism := flags.Int("parallel", 0, "number of jobs to run at once (0 runs one per CPU)")
+	natsURL := flags.String("nats-url", "", "also take jobs from this NATS server (e.g. nats://localhost:4222)")
+	natsSubject := flags.String("nats-subject", "coloring.jobs", "NATS subject to take jobs from")
+	natsResults := flags.String("nats-results", "coloring.results", "NATS subject prefix on which finished jobs are published")
+	storeFile := flags.String("store", "", "keep jobs in this SQLite database and resume them after a restart")
+	maxAttempts := flags.Int("max-attempts", 3, "number of times a job is run before giving up on it when it fails or is interrupted")
+	maxNodes := flags.Int("max-nodes", 0, "reject graphs with more nodes (0 is unlimited)")
+	maxIterations := flags.Int("max-iterations", 0, "reject jobs with more iterations (0 is unlimited)")
+	maxPopSize := flags.Int("max-popsize", 0, "reject jobs with a larger population (0 is unlimited)")
+	jobTimeout := flags.Duration("job-timeout", 0, "fail jobs that run for longer (0 is unlimited)")
+	tenantsFile := flags.String("tenants", "", "JSON list of tenants with API keys, priorities, job quotas, storage prefixes and callback hosts; requests then need a key")
+	publicURL := flags.String("public-url", "", "URL the job API is reachable at, for the links in callback summaries (e.g. https://coloring.example.com)")
+	retention := flags.Duration("retention", 0, "forget finished jobs and their solutions after this long (0 keeps them)")
+	otelEndpoint := flags.String("otel-endpoint", "", "export OpenTelemetry spans over OTLP/gRPC to this host:port (env reads OTEL_EXPORTER_OTLP_ENDPOINT)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s serve [-addr :8080] [-grpc-addr :9090] [-parallel N] [-store jobs.db]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *otelEndpoint != "" {
+		shutdown, err := SetupTracing(*otelEndpoint)
+		ExpectOk(err)
+		defer shutdown()
+	}
+
+	jobs := server.New(*parallelism, logger)
+	jobs.MaxAttempts = *maxAttempts
+	jobs.BaseURL = strings.TrimSuffix(*publicURL, "/")
+	jobs.Limits = server.Limits{
+		MaxNodes:      *maxNodes,
+		MaxIterations: *maxIterations,
+		MaxPopSize:    *maxPopSize,
+		MaxDuration:   *jobTimeout,
+	}
+	if *tenantsFile != "" {
+		tenants, err := server.LoadTenants(*tenantsFile)
+		ExpectOk(err)
+		ExpectOk(jobs.SetTenants(tenants))
+		logger.Info("serving tenants", "tenants", len(tenants))
+	}
+	if *storeFile != "" {
+		store, err := server.OpenStore(*storeFile)
+		ExpectOk(err)
+		defer store.Close()
+		ExpectOk(jobs.UseStore(store))
+		logger.Info("keeping jobs in store", "store", *storeFile, "jobs", len(jobs.List(context.Background())))
+	}
+	if *retention > 0 {
+		go collectJobs(jobs, *retention)
+	}
+	if *natsURL != "" {
+		conn, err := nats.Connect(*natsURL)
+		ExpectOk(err)
+		defer conn.Close()
+		_, err = jobs.ConsumeNATS(conn, *natsSubject, *natsResults)
+		ExpectOk(err)
+		logger.Info("taking jobs from NATS", "url", *natsURL, "subject", *natsSubject, "results", *natsResults+".<id>")
+	}
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		ExpectOk(err)
+		grpcServer := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+		jobs.RegisterGRPC(grpcServer)
+		logger.Info("serving gRPC API", "addr", *grpcAddr)
+		go func() {
+			ExpectOk(grpcServer.Serve(listener))
+		}()
+		defer grpcServer.Stop()
+	}
+
+	// Jobs still running on shutdown stay in the store and are run again on
+	// the next start.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	httpServer := &http.Server{Addr: *addr, Handler: otelhttp.NewHandler(jobs.Handler(), "jobs")}
+	shutDown := make(chan struct{})
+	go func() {
+		defer close(shutDown)
+		<-ctx.Done()
+		logger.Info("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+	logger.Info("serving job API", "addr", *addr)
+	err := httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		ExpectOk(err)
+	}
+	<-shutDown
+}
+
+// collectJobs forgets the jobs that finished more than retention ago, checking
+// once a minute.
+func collectJobs(jobs *server.Server, retention time.Duration) {
+	for ; ; time.Sleep(time.Minute) {
+		removed, err := jobs.Collect(time.Now().Add(-retention))
+		if err != nil {
+			logger.Warn("failed to collect finished jobs", "err", err)
+		} else if removed > 0 {
+			logger.Info("collected finished jobs", "removed", removed)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+const (
+	tracerName      = "github.com/packedbread/gen-alg-graph-coloring/cmd/gen-alg-coloring"
+	shutdownTimeout = 5 * time.Second
+)
+
+// SetupTracing exports OpenTelemetry spans over OTLP/gRPC to endpoint, or to
+// the endpoint in OTEL_EXPORTER_OTLP_ENDPOINT if it is "env". The returned
+// function flushes the remaining spans and must be called before exiting.
+func SetupTracing(endpoint string) (func(), error) {
+	options := []otlptracegrpc.Option{}
+	if endpoint != "env" {
+		options = append(options, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("gen-alg-coloring"),
+			semconv.ServiceVersion(GitRevision()),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("failed to export spans", "err", err)
+	}))
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err := provider.Shutdown(ctx)
+		if err != nil {
+			logger.Warn("failed to flush spans", "err", err)
+		}
+	}, nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+const (
+	dashboardRefresh = 100 * time.Millisecond
+	sparklineWidth   = 60
+)
+
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+type Dashboard struct {
+	out           io.Writer
+	title         string
+	numIterations int
+
+	lastDraw time.Time
+	last     ga.GenerationStats
+	scores   []int
+}
+
+func NewDashboard(out io.Writer, title string, numIterations int) *Dashboard {
+	return &Dashboard{
+		out:           out,
+		title:         title,
+		numIterations: numIterations,
+	}
+}
+
+func (dashboard *Dashboard) Update(stats ga.GenerationStats, best ga.Chromosome) {
+	dashboard.last = stats
+	if time.Since(dashboard.lastDraw) < dashboardRefresh && stats.Best != 0 {
+		return
+	}
+
+	dashboard.scores = append(dashboard.scores, stats.Best)
+	if len(dashboard.scores) > sparklineWidth {
+		dashboard.scores = dashboard.scores[len(dashboard.scores)-sparklineWidth:]
+	}
+	dashboard.draw()
+}
+
+func (dashboard *Dashboard) Finish() {
+	dashboard.draw()
+	fmt.Fprintln(dashboard.out)
+}
+
+func (dashboard *Dashboard) Notify(event ga.Event) {
+	switch event := event.(type) {
+	case ga.GenerationCompleted:
+		dashboard.Update(event.Stats, event.Best)
+	case ga.Terminated:
+		dashboard.Finish()
+	}
+}
+
+func (dashboard *Dashboard) draw() {
+	dashboard.lastDraw = time.Now()
+	stats := dashboard.last
+
+	progress, eta := estimateProgress(stats, dashboard.numIterations)
+	rate := evaluationRate(stats)
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[H\x1b[2J")
+	sb.WriteString(fmt.Sprintf("gen-alg-graph-coloring  %s\n\n", dashboard.title))
+	sb.WriteString(fmt.Sprintf("  generation   %d / %d (%.1f%%)\n", stats.Generation, dashboard.numIterations, 100*progress))
+	sb.WriteString(fmt.Sprintf("  best score   %d\n", stats.Best))
+	sb.WriteString(fmt.Sprintf("  mean score   %.2f\n", stats.Mean))
+	sb.WriteString(fmt.Sprintf("  diversity    %.4f\n", stats.Diversity))
+	sb.WriteString(fmt.Sprintf("  evaluations  %d (%.0f/s)\n", stats.Evaluations, rate))
+	sb.WriteString(fmt.Sprintf("  elapsed      %s\n", stats.Elapsed.Round(time.Second)))
+	sb.WriteString(fmt.Sprintf("  eta          %s\n\n", eta.Round(time.Second)))
+	sb.WriteString(fmt.Sprintf("  score  %s\n", sparkline(dashboard.scores)))
+
+	fmt.Fprint(dashboard.out, sb.String())
+}
+
+func estimateProgress(stats ga.GenerationStats, numIterations int) (float64, time.Duration) {
+	done := stats.Generation + 1
+	if done >= numIterations {
+		return 1, 0
+	}
+
+	remaining := time.Duration(float64(stats.Elapsed) * float64(numIterations-done) / float64(done))
+	return float64(done) / float64(numIterations), remaining
+}
+
+func evaluationRate(stats ga.GenerationStats) float64 {
+	if stats.Elapsed <= 0 {
+		return 0
+	}
+	return float64(stats.Evaluations) / stats.Elapsed.Seconds()
+}
+
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+const progressBarWidth = 30
+
+type ProgressBar struct {
+	out           io.Writer
+	numIterations int
+
+	lastDraw time.Time
+	last     ga.GenerationStats
+}
+
+func NewProgressBar(out io.Writer, numIterations int) *ProgressBar {
+	return &ProgressBar{
+		out:           out,
+		numIterations: numIterations,
+	}
+}
+
+func (bar *ProgressBar) Update(stats ga.GenerationStats, best ga.Chromosome) {
+	bar.last = stats
+	if time.Since(bar.lastDraw) < dashboardRefresh && stats.Best != 0 {
+		return
+	}
+	bar.draw()
+}
+
+func (bar *ProgressBar) Finish() {
+	bar.draw()
+	fmt.Fprintln(bar.out)
+}
+
+func (bar *ProgressBar) Notify(event ga.Event) {
+	switch event := event.(type) {
+	case ga.GenerationCompleted:
+		bar.Update(event.Stats, event.Best)
+	case ga.Terminated:
+		bar.Finish()
+	}
+}
+
+func (bar *ProgressBar) draw() {
+	bar.lastDraw = time.Now()
+	stats := bar.last
+
+	progress, eta := estimateProgress(stats, bar.numIterations)
+	if stats.Best == 0 {
+		progress, eta = 1, 0
+	}
+	filled := int(progress * progressBarWidth)
+
+	scoreColor := "\x1b[33m"
+	if stats.Best == 0 {
+		scoreColor = "\x1b[32m"
+	}
+
+	fmt.Fprintf(
+		bar.out,
+		"\r\x1b[K[\x1b[36m%s\x1b[0m%s] %5.1f%% gen %d/%d best %s%d\x1b[0m %.0f eval/s ETA %s",
+		strings.Repeat("#", filled),
+		strings.Repeat(".", progressBarWidth-filled),
+		100*progress,
+		stats.Generation,
+		bar.numIterations,
+		scoreColor,
+		stats.Best,
+		evaluationRate(stats),
+		eta.Round(time.Second),
+	)
+}
+
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	min, max := values[0], values[0]
+	for _, value := range values {
+		if value < min {
+			min = value
+		}
+		if value > max {
+			max = value
+		}
+	}
+
+	var sb strings.Builder
+	for _, value := range values {
+		level := 0
+		if max > min {
+			level = (value - min) * (len(sparklineLevels) - 1) / (max - min)
+		}
+		sb.WriteRune(sparklineLevels[level])
+	}
+	return sb.String()
+}
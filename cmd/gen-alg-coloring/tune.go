@@ -0,0 +1,406 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/experiment"
+	"github.com/packedbread/gen-alg-graph-coloring/viz"
+)
+
+func runTune(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s tune grid|search|race|ab [flags] file.json\n       %s tune sensitivity [flags] results.json\n", os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "grid":
+		runTuneGrid(args[1:])
+	case "search":
+		runTuneSearch(args[1:])
+	case "race":
+		runTuneRace(args[1:])
+	case "ab":
+		runTuneAB(args[1:])
+	case "sensitivity":
+		runTuneSensitivity(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown tune method %q, expected grid, search, race, ab or sensitivity\n", args[0])
+		os.Exit(2)
+	}
+}
+
+func runTuneGrid(args []string) {
+	flags := flag.NewFlagSet("tune grid", flag.ExitOnError)
+	parallelism := flags.Int("parallel", 0, "number of runs to execute at once (overrides the grid file)")
+	resultsOut := flags.String("out", "", "write every individual run result as JSON to this file")
+	runsOut := flags.String("runs-out", "", "write a row per run as CSV, or as Parquet for .parquet, to this file")
+	generationsOut := flags.String("generations-out", "", "write a row per generation of every run as CSV, or as Parquet for .parquet, to this file")
+	bestOut := flags.String("best-out", "", "write the solver config of the best configuration to this file, for use with -config")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s tune grid [-parallel N] [-out results.json] [-best-out best.json] grid.json\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	grid, err := experiment.LoadGrid(flags.Arg(0))
+	ExpectOk(err)
+	if *parallelism > 0 {
+		grid.Parallelism = *parallelism
+	}
+	manifest, err := grid.Experiment()
+	ExpectOk(err)
+	logger.Info("tuning", "configurations", len(manifest.Configurations), "runs", len(manifest.Configurations)*len(manifest.Instances)*len(manifest.Seeds))
+
+	manifest.KeepHistory = *generationsOut != ""
+	results, err := manifest.Run()
+	ExpectOk(err)
+	if *resultsOut != "" {
+		bytes, err := json.MarshalIndent(results, "", "\t")
+		ExpectOk(err)
+		ExpectOk(os.WriteFile(*resultsOut, bytes, 0600))
+	}
+	saveTables(results, *runsOut, *generationsOut)
+
+	rankings := experiment.Rank(results)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tCONFIGURATION\tRUNS\tSOLVED\tBEST\tMEAN\tSTDDEV\tGENERATIONS\tTIME")
+	for i, ranking := range rankings {
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%d\t%d\t%d\t%.2f\t%.2f\t%.0f\t%s\n",
+			i+1,
+			ranking.Configuration,
+			ranking.Runs,
+			ranking.Solved,
+			ranking.BestScore,
+			ranking.MeanScore,
+			ranking.ScoreStdDev,
+			ranking.MeanGenerations,
+			ranking.MeanElapsed.Round(time.Millisecond),
+		)
+	}
+	w.Flush()
+
+	best := rankings[0]
+	for _, configuration := range manifest.Configurations {
+		if configuration.Name != best.Configuration {
+			continue
+		}
+		bytes, err := json.MarshalIndent(configuration.SolverConfig, "", "\t")
+		ExpectOk(err)
+		fmt.Printf("\nbest configuration: %s (solved %d of %d runs, mean score %.2f)\n%s\n", best.Configuration, best.Solved, best.Runs, best.MeanScore, bytes)
+		if *bestOut != "" {
+			ExpectOk(os.WriteFile(*bestOut, bytes, 0600))
+		}
+	}
+}
+
+func runTuneSearch(args []string) {
+	flags := flag.NewFlagSet("tune search", flag.ExitOnError)
+	parallelism := flags.Int("parallel", 0, "number of runs to execute at once (overrides the search file)")
+	budget := flags.Int("budget", 0, "evaluation budget: configurations to try, each on every instance and seed (0 keeps the search file's budget, where 0 means no limit within its wall_clock)")
+	method := flags.String("method", "", "search method: random, tpe, halving or hyperband (overrides the search file, defaults to tpe)")
+	objective := flags.String("objective", "", "what to minimize: score or time to zero conflicts (overrides the search file, defaults to score)")
+	timeLimit := flags.Duration("time-limit", 0, "stop every run after this long; required by -objective time (overrides the search file)")
+	seed := flags.Int64("seed", 0, "seed for choosing configurations (overrides the search file)")
+	wallClock := flags.Duration("wall-clock", 0, "stop the whole search after this long with the trials finished by then (overrides the search file)")
+	trialsOut := flags.String("out", "", "write every trial as JSON to this file")
+	bestOut := flags.String("best-out", "", "write the solver config of the best configuration to this file, for use with -config")
+	top := flags.Int("top", 10, "number of best trials to list")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s tune search [-budget N] [-wall-clock 1h] [-method random|tpe|halving|hyperband] [-objective score|time -time-limit 10s] [-out trials.json] space.json\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	search, err := experiment.LoadSearch(flags.Arg(0))
+	ExpectOk(err)
+	if *parallelism > 0 {
+		search.Parallelism = *parallelism
+	}
+	if *budget > 0 {
+		search.Budget = *budget
+	}
+	if *method != "" {
+		search.Method = *method
+	}
+	if *objective != "" {
+		search.Objective = *objective
+	}
+	if *timeLimit > 0 {
+		search.TimeLimit = timeLimit.String()
+	}
+	if *seed != 0 {
+		search.Seed = *seed
+	}
+	if *wallClock > 0 {
+		search.WallClock = wallClock.String()
+	}
+
+	trials, err := search.Run(func(trial experiment.Trial) {
+		logger.Info("trial", "index", trial.Index, "configuration", trial.Configuration.Name, "time_limit", trial.TimeLimit, "cost", trial.Cost, "solved", trial.Ranking.Solved, "runs", trial.Ranking.Runs)
+	})
+	ExpectOk(err)
+	if len(trials) == 0 {
+		Fatal("no trial finished within the wall clock", "wall_clock", search.WallClock)
+	}
+	if *trialsOut != "" {
+		bytes, err := json.MarshalIndent(trials, "", "\t")
+		ExpectOk(err)
+		ExpectOk(os.WriteFile(*trialsOut, bytes, 0600))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TRIAL\tCONFIGURATION\tTIME LIMIT\tCOST\tRUNS\tSOLVED\tBEST\tMEAN\tSTDDEV\tTIME")
+	for _, trial := range trials[:min(*top, len(trials))] {
+		timeLimit := "-"
+		if trial.TimeLimit > 0 {
+			timeLimit = trial.TimeLimit.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%.4g\t%d\t%d\t%d\t%.2f\t%.2f\t%s\n",
+			trial.Index,
+			trial.Configuration.Name,
+			timeLimit,
+			trial.Cost,
+			trial.Ranking.Runs,
+			trial.Ranking.Solved,
+			trial.Ranking.BestScore,
+			trial.Ranking.MeanScore,
+			trial.Ranking.ScoreStdDev,
+			trial.Ranking.MeanElapsed.Round(time.Millisecond),
+		)
+	}
+	w.Flush()
+
+	best := trials[0]
+	bytes, err := json.MarshalIndent(best.Configuration.SolverConfig, "", "\t")
+	ExpectOk(err)
+	fmt.Printf("\nbest configuration: %s (%s %.4g over %d runs)\n%s\n", best.Configuration.Name, search.Objective, best.Cost, best.Ranking.Runs, bytes)
+	if *bestOut != "" {
+		ExpectOk(os.WriteFile(*bestOut, bytes, 0600))
+	}
+}
+
+func runTuneRace(args []string) {
+	flags := flag.NewFlagSet("tune race", flag.ExitOnError)
+	parallelism := flags.Int("parallel", 0, "number of runs to execute at once (overrides the race file)")
+	budget := flags.Int("budget", 0, "evaluation budget: runs of a configuration on an instance and seed to spend at most (0 keeps the race file's budget, where 0 means no limit)")
+	objective := flags.String("objective", "", "what to minimize: score or time to zero conflicts (overrides the race file, defaults to score)")
+	timeLimit := flags.Duration("time-limit", 0, "stop every run after this long; required by -objective time (overrides the race file)")
+	alpha := flags.Float64("alpha", 0, "significance level of the elimination tests (overrides the race file, defaults to 0.05)")
+	resultsOut := flags.String("out", "", "write every candidate with its costs as JSON to this file")
+	bestOut := flags.String("best-out", "", "write the solver config of the winner to this file, for use with -config")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s tune race [-budget N] [-objective score|time -time-limit 10s] [-alpha 0.05] race.json\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	race, err := experiment.LoadRace(flags.Arg(0))
+	ExpectOk(err)
+	if *parallelism > 0 {
+		race.Parallelism = *parallelism
+	}
+	if *budget > 0 {
+		race.Budget = *budget
+	}
+	if *objective != "" {
+		race.Objective = *objective
+	}
+	if *timeLimit > 0 {
+		race.TimeLimit = timeLimit.String()
+	}
+	if *alpha > 0 {
+		race.Alpha = *alpha
+	}
+
+	standings, err := race.Run(func(blocks int, alive []*experiment.Contender, dropped []*experiment.Contender) {
+		for _, contender := range dropped {
+			logger.Info("eliminated", "configuration", contender.Configuration.Name, "blocks", blocks, "mean_rank", contender.MeanRank)
+		}
+		logger.Info("block finished", "blocks", blocks, "alive", len(alive))
+	})
+	ExpectOk(err)
+	if *resultsOut != "" {
+		bytes, err := json.MarshalIndent(standings, "", "\t")
+		ExpectOk(err)
+		ExpectOk(os.WriteFile(*resultsOut, bytes, 0600))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONFIGURATION\tBLOCKS\tMEAN RANK\tMEAN COST\tELIMINATED")
+	for _, contender := range standings {
+		eliminated := "-"
+		if contender.Eliminated > 0 {
+			eliminated = fmt.Sprintf("after %d", contender.Eliminated)
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%d\t%.2f\t%.4g\t%s\n",
+			contender.Configuration.Name,
+			len(contender.Costs),
+			contender.MeanRank,
+			contender.MeanCost,
+			eliminated,
+		)
+	}
+	w.Flush()
+
+	best := standings[0]
+	bytes, err := json.MarshalIndent(best.Configuration.SolverConfig, "", "\t")
+	ExpectOk(err)
+	fmt.Printf("\nbest configuration: %s (mean rank %.2f over %d blocks)\n%s\n", best.Configuration.Name, best.MeanRank, len(best.Costs), bytes)
+	if *bestOut != "" {
+		ExpectOk(os.WriteFile(*bestOut, bytes, 0600))
+	}
+}
+
+func runTuneAB(args []string) {
+	flags := flag.NewFlagSet("tune ab", flag.ExitOnError)
+	parallelism := flags.Int("parallel", 0, "number of runs to execute at once (overrides the comparison file)")
+	objective := flags.String("objective", "", "what to minimize: score or time to zero conflicts (overrides the comparison file, defaults to score)")
+	timeLimit := flags.Duration("time-limit", 0, "stop every run after this long; required by -objective time (overrides the comparison file)")
+	alpha := flags.Float64("alpha", 0, "significance level of the verdicts (overrides the comparison file, defaults to 0.05)")
+	resultsOut := flags.String("out", "", "write the outcomes and every individual run result as JSON to this file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s tune ab [-objective score|time -time-limit 10s] [-alpha 0.05] ab.json\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ab, err := experiment.LoadAB(flags.Arg(0))
+	ExpectOk(err)
+	if *parallelism > 0 {
+		ab.Parallelism = *parallelism
+	}
+	if *objective != "" {
+		ab.Objective = *objective
+	}
+	if *timeLimit > 0 {
+		ab.TimeLimit = timeLimit.String()
+	}
+	if *alpha > 0 {
+		ab.Alpha = *alpha
+	}
+
+	report, err := ab.Run(func(seed int64) {
+		logger.Info("seed finished", "seed", seed)
+	})
+	ExpectOk(err)
+	if *resultsOut != "" {
+		bytes, err := json.MarshalIndent(report, "", "\t")
+		ExpectOk(err)
+		ExpectOk(os.WriteFile(*resultsOut, bytes, 0600))
+	}
+
+	fmt.Printf("%s\n%s\n\n", report.A.Name, report.B.Name)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE\tB WINS\tLOSSES\tTIES\tMEAN A\tMEAN B\tSIGN P\tWILCOXON P\tBETTER")
+	for _, outcome := range append(report.Outcomes, report.Overall) {
+		fmt.Fprintf(
+			w,
+			"%s\t%d\t%d\t%d\t%.4g\t%.4g\t%.3g\t%.3g\t%s\n",
+			outcome.Instance,
+			outcome.Wins,
+			outcome.Losses,
+			outcome.Ties,
+			outcome.MeanA,
+			outcome.MeanB,
+			outcome.SignP,
+			outcome.WilcoxonP,
+			outcome.Verdict(ab.Alpha),
+		)
+	}
+	w.Flush()
+}
+
+func runTuneSensitivity(args []string) {
+	flags := flag.NewFlagSet("tune sensitivity", flag.ExitOnError)
+	objective := flags.String("objective", experiment.ObjectiveScore, "what the parameters are judged by: score or time to zero conflicts")
+	timeLimit := flags.Duration("time-limit", 0, "the time limit the runs had; required by -objective time")
+	plotOut := flags.String("plot", "", "draw the main effect plots as SVG to this file")
+	resultsOut := flags.String("out", "", "write the effects as JSON to this file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s tune sensitivity [-objective score|time -time-limit 10s] [-plot effects.svg] results.json\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "results.json holds the runs of a sweep, as written by tune grid -out or a manifest's results file.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *objective == experiment.ObjectiveTime && *timeLimit <= 0 {
+		Fatal("the time objective needs -time-limit to penalize unsolved runs")
+	}
+
+	results, err := experiment.LoadResults(flags.Arg(0))
+	ExpectOk(err)
+	effects, unexplained, err := experiment.Sensitivity(results, *objective, *timeLimit)
+	ExpectOk(err)
+	if *resultsOut != "" {
+		bytes, err := json.MarshalIndent(effects, "", "\t")
+		ExpectOk(err)
+		ExpectOk(os.WriteFile(*resultsOut, bytes, 0600))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PARAMETER\tSHARE\tSPREAD\tLEVEL\tRUNS\tSOLVED\tMEAN COST")
+	for _, effect := range effects {
+		for i, level := range effect.Levels {
+			if i == 0 {
+				fmt.Fprintf(w, "%s\t%.1f%%\t%.4g\t", effect.Parameter, 100*effect.Share, effect.Spread())
+			} else {
+				fmt.Fprint(w, "\t\t\t")
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.4g\n", level.Value, level.Runs, level.Solved, level.MeanCost)
+		}
+	}
+	w.Flush()
+	fmt.Printf("\nunexplained by main effects (interactions and noise): %.1f%%\n", 100*unexplained)
+
+	if *plotOut != "" {
+		var panels []viz.EffectPanel
+		total, runs := 0.0, 0
+		for _, effect := range effects {
+			panel := viz.EffectPanel{Title: fmt.Sprintf("%s (%.0f%%)", effect.Parameter, 100*effect.Share)}
+			for _, level := range effect.Levels {
+				panel.Labels = append(panel.Labels, level.Value)
+				panel.Values = append(panel.Values, level.MeanCost)
+				total += level.MeanCost * float64(level.Runs)
+				runs += level.Runs
+			}
+			panels = append(panels, panel)
+		}
+		ExpectOk(viz.SaveMainEffectsChart(*plotOut, panels, total/float64(max(runs, 1)), *objective))
+		logger.Info("main effect plots saved", "file", *plotOut)
+	}
+}
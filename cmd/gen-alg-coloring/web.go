@@ -0,0 +1,129 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/viz"
+)
+
+//go:embed dashboard.html
+var dashboardPage []byte
+
+type webGraph struct {
+	Name      string
+	NodeCount int
+	Edges     [][2]int
+	Positions []viz.Point
+}
+
+type webProgress struct {
+	NumIterations int
+	History       ga.History
+	Best          ga.Chromosome
+	Finished      bool
+}
+
+type WebDashboard struct {
+	graph         webGraph
+	numIterations int
+
+	mutex    sync.Mutex
+	history  ga.History
+	best     ga.Chromosome
+	finished bool
+}
+
+func NewWebDashboard(g *graph.Graph, name string, numIterations int) *WebDashboard {
+	graph := webGraph{
+		Name:      name,
+		NodeCount: g.NodeCount(),
+		Positions: viz.CircularLayout(g.NodeCount()),
+	}
+	for i, neighbours := range g.AdjecencyList {
+		for _, j := range neighbours {
+			graph.Edges = append(graph.Edges, [2]int{i, j})
+		}
+	}
+
+	return &WebDashboard{
+		graph:         graph,
+		numIterations: numIterations,
+	}
+}
+
+func (dashboard *WebDashboard) Update(stats ga.GenerationStats, best ga.Chromosome) {
+	dashboard.mutex.Lock()
+	defer dashboard.mutex.Unlock()
+
+	dashboard.history = append(dashboard.history, stats)
+	dashboard.best = append(dashboard.best[:0], best...)
+}
+
+func (dashboard *WebDashboard) Finish() {
+	dashboard.mutex.Lock()
+	defer dashboard.mutex.Unlock()
+
+	dashboard.finished = true
+}
+
+func (dashboard *WebDashboard) Notify(event ga.Event) {
+	switch event := event.(type) {
+	case ga.GenerationCompleted:
+		dashboard.Update(event.Stats, event.Best)
+	case ga.Terminated:
+		dashboard.Finish()
+	}
+}
+
+func (dashboard *WebDashboard) Serve(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(dashboardPage)
+	})
+	mux.HandleFunc("/api/graph", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, dashboard.graph)
+	})
+	mux.HandleFunc("/api/progress", func(w http.ResponseWriter, r *http.Request) {
+		since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+		writeJSON(w, dashboard.progress(since))
+	})
+
+	logger.Info("serving dashboard", "url", "http://"+addr)
+	go func() {
+		err := http.ListenAndServe(addr, mux)
+		if err != nil {
+			logger.Warn("dashboard server stopped", "err", err)
+		}
+	}()
+}
+
+func (dashboard *WebDashboard) progress(since int) webProgress {
+	dashboard.mutex.Lock()
+	defer dashboard.mutex.Unlock()
+
+	if since < 0 || since > len(dashboard.history) {
+		since = 0
+	}
+
+	return webProgress{
+		NumIterations: dashboard.numIterations,
+		History:       append(ga.History(nil), dashboard.history[since:]...),
+		Best:          append(ga.Chromosome(nil), dashboard.best...),
+		Finished:      dashboard.finished,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		logger.Warn("failed to write response", "err", err)
+	}
+}
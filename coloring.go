@@ -0,0 +1,60 @@
+// Package coloring is the entry point for programs that embed the solver. It
+// re-exports the types most callers need; package graph, ga, encoding and viz
+// hold the rest, including the operators, solver options and file formats.
+package coloring
+
+import (
+	"context"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+type (
+	Graph      = graph.Graph
+	Coloring   = ga.Chromosome
+	Solver     = ga.GraphColoringSolver
+	Solution   = ga.GraphColoringSolution
+	Option     = ga.Option
+	Selector   = ga.Selector
+	Crossover  = ga.Crossover
+	Mutator    = ga.Mutator
+	Fitness    = ga.Fitness
+	Event      = ga.Event
+	Subscriber = ga.Subscriber
+	Preference = ga.Preference
+)
+
+// Unassigned marks the nodes a precoloring leaves to the solver.
+const Unassigned = ga.Unassigned
+
+func NewGraph(nodeCount int) *Graph {
+	return graph.New(nodeCount)
+}
+
+// LoadGraph reads a graph in DIMACS format.
+func LoadGraph(filename string) (*Graph, error) {
+	return encoding.LoadGraph(filename)
+}
+
+func NewSolver(g *Graph, options ...Option) *Solver {
+	return ga.NewSolver(g, options...)
+}
+
+// Solve runs a solver configured by options to completion on g.
+func Solve(g *Graph, options ...Option) Solution {
+	return NewSolver(g, options...).Solve()
+}
+
+// SolveContext is Solve that stops once ctx is done, returning the best
+// coloring found by then.
+func SolveContext(ctx context.Context, g *Graph, options ...Option) Solution {
+	return NewSolver(g, options...).SolveContext(ctx)
+}
+
+// CountConflicts returns how many edges of g coloring gives both ends the
+// same color; 0 means the coloring is proper.
+func CountConflicts(g *Graph, coloring Coloring) int {
+	return graph.CountConflicts(g, coloring)
+}
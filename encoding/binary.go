@@ -0,0 +1,135 @@
+package encoding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// Binary files start with a magic string, a format version and the kind of
+// value that follows as a gob stream. Bump binaryVersion whenever a change to
+// the encoded types cannot be decoded by older readers.
+const (
+	binaryMagic   = "GAGC"
+	binaryVersion = 2
+)
+
+type binaryKind uint8
+
+const (
+	binaryGraph binaryKind = iota + 1
+	binaryPopulation
+	binaryCheckpoint
+)
+
+func (kind binaryKind) String() string {
+	switch kind {
+	case binaryGraph:
+		return "graph"
+	case binaryPopulation:
+		return "population"
+	case binaryCheckpoint:
+		return "checkpoint"
+	}
+	return fmt.Sprintf("kind %d", uint8(kind))
+}
+
+func IsBinary(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(binaryMagic))
+}
+
+func writeBinary(w io.Writer, kind binaryKind, value interface{}) error {
+	buffered := bufio.NewWriter(w)
+
+	_, err := buffered.WriteString(binaryMagic)
+	if err != nil {
+		return err
+	}
+	_, err = buffered.Write([]byte{binaryVersion, byte(kind)})
+	if err != nil {
+		return err
+	}
+
+	err = gob.NewEncoder(buffered).Encode(value)
+	if err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+func readBinary(r io.Reader, kind binaryKind, value interface{}) error {
+	buffered := bufio.NewReader(r)
+
+	header := make([]byte, len(binaryMagic)+2)
+	_, err := io.ReadFull(buffered, header)
+	if err != nil {
+		return err
+	}
+	if !IsBinary(header) {
+		return fmt.Errorf("not a binary %s file", kind)
+	}
+	version := header[len(binaryMagic)]
+	if version != binaryVersion {
+		return fmt.Errorf("unsupported binary format version %d, expected %d", version, binaryVersion)
+	}
+	actualKind := binaryKind(header[len(binaryMagic)+1])
+	if actualKind != kind {
+		return fmt.Errorf("expected a binary %s file, got %s", kind, actualKind)
+	}
+
+	return gob.NewDecoder(buffered).Decode(value)
+}
+
+func WriteGraph(w io.Writer, g *graph.Graph) error {
+	return writeBinary(w, binaryGraph, g)
+}
+
+func ReadGraph(r io.Reader) (*graph.Graph, error) {
+	stored := graph.Graph{}
+	err := readBinary(r, binaryGraph, &stored)
+	if err != nil {
+		return nil, err
+	}
+	return rebuild(&stored), nil
+}
+
+// rebuild returns a graph with the edges and colors of a decoded one. Only
+// the stored edges are encoded; AddEdge puts back the neighbours.
+func rebuild(stored *graph.Graph) *graph.Graph {
+	g := graph.New(len(stored.AdjecencyList))
+	for i, neighbours := range stored.AdjecencyList {
+		for k, j := range neighbours {
+			g.AddWeightedEdge(i, j, graph.EdgeWeight(stored, i, k))
+		}
+	}
+	copy(g.Colors, stored.Colors)
+	return g
+}
+
+func WritePopulation(w io.Writer, population ga.Population) error {
+	return writeBinary(w, binaryPopulation, population)
+}
+
+func ReadPopulation(r io.Reader) (ga.Population, error) {
+	var population ga.Population
+	err := readBinary(r, binaryPopulation, &population)
+	return population, err
+}
+
+func WriteCheckpoint(w io.Writer, checkpoint *ga.Checkpoint) error {
+	return writeBinary(w, binaryCheckpoint, checkpoint)
+}
+
+func ReadCheckpoint(r io.Reader) (*ga.Checkpoint, error) {
+	checkpoint := ga.Checkpoint{}
+	err := readBinary(r, binaryCheckpoint, &checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
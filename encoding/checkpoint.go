@@ -0,0 +1,59 @@
+package encoding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+// Checkpoints are written in the binary format unless the filename ends in
+// .json; LoadCheckpoint accepts either. Local checkpoints are replaced through
+// a temporary file, objects in one upload.
+func SaveCheckpoint(filename string, checkpoint *ga.Checkpoint) error {
+	var buffer bytes.Buffer
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err := json.NewEncoder(&buffer).Encode(checkpoint)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := WriteCheckpoint(&buffer, checkpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	if storage.IsRemote(filename) {
+		return storage.WriteFile(context.Background(), filename, buffer.Bytes())
+	}
+	tmpFilename := filename + ".tmp"
+	err := os.WriteFile(tmpFilename, buffer.Bytes(), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpFilename, filename)
+}
+
+func LoadCheckpoint(filename string) (*ga.Checkpoint, error) {
+	data, err := storage.ReadFile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsBinary(data) {
+		return ReadCheckpoint(bytes.NewReader(data))
+	}
+
+	checkpoint := ga.Checkpoint{}
+	err = json.Unmarshal(data, &checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
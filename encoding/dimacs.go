@@ -0,0 +1,373 @@
+// Package encoding reads and writes graphs, solutions and solver state in the
+// supported file formats.
+package encoding
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+// LoadGraph reads a DIMACS file, which may also be an s3:// or gs:// URI.
+func LoadGraph(filename string) (*graph.Graph, error) {
+	g, _, err := LoadGraphOptions(filename, DIMACSOptions{})
+	return g, err
+}
+
+// LoadGraphOptions is LoadGraph reading the file as options say, returning
+// what it had to change in the graph as warnings.
+func LoadGraphOptions(filename string, options DIMACSOptions) (*graph.Graph, []string, error) {
+	// Local files are read as they are parsed rather than all at once, which
+	// matters for graphs that barely fit in memory.
+	var r io.Reader
+	if storage.IsRemote(filename) {
+		data, err := storage.ReadFile(context.Background(), filename)
+		if err != nil {
+			return nil, nil, err
+		}
+		r = bytes.NewReader(data)
+	} else {
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	g, warnings, err := ReadDIMACSOptions(r, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return g, warnings, nil
+}
+
+// DIMACSOptions say how to read DIMACS files that stray from the format.
+//
+// Lines ending in CRLF or whitespace, tokens separated by tabs or several
+// spaces, and edges listed before the problem line, or in its absence, are
+// repaired with a warning, unless Strict makes them an error reported with
+// its line and column. ZeroBased numbers the vertices of the file from 0
+// instead of 1. Remap accepts vertex IDs outside of the declared node count:
+// the IDs within it keep their nodes, and the others become new nodes after
+// them, in increasing order of ID. Without it such IDs are an error.
+// MemoryLimit, if set, rejects files whose problem line declares a graph that
+// EstimateMemory puts above it, before reading any edges.
+type DIMACSOptions struct {
+	Strict      bool
+	ZeroBased   bool
+	Remap       bool
+	MemoryLimit uint64
+}
+
+// ReadDIMACS reads a graph in DIMACS edge format, where nodes are numbered
+// from 1. Many files list every edge in both directions; the graph keeps the
+// first of them only. Edge lines may end in a weight, as bandwidth coloring
+// instances have.
+func ReadDIMACS(r io.Reader) (*graph.Graph, error) {
+	g, _, err := ReadDIMACSOptions(r, DIMACSOptions{})
+	return g, err
+}
+
+// ReadDIMACSOptions is ReadDIMACS reading the file as options say, returning
+// what it had to change in the graph as warnings.
+func ReadDIMACSOptions(r io.Reader, options DIMACSOptions) (*graph.Graph, []string, error) {
+	type edge struct {
+		line   int
+		first  int64
+		last   int64
+		weight int
+	}
+	nodeCount := int64(-1)
+	var edges []edge
+	var warnings []string
+	// repairs counts the lines repaired of every kind, in the order
+	// the kinds first occur, and keeps the first line of each.
+	var repaired []string
+	repairs := map[string][2]int{}
+	repair := func(problem string, line int) {
+		counted, exists := repairs[problem]
+		if !exists {
+			repaired = append(repaired, problem)
+			counted[1] = line
+		}
+		counted[0]++
+		repairs[problem] = counted
+	}
+
+	reader := bufio.NewReader(r)
+	for i := 0; ; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		if err == io.EOF && len(line) == 0 {
+			break
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if len(line) == 0 {
+			continue
+		}
+		if trimmed, crlf := strings.CutSuffix(line, "\r"); crlf {
+			if options.Strict {
+				return nil, nil, fmt.Errorf("line %d, column %d: a CRLF line ending", i+1, len(line))
+			}
+			repair("a CRLF line ending", i+1)
+			line = trimmed
+		}
+		column, problem := dimacsProblem(line)
+		if problem != "" {
+			if options.Strict {
+				return nil, nil, fmt.Errorf("line %d, column %d: %s", i+1, column, problem)
+			}
+			repair(problem, i+1)
+		}
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		switch tokens[0] {
+		case "c":
+			continue
+		case "p":
+			if len(tokens) < 3 {
+				return nil, nil, fmt.Errorf("line %d: malformed problem line %q", i+1, line)
+			}
+			nodeCount, err = strconv.ParseInt(tokens[2], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if nodeCount < 0 || nodeCount > math.MaxInt {
+				return nil, nil, fmt.Errorf("line %d: node count %d is outside of 0..%d", i+1, nodeCount, math.MaxInt)
+			}
+			edgeCount := int64(0)
+			if len(tokens) > 3 {
+				edgeCount, err = strconv.ParseInt(tokens[3], 10, 64)
+				if err != nil {
+					return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+				}
+			}
+			if needed := EstimateMemory(nodeCount, edgeCount); options.MemoryLimit > 0 && needed > options.MemoryLimit {
+				return nil, nil, fmt.Errorf(
+					"line %d: %d nodes and %d edges need about %s of memory, more than the %s available",
+					i+1, nodeCount, edgeCount, formatBytes(needed), formatBytes(options.MemoryLimit),
+				)
+			}
+		case "e":
+			if len(tokens) < 3 {
+				return nil, nil, fmt.Errorf("line %d: malformed edge line %q", i+1, line)
+			}
+			first, err := strconv.ParseInt(tokens[1], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			second, err := strconv.ParseInt(tokens[2], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			weight := int64(1)
+			if len(tokens) > 3 {
+				weight, err = strconv.ParseInt(tokens[3], 10, 64)
+				if err != nil {
+					return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+				}
+				if weight < 0 || weight > math.MaxInt32 {
+					return nil, nil, fmt.Errorf("line %d: edge weight %d is outside of 0..%d", i+1, weight, math.MaxInt32)
+				}
+			}
+			if nodeCount < 0 {
+				if options.Strict {
+					return nil, nil, fmt.Errorf("line %d: edge before the problem line", i+1)
+				}
+				repair("an edge before the problem line", i+1)
+			}
+			edges = append(edges, edge{i + 1, first, second, int(weight)})
+		}
+	}
+	for _, problem := range repaired {
+		warnings = append(warnings, fmt.Sprintf("repaired %d lines with %s, the first on line %d", repairs[problem][0], problem, repairs[problem][1]))
+	}
+
+	if nodeCount < 0 && len(edges) > 0 {
+		// A file without a problem line has the nodes up to the largest ID.
+		for _, e := range edges {
+			nodeCount = max(nodeCount, e.first, e.last)
+		}
+		if options.ZeroBased {
+			nodeCount++
+		}
+		warnings = append(warnings, fmt.Sprintf("there is no problem line, so the graph has the %d nodes up to the largest vertex ID", nodeCount))
+	}
+	nodeCount = max(nodeCount, 0)
+	base := int64(1)
+	if options.ZeroBased {
+		base = 0
+	}
+	// node maps the vertex IDs of the file to nodes.
+	node := func(id int64) int { return int(id - base) }
+	var outside []int64
+	for _, e := range edges {
+		for _, id := range []int64{e.first, e.last} {
+			if id >= base && id < base+nodeCount {
+				continue
+			}
+			if !options.Remap {
+				hint := ""
+				switch {
+				case id == 0 && base == 1:
+					hint = "; the file may number vertices from 0"
+				case id == nodeCount && base == 0:
+					hint = "; the file may number vertices from 1"
+				}
+				return nil, nil, fmt.Errorf("line %d: vertex %d is outside of %d..%d%s", e.line, id, base, base+nodeCount-1, hint)
+			}
+			outside = append(outside, id)
+		}
+	}
+
+	if len(outside) > 0 {
+		slices.Sort(outside)
+		outside = slices.Compact(outside)
+		if int64(len(outside)) > math.MaxInt-nodeCount {
+			return nil, nil, fmt.Errorf("%d nodes and %d remapped vertex IDs are more than %d nodes", nodeCount, len(outside), math.MaxInt)
+		}
+		remapped := map[int64]int{}
+		var renames []string
+		for i, id := range outside {
+			remapped[id] = int(nodeCount) + i
+			if i < 10 {
+				renames = append(renames, fmt.Sprintf("%d as %d", id, int64(remapped[id])+base))
+			}
+		}
+		if len(outside) > 10 {
+			renames = append(renames, fmt.Sprintf("and %d more", len(outside)-10))
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"%d vertex IDs are outside of the %d declared nodes %d..%d and were numbered after them: %s",
+			len(outside), nodeCount, base, base+nodeCount-1, strings.Join(renames, ", "),
+		))
+		if slices.Contains(outside, 0) && base == 1 {
+			warnings = append(warnings, "vertex 0 appears, so the file may number vertices from 0")
+		}
+		node = func(id int64) int {
+			if n, exists := remapped[id]; exists {
+				return n
+			}
+			return int(id - base)
+		}
+	}
+
+	// Edges listed twice are found by sorting their indices by the edge they
+	// hold, which takes far less memory than a set of edges, and only the
+	// first of them is added, so the graph keeps the order of the file.
+	key := func(e edge) [2]int {
+		u, v := node(e.first), node(e.last)
+		return [2]int{min(u, v), max(u, v)}
+	}
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		first, second := key(edges[a]), key(edges[b])
+		return cmp.Or(cmp.Compare(first[0], second[0]), cmp.Compare(first[1], second[1]))
+	})
+	duplicate := make([]bool, len(edges))
+	for i := 1; i < len(order); i++ {
+		duplicate[order[i]] = key(edges[order[i]]) == key(edges[order[i-1]])
+	}
+
+	g := graph.New(int(nodeCount) + len(outside))
+	for i, e := range edges {
+		if !duplicate[i] {
+			g.AddWeightedEdge(node(e.first), node(e.last), e.weight)
+		}
+	}
+
+	return g, warnings, nil
+}
+
+// EstimateMemory returns about how many bytes reading a DIMACS file with
+// nodeCount nodes and edgeCount edges takes: every node holds two slice
+// headers and a color, every edge three ints in the graph, with room to
+// grow, and what the reader keeps of it meanwhile. Edge weights take more.
+func EstimateMemory(nodeCount int64, edgeCount int64) uint64 {
+	const perNode, perEdge = 3*8 + 3*8 + 8, 3*8*3/2 + 4*8 + 8 + 1
+	return uint64(max(nodeCount, 0))*perNode + uint64(max(edgeCount, 0))*perEdge
+}
+
+// formatBytes writes bytes in the largest binary unit that keeps it at 1 or
+// more.
+func formatBytes(bytes uint64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	value, unit := float64(bytes), 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+// WriteDIMACS writes g in DIMACS edge format, listing every stored edge once,
+// with its weight if g has weights.
+func WriteDIMACS(w io.Writer, g graph.Interface) error {
+	edgeCount := 0
+	for i := 0; i < g.NodeCount(); i++ {
+		edgeCount += len(g.Edges(i))
+	}
+
+	buffered := bufio.NewWriter(w)
+	fmt.Fprintf(buffered, "p edge %d %d\n", g.NodeCount(), edgeCount)
+	for i := 0; i < g.NodeCount(); i++ {
+		weights := weightsOf(g, i)
+		for k, j := range g.Edges(i) {
+			if weights != nil {
+				fmt.Fprintf(buffered, "e %d %d %d\n", i+1, j+1, weights[k])
+			} else {
+				fmt.Fprintf(buffered, "e %d %d\n", i+1, j+1)
+			}
+		}
+	}
+	return buffered.Flush()
+}
+
+// weightsOf returns the EdgeWeights of node if g is weighted.
+func weightsOf(g graph.Interface, node int) []int {
+	if weighted, ok := g.(graph.Weighted); ok {
+		return weighted.EdgeWeights(node)
+	}
+	return nil
+}
+
+// dimacsProblem returns the first way line, without its line ending, strays
+// from the format, and the column it does so at, counting from 1. Comment
+// lines may hold any whitespace.
+func dimacsProblem(line string) (int, string) {
+	if strings.HasPrefix(strings.TrimLeft(line, " \t"), "c") {
+		return 0, ""
+	}
+	if trimmed := strings.TrimRight(line, " \t"); len(trimmed) < len(line) {
+		return len(trimmed) + 1, "trailing whitespace"
+	}
+	if trimmed := strings.TrimLeft(line, " \t"); len(trimmed) < len(line) {
+		return 1, "leading whitespace"
+	}
+	if i := strings.IndexByte(line, '\t'); i >= 0 {
+		return i + 1, "a tab between tokens"
+	}
+	if i := strings.Index(line, "  "); i >= 0 {
+		return i + 1, "several spaces between tokens"
+	}
+	return 0, ""
+}
@@ -0,0 +1,86 @@
+package encoding
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+func SaveGraphML(filename string, g *graph.Graph) error {
+	var sb strings.Builder
+
+	sb.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	sb.WriteString("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	sb.WriteString("\t<key id=\"color\" for=\"node\" attr.name=\"color\" attr.type=\"int\"/>\n")
+	sb.WriteString("\t<key id=\"conflicts\" for=\"node\" attr.name=\"conflicts\" attr.type=\"int\"/>\n")
+	sb.WriteString("\t<key id=\"degree\" for=\"node\" attr.name=\"degree\" attr.type=\"int\"/>\n")
+	sb.WriteString("\t<key id=\"conflict\" for=\"edge\" attr.name=\"conflict\" attr.type=\"boolean\"/>\n")
+	sb.WriteString("\t<graph id=\"G\" edgedefault=\"undirected\">\n")
+
+	degrees := g.Degrees()
+	conflicts := g.NodeConflicts()
+	for i := 0; i < g.NodeCount(); i++ {
+		sb.WriteString(fmt.Sprintf(
+			"\t\t<node id=\"n%d\"><data key=\"color\">%d</data><data key=\"conflicts\">%d</data><data key=\"degree\">%d</data></node>\n",
+			i, g.Colors[i], conflicts[i], degrees[i],
+		))
+	}
+	for i, neighbours := range g.AdjecencyList {
+		for _, j := range neighbours {
+			sb.WriteString(fmt.Sprintf(
+				"\t\t<edge source=\"n%d\" target=\"n%d\"><data key=\"conflict\">%t</data></edge>\n",
+				i, j, g.Colors[i] == g.Colors[j],
+			))
+		}
+	}
+
+	sb.WriteString("\t</graph>\n</graphml>\n")
+
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}
+
+func SaveGEXF(filename string, g *graph.Graph) error {
+	var sb strings.Builder
+
+	sb.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	sb.WriteString("<gexf xmlns=\"http://gexf.net/1.3\" version=\"1.3\">\n")
+	sb.WriteString("\t<graph defaultedgetype=\"undirected\">\n")
+	sb.WriteString("\t\t<attributes class=\"node\">\n")
+	sb.WriteString("\t\t\t<attribute id=\"color\" title=\"color\" type=\"integer\"/>\n")
+	sb.WriteString("\t\t\t<attribute id=\"conflicts\" title=\"conflicts\" type=\"integer\"/>\n")
+	sb.WriteString("\t\t\t<attribute id=\"degree\" title=\"degree\" type=\"integer\"/>\n")
+	sb.WriteString("\t\t</attributes>\n")
+	sb.WriteString("\t\t<attributes class=\"edge\">\n")
+	sb.WriteString("\t\t\t<attribute id=\"conflict\" title=\"conflict\" type=\"boolean\"/>\n")
+	sb.WriteString("\t\t</attributes>\n")
+
+	degrees := g.Degrees()
+	conflicts := g.NodeConflicts()
+	sb.WriteString("\t\t<nodes>\n")
+	for i := 0; i < g.NodeCount(); i++ {
+		sb.WriteString(fmt.Sprintf(
+			"\t\t\t<node id=\"%d\" label=\"%d\"><attvalues><attvalue for=\"color\" value=\"%d\"/><attvalue for=\"conflicts\" value=\"%d\"/><attvalue for=\"degree\" value=\"%d\"/></attvalues></node>\n",
+			i, i, g.Colors[i], conflicts[i], degrees[i],
+		))
+	}
+	sb.WriteString("\t\t</nodes>\n")
+
+	edgeID := 0
+	sb.WriteString("\t\t<edges>\n")
+	for i, neighbours := range g.AdjecencyList {
+		for _, j := range neighbours {
+			sb.WriteString(fmt.Sprintf(
+				"\t\t\t<edge id=\"%d\" source=\"%d\" target=\"%d\"><attvalues><attvalue for=\"conflict\" value=\"%t\"/></attvalues></edge>\n",
+				edgeID, i, j, g.Colors[i] == g.Colors[j],
+			))
+			edgeID++
+		}
+	}
+	sb.WriteString("\t\t</edges>\n")
+
+	sb.WriteString("\t</graph>\n</gexf>\n")
+
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}
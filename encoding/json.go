@@ -0,0 +1,149 @@
+package encoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+func SaveGraph(filename string, g *graph.Graph) error {
+	bytes, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	err = storage.WriteFile(context.Background(), filename, bytes)
+	return err
+}
+
+// LoadGraphJSON reads a graph SaveGraph wrote.
+func LoadGraphJSON(filename string) (*graph.Graph, error) {
+	bytes, err := storage.ReadFile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := graph.Graph{}
+	err = json.Unmarshal(bytes, &stored)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rebuild(&stored), nil
+}
+
+func SaveSolution(filename string, solution *ga.GraphColoringSolution) error {
+	bytes, err := json.Marshal(solution)
+	if err != nil {
+		return err
+	}
+
+	return storage.WriteFile(context.Background(), filename, bytes)
+}
+
+func LoadSolution(filename string) (*ga.GraphColoringSolution, error) {
+	bytes, err := storage.ReadFile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	solution := ga.GraphColoringSolution{}
+	err = json.Unmarshal(bytes, &solution)
+	if err != nil {
+		return nil, err
+	}
+	return &solution, nil
+}
+
+// LoadPrecoloring reads a partial coloring, either a JSON array holding the
+// color of every node or null for the unassigned ones, or a solution whose
+// Coloring marks them with ga.Unassigned.
+func LoadPrecoloring(filename string) (ga.Chromosome, error) {
+	bytes, err := storage.ReadFile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var colors []*int
+	if err := json.Unmarshal(bytes, &colors); err == nil {
+		precoloring := make(ga.Chromosome, len(colors))
+		for i, color := range colors {
+			precoloring[i] = ga.Unassigned
+			if color != nil {
+				precoloring[i] = *color
+			}
+		}
+		return precoloring, nil
+	}
+	solution := ga.GraphColoringSolution{}
+	err = json.Unmarshal(bytes, &solution)
+	if err != nil {
+		return nil, fmt.Errorf("%s: expected a JSON array of colors or a solution: %w", filename, err)
+	}
+	return solution.Coloring, nil
+}
+
+// LoadPreferences reads soft color preferences, a JSON array of objects with
+// a vertex numbered from 1 as in DIMACS files, a color and a weight, which
+// defaults to 1.
+func LoadPreferences(filename string) ([]ga.Preference, error) {
+	bytes, err := storage.ReadFile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []struct {
+		Vertex int  `json:"vertex"`
+		Color  int  `json:"color"`
+		Weight *int `json:"weight"`
+	}
+	err = json.Unmarshal(bytes, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	preferences := make([]ga.Preference, len(entries))
+	for i, entry := range entries {
+		preferences[i] = ga.Preference{Node: entry.Vertex - 1, Color: entry.Color, Weight: 1}
+		if entry.Weight != nil {
+			preferences[i].Weight = *entry.Weight
+		}
+	}
+	return preferences, nil
+}
+
+func SaveHistory(filename string, history ga.History) error {
+	bytes, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	return storage.WriteFile(context.Background(), filename, bytes)
+}
+
+// LoadConfig reads a solver config from a JSON file, or a YAML one if its
+// name ends in .yaml or .yml.
+func LoadConfig(filename string) (*ga.SolverConfig, error) {
+	bytes, err := storage.ReadFile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	config := ga.SolverConfig{}
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(bytes, &config)
+	default:
+		err = json.Unmarshal(bytes, &config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	return &config, nil
+}
@@ -0,0 +1,145 @@
+package encoding
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+// Table is data laid out for analysis: a row per observation and a column per
+// variable, so that it loads into pandas or R as it is. Every command names
+// the same variable the same way, in snake_case, with times in seconds.
+// Values are int, float64, bool, string or nil for a missing value, each
+// column holding a single type.
+type Table struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// SaveTable writes table as Parquet if filename ends in .parquet, and as CSV
+// otherwise. Parquet columns come in name order, as the format sorts them.
+func SaveTable(filename string, table Table) error {
+	var data []byte
+	var err error
+	if strings.HasSuffix(filename, ".parquet") {
+		data, err = table.parquet()
+	} else {
+		data, err = table.csv()
+	}
+	if err != nil {
+		return err
+	}
+	return storage.WriteFile(context.Background(), filename, data)
+}
+
+func (table Table) csv() ([]byte, error) {
+	buffer := bytes.Buffer{}
+	w := csv.NewWriter(&buffer)
+	w.Write(table.Columns)
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, value := range row {
+			switch value := value.(type) {
+			case nil:
+				record[i] = ""
+			case float64:
+				record[i] = strconv.FormatFloat(value, 'f', -1, 64)
+			default:
+				record[i] = fmt.Sprint(value)
+			}
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return buffer.Bytes(), w.Error()
+}
+
+func (table Table) parquet() ([]byte, error) {
+	group := parquet.Group{}
+	for i, column := range table.Columns {
+		var value any
+		for _, row := range table.Rows {
+			if row[i] != nil {
+				value = row[i]
+				break
+			}
+		}
+		var node parquet.Node
+		switch value.(type) {
+		case int:
+			node = parquet.Int(64)
+		case float64:
+			node = parquet.Leaf(parquet.DoubleType)
+		case bool:
+			node = parquet.Leaf(parquet.BooleanType)
+		default:
+			node = parquet.String()
+		}
+		group[column] = parquet.Optional(node)
+	}
+	schema := parquet.NewSchema("table", group)
+	index := map[string]int{}
+	for i, field := range schema.Fields() {
+		index[field.Name()] = i
+	}
+
+	rows := make([]parquet.Row, len(table.Rows))
+	for r, row := range table.Rows {
+		rows[r] = make(parquet.Row, len(row))
+		for i, value := range row {
+			column := index[table.Columns[i]]
+			if value == nil {
+				rows[r][column] = parquet.NullValue().Level(0, 0, column)
+				continue
+			}
+			if integer, isInt := value.(int); isInt {
+				value = int64(integer)
+			}
+			rows[r][column] = parquet.ValueOf(value).Level(0, 1, column)
+		}
+	}
+
+	buffer := bytes.Buffer{}
+	w := parquet.NewWriter(&buffer, schema)
+	_, err := w.WriteRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	return buffer.Bytes(), err
+}
+
+// HistoryColumns are the columns of HistoryRow.
+var HistoryColumns = []string{"generation", "best", "mean", "worst", "diversity", "evaluations", "elapsed", "mutation_rate", "crossover_rate"}
+
+// HistoryRow is the row of one generation.
+func HistoryRow(stats ga.GenerationStats) []any {
+	return []any{
+		stats.Generation,
+		stats.Best,
+		stats.Mean,
+		stats.Worst,
+		stats.Diversity,
+		stats.Evaluations,
+		stats.Elapsed.Seconds(),
+		stats.MutationRate,
+		stats.CrossoverRate,
+	}
+}
+
+// HistoryTable has a row per generation of history.
+func HistoryTable(history ga.History) Table {
+	table := Table{Columns: HistoryColumns}
+	for _, stats := range history {
+		table.Rows = append(table.Rows, HistoryRow(stats))
+	}
+	return table
+}
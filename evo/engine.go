@@ -0,0 +1,296 @@
+// Package evo is a generational genetic algorithm engine that is generic over
+// the genome type; a problem plugs in its genome, fitness and operators.
+package evo
+
+import (
+	"cmp"
+	"context"
+	"math/rand"
+	"slices"
+)
+
+// Problem creates and scores genomes. Scores are minimized.
+type Problem[G any] interface {
+	RandomGenome(rng *rand.Rand) G
+	Fitness(genome G) int
+}
+
+// Variation breeds children: Select returns the indices of the parents in the
+// population, Recombine builds a new genome from them and Mutate changes that
+// genome in place.
+type Variation[G any] interface {
+	Select(rng *rand.Rand, population []G, scores []int) []int
+	Recombine(rng *rand.Rand, parents []G) G
+	Mutate(rng *rand.Rand, child G) G
+}
+
+// IncrementalProblem is a Problem that can score a child faster knowing its
+// parents and their scores. Engine scores children with ChildFitness instead
+// of Fitness when its problem has it.
+type IncrementalProblem[G any] interface {
+	Problem[G]
+	ChildFitness(parents []G, scores []int, child G) int
+}
+
+// Recycler is a Problem that reuses genomes rather than allocating fresh ones.
+// Engine hands it every genome that leaves the population, and every child
+// that never enters it, at the end of every generation; nothing else refers
+// to them then, as long as no genome is in the population twice.
+type Recycler[G any] interface {
+	Problem[G]
+	Recycle(genome G)
+}
+
+type Scored[G any] struct {
+	Genome G
+	Score  int
+}
+
+// Engine breeds Offspring children per generation and keeps the best PopSize
+// of them, or the best Elitism genomes of the generation before and the best
+// PopSize-Elitism children. Run stops after MaxGenerations generations, once
+// the best score reaches Target or when its context is done. Either way every
+// generation ends with the population sorted by score.
+type Engine[G any] struct {
+	Problem        Problem[G]
+	Variation      Variation[G]
+	Rand           *rand.Rand
+	PopSize        int
+	Offspring      int
+	Elitism        int
+	MaxGenerations int
+	Target         int
+	// Truncation picks the children that survive, SelectTruncation if nil.
+	Truncation Truncation[G]
+	// SteadyState makes every child take the place of the worst genome of
+	// the population as soon as it is bred, see ReplaceWorst, so that the
+	// next child may already descend from it. The best genome then always
+	// survives, and Elitism and Truncation do not apply. Offspring 0 means
+	// PopSize children per generation.
+	SteadyState bool
+	// Crowding, if set, makes every child compete with the genome closest to
+	// it by Distance among its parents and Crowding-1 others drawn at
+	// random, taking its place as soon as it is bred if it is no worse, see
+	// ReplaceNearest. Children then only push out genomes like themselves,
+	// so that the population keeps several niches rather than collapsing
+	// to copies of the best. Otherwise it works like SteadyState, which it
+	// replaces.
+	Crowding int
+	Distance func(a G, b G) int
+
+	OnChild      func(parents []int, child G, score int)
+	OnGeneration func(generation int, population []G, scores []int)
+
+	Population []G
+	Scores     []int
+	Generation int
+
+	// The slices Step fills, kept from one generation to the next.
+	children     []Scored[G]
+	parents      []G
+	parentScores []int
+	dropped      []G
+	candidates   []int
+}
+
+// Init fills the population with random genomes and scores them.
+func (engine *Engine[G]) Init() {
+	engine.Population = make([]G, engine.PopSize)
+	engine.Scores = make([]int, engine.PopSize)
+	for i := range engine.Population {
+		engine.Population[i] = engine.Problem.RandomGenome(engine.Rand)
+	}
+	for i, genome := range engine.Population {
+		engine.Scores[i] = engine.Problem.Fitness(genome)
+	}
+}
+
+func (engine *Engine[G]) Step() {
+	offspring := engine.Offspring
+	if offspring < 1 {
+		offspring = 2 * len(engine.Population)
+		if engine.SteadyState || engine.Crowding > 0 {
+			offspring = len(engine.Population)
+		}
+	}
+
+	incremental, _ := engine.Problem.(IncrementalProblem[G])
+	recycler, _ := engine.Problem.(Recycler[G])
+	children := engine.children[:0]
+	dropped := engine.dropped[:0]
+	for i := 0; i < offspring; i++ {
+		parentIndices := engine.Variation.Select(engine.Rand, engine.Population, engine.Scores)
+		parents, parentScores := engine.parents[:0], engine.parentScores[:0]
+		for _, index := range parentIndices {
+			parents = append(parents, engine.Population[index])
+			parentScores = append(parentScores, engine.Scores[index])
+		}
+		engine.parents, engine.parentScores = parents, parentScores
+		child := engine.Variation.Recombine(engine.Rand, parents)
+		child = engine.Variation.Mutate(engine.Rand, child)
+		var score int
+		if incremental != nil {
+			score = incremental.ChildFitness(parents, parentScores, child)
+		} else {
+			score = engine.Problem.Fitness(child)
+		}
+		if engine.Crowding > 0 {
+			candidates := append(engine.candidates[:0], parentIndices...)
+			for k := 1; k < engine.Crowding; k++ {
+				candidates = append(candidates, engine.Rand.Intn(len(engine.Population)))
+			}
+			engine.candidates = candidates
+		}
+		if engine.OnChild != nil {
+			engine.OnChild(parentIndices, child, score)
+		}
+		if engine.Crowding > 0 {
+			dropped = append(dropped, ReplaceNearest(engine.Population, engine.Scores, engine.candidates, Scored[G]{Genome: child, Score: score}, engine.Distance))
+			continue
+		}
+		if engine.SteadyState {
+			dropped = append(dropped, ReplaceWorst(engine.Population, engine.Scores, Scored[G]{Genome: child, Score: score}))
+			continue
+		}
+		children = append(children, Scored[G]{Genome: child, Score: score})
+	}
+	if engine.SteadyState || engine.Crowding > 0 {
+		for i, genome := range engine.Population {
+			children = append(children, Scored[G]{Genome: genome, Score: engine.Scores[i]})
+		}
+		Truncate(engine.Population, engine.Scores, children)
+	} else {
+		dropped = Replace(engine.Population, engine.Scores, children, engine.Elitism, engine.Truncation, dropped)
+	}
+	engine.children, engine.dropped = children, dropped
+	if recycler != nil {
+		for _, genome := range engine.dropped {
+			recycler.Recycle(genome)
+		}
+	}
+
+	engine.Generation++
+	if engine.OnGeneration != nil {
+		engine.OnGeneration(engine.Generation-1, engine.Population, engine.Scores)
+	}
+}
+
+// Competitors returns the genomes other than its parents that the child last
+// passed to OnChild competes with under Crowding, as indices into the
+// population.
+func (engine *Engine[G]) Competitors() []int {
+	if engine.Crowding <= 0 {
+		return nil
+	}
+	return engine.candidates[len(engine.parents):]
+}
+
+func (engine *Engine[G]) Run(ctx context.Context) {
+	if engine.Population == nil {
+		engine.Init()
+	}
+	for engine.Generation < engine.MaxGenerations && ctx.Err() == nil {
+		engine.Step()
+		if engine.Scores[0] <= engine.Target {
+			break
+		}
+	}
+}
+
+// Replace is Truncate that first sets aside the elitism best genomes of
+// population, as scored by scores, to keep in place of the worst children,
+// picking the children with truncation, or SelectTruncation if it is nil.
+// It appends the genomes it leaves out, old or new, to dropped and returns
+// it.
+func Replace[G any](population []G, scores []int, children []Scored[G], elitism int, truncation Truncation[G], dropped []G) []G {
+	if truncation == nil {
+		truncation = SelectTruncation[G]
+	}
+	elitism = min(elitism, len(population))
+	if elitism == 0 {
+		dropped = append(dropped, population...)
+		truncation(children, len(population))
+		for _, child := range children[len(population):] {
+			dropped = append(dropped, child.Genome)
+		}
+		keep(population, scores, children)
+		return dropped
+	}
+
+	order := make([]int, len(population))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i int, j int) int {
+		return cmp.Compare(scores[i], scores[j])
+	})
+	elite := make([]Scored[G], elitism)
+	for i, index := range order[:elitism] {
+		elite[i] = Scored[G]{Genome: population[index], Score: scores[index]}
+	}
+	for _, index := range order[elitism:] {
+		dropped = append(dropped, population[index])
+	}
+	truncation(children, len(population)-elitism)
+	for _, child := range children[len(population)-elitism:] {
+		dropped = append(dropped, child.Genome)
+	}
+	children = append(children[:len(population)-elitism], elite...)
+	SortTruncation(children, len(children))
+	keep(population, scores, children)
+	return dropped
+}
+
+// ReplaceWorst puts child in the place of the worst genome of population, as
+// scored by scores, or the last of them if several tie, unless child scores
+// worse still. It returns the genome it leaves out, the old one or child.
+func ReplaceWorst[G any](population []G, scores []int, child Scored[G]) G {
+	worst := 0
+	for i, score := range scores {
+		if score >= scores[worst] {
+			worst = i
+		}
+	}
+	if child.Score > scores[worst] {
+		return child.Genome
+	}
+	genome := population[worst]
+	population[worst], scores[worst] = child.Genome, child.Score
+	return genome
+}
+
+// ReplaceNearest puts child in the place of the genome of population closest
+// to it by distance among those at candidates, the first of them if several
+// tie, unless child scores worse than it. It returns the genome it leaves
+// out, the old one or child.
+func ReplaceNearest[G any](population []G, scores []int, candidates []int, child Scored[G], distance func(a G, b G) int) G {
+	nearest, nearestDistance := candidates[0], distance(population[candidates[0]], child.Genome)
+	for _, index := range candidates[1:] {
+		if d := distance(population[index], child.Genome); d < nearestDistance {
+			nearest, nearestDistance = index, d
+		}
+	}
+	if child.Score > scores[nearest] {
+		return child.Genome
+	}
+	genome := population[nearest]
+	population[nearest], scores[nearest] = child.Genome, child.Score
+	return genome
+}
+
+// Truncate sorts children by score, ties keeping their order, and replaces
+// population with the best len(population) of them. scores may be nil.
+func Truncate[G any](population []G, scores []int, children []Scored[G]) {
+	SelectTruncation(children, len(population))
+	keep(population, scores, children)
+}
+
+// keep copies the first len(population) children into population and scores.
+func keep[G any](population []G, scores []int, children []Scored[G]) {
+	for i := range population {
+		population[i] = children[i].Genome
+		if scores != nil {
+			scores[i] = children[i].Score
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package evo
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Truncation moves the best k children to the front of children, sorted by
+// score with tied children in the order they were bred, and leaves the
+// others after them in any order. As ties are broken the same way, every
+// Truncation keeps the same children in the same order and they differ only
+// in speed.
+type Truncation[G any] func(children []Scored[G], k int)
+
+// SortTruncation sorts all of children.
+func SortTruncation[G any](children []Scored[G], k int) {
+	slices.SortStableFunc(children, compareScored[G])
+}
+
+// SelectTruncation finds the score of the k-th best child by quickselect and
+// only sorts the children that beat or tie it, which saves about half of the
+// sort when there are twice as many children as survivors.
+func SelectTruncation[G any](children []Scored[G], k int) {
+	if k <= 0 {
+		return
+	}
+	if k >= len(children) {
+		SortTruncation(children, k)
+		return
+	}
+
+	scores := make([]int, len(children))
+	for i, child := range children {
+		scores[i] = child.Score
+	}
+	threshold := nthSmallest(scores, k-1)
+	ties := k
+	for _, child := range children {
+		if child.Score < threshold {
+			ties--
+		}
+	}
+	// Moving every survivor to the next free place in turn keeps the
+	// survivors in order.
+	next := 0
+	for i, child := range children {
+		if child.Score > threshold || (child.Score == threshold && ties == 0) {
+			continue
+		}
+		if child.Score == threshold {
+			ties--
+		}
+		children[next], children[i] = children[i], children[next]
+		next++
+	}
+	slices.SortStableFunc(children[:k], compareScored[G])
+}
+
+func compareScored[G any](a Scored[G], b Scored[G]) int {
+	return cmp.Compare(a.Score, b.Score)
+}
+
+// nthSmallest returns the value values would have at index n if sorted,
+// reordering values as it goes.
+func nthSmallest(values []int, n int) int {
+	low, high := 0, len(values)-1
+	for low < high {
+		// Hoare partition around the middle value, which keeps sorted and
+		// nearly sorted scores, as populations often are, linear.
+		pivot := values[low+(high-low)/2]
+		i, j := low, high
+		for i <= j {
+			for values[i] < pivot {
+				i++
+			}
+			for values[j] > pivot {
+				j--
+			}
+			if i <= j {
+				values[i], values[j] = values[j], values[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case n <= j:
+			high = j
+		case n >= i:
+			low = i
+		default:
+			return values[n]
+		}
+	}
+	return values[n]
+}
@@ -0,0 +1,91 @@
+package evo
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// testChildren returns n children with scores below maxScore, so that small
+// maxScores give many ties, and genomes numbering them in breed order.
+func testChildren(rng *rand.Rand, n int, maxScore int) []Scored[int] {
+	children := make([]Scored[int], n)
+	for i := range children {
+		children[i] = Scored[int]{Genome: i, Score: rng.Intn(maxScore)}
+	}
+	return children
+}
+
+func TestNthSmallestMatchesSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 10, 101, 400} {
+		for _, maxScore := range []int{1, 3, 1000} {
+			values := make([]int, n)
+			for i := range values {
+				values[i] = rng.Intn(maxScore)
+			}
+			sorted := slices.Sorted(slices.Values(values))
+			for k := range n {
+				if got := nthSmallest(slices.Clone(values), k); got != sorted[k] {
+					t.Fatalf("nthSmallest(%v, %d) = %d, want %d", values, k, got, sorted[k])
+				}
+			}
+		}
+	}
+}
+
+// SelectTruncation must keep the same children in the same order as
+// SortTruncation, which the Truncation contract promises.
+func TestSelectTruncationMatchesSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 5, 64, 400} {
+		for _, maxScore := range []int{1, 3, 1000} {
+			for _, k := range []int{0, 1, n / 3, n / 2, n - 1, n, n + 1} {
+				if k < 0 {
+					continue
+				}
+				children := testChildren(rng, n, maxScore)
+				want := slices.Clone(children)
+				SortTruncation(want, k)
+				got := slices.Clone(children)
+				SelectTruncation(got, k)
+
+				kept := min(k, n)
+				if !slices.Equal(got[:kept], want[:kept]) {
+					t.Fatalf("n=%d k=%d maxScore=%d: kept %v, want %v", n, k, maxScore, got[:kept], want[:kept])
+				}
+				left := func(children []Scored[int]) []int {
+					var genomes []int
+					for _, child := range children[kept:] {
+						genomes = append(genomes, child.Genome)
+					}
+					slices.Sort(genomes)
+					return genomes
+				}
+				if !slices.Equal(left(got), left(want)) {
+					t.Fatalf("n=%d k=%d maxScore=%d: left out %v, want %v", n, k, maxScore, left(got), left(want))
+				}
+			}
+		}
+	}
+}
+
+func benchmarkTruncation(b *testing.B, truncation Truncation[int]) {
+	rng := rand.New(rand.NewSource(1))
+	// A generation of 400 children of which 200 survive, as with the default
+	// population size.
+	children := testChildren(rng, 400, 10000)
+	work := make([]Scored[int], len(children))
+	for b.Loop() {
+		copy(work, children)
+		truncation(work, 200)
+	}
+}
+
+func BenchmarkSortTruncation(b *testing.B) {
+	benchmarkTruncation(b, SortTruncation[int])
+}
+
+func BenchmarkSelectTruncation(b *testing.B) {
+	benchmarkTruncation(b, SelectTruncation[int])
+}
@@ -0,0 +1,173 @@
+package experiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+// AB compares configuration B against the baseline A, both given as changes
+// to Base, to tell whether a single change such as another operator helps.
+// Both run on every instance with every seed, side by side, so that each run
+// of B has a run of A on the same instance and seed to be measured against.
+// Objective and TimeLimit are as for a search; Alpha, 0.05 by default, is the
+// significance level of the verdicts.
+type AB struct {
+	Instances   []string                   `json:"instances"`
+	Seeds       []int64                    `json:"seeds"`
+	Parallelism int                        `json:"parallelism,omitempty"`
+	Base        ga.SolverConfig            `json:"base"`
+	A           map[string]json.RawMessage `json:"a"`
+	B           map[string]json.RawMessage `json:"b"`
+	Objective   string                     `json:"objective,omitempty"`
+	TimeLimit   string                     `json:"time_limit,omitempty"`
+	Alpha       float64                    `json:"alpha,omitempty"`
+}
+
+func LoadAB(filename string) (*AB, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	ab := AB{Objective: ObjectiveScore, Alpha: 0.05}
+	err = json.Unmarshal(data, &ab)
+	if err != nil {
+		return nil, err
+	}
+	for i, instance := range ab.Instances {
+		ab.Instances[i] = relativeTo(filename, instance)
+	}
+	return &ab, nil
+}
+
+// Outcome is how B fared against A over pairs of runs: Wins counts the pairs
+// where B had the lower cost, Losses those where A had. SignP and WilcoxonP
+// are the two-sided p-values of the sign test and of the Wilcoxon signed-rank
+// test of the hypothesis that neither is better.
+type Outcome struct {
+	Instance  string
+	Wins      int
+	Losses    int
+	Ties      int
+	MeanA     float64
+	MeanB     float64
+	SignP     float64
+	WilcoxonP float64
+}
+
+// Verdict names the better configuration if the Wilcoxon test finds a
+// difference at alpha, and is "tie" otherwise.
+func (outcome Outcome) Verdict(alpha float64) string {
+	switch {
+	case outcome.WilcoxonP >= alpha:
+		return "tie"
+	case outcome.MeanB < outcome.MeanA || (outcome.MeanB == outcome.MeanA && outcome.Wins > outcome.Losses):
+		return "B"
+	}
+	return "A"
+}
+
+func compare(instance string, a []float64, b []float64) Outcome {
+	outcome := Outcome{Instance: instance}
+	differences := make([]float64, len(a))
+	for i := range a {
+		differences[i] = a[i] - b[i]
+		switch {
+		case b[i] < a[i]:
+			outcome.Wins++
+		case b[i] > a[i]:
+			outcome.Losses++
+		default:
+			outcome.Ties++
+		}
+	}
+	outcome.MeanA, _ = meanStdDev(a)
+	outcome.MeanB, _ = meanStdDev(b)
+	outcome.SignP = signTest(outcome.Wins, outcome.Losses)
+	outcome.WilcoxonP = wilcoxon(differences)
+	return outcome
+}
+
+// ABReport holds the outcome on every instance, over all pairs, and the runs
+// themselves, A and B alternating for every instance and seed.
+type ABReport struct {
+	A        Configuration
+	B        Configuration
+	Outcomes []Outcome
+	Overall  Outcome
+	Results  []Result
+}
+
+// Run runs one seed after another on every instance, passing the seed to
+// progress once both configurations are done with it.
+func (ab *AB) Run(progress func(seed int64)) (*ABReport, error) {
+	timeLimit, err := checkObjective(ab.Objective, ab.TimeLimit)
+	if err != nil {
+		return nil, err
+	}
+	if ab.Alpha <= 0 || ab.Alpha >= 1 {
+		return nil, fmt.Errorf("alpha must be between 0 and 1, got %g", ab.Alpha)
+	}
+	report := ABReport{}
+	for _, side := range []struct {
+		name    string
+		changes map[string]json.RawMessage
+		into    *Configuration
+	}{{"A", ab.A, &report.A}, {"B", ab.B, &report.B}} {
+		names := make([]string, 0, len(side.changes))
+		for name := range side.changes {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		values := make([]json.RawMessage, len(names))
+		for i, name := range names {
+			values[i] = side.changes[name]
+		}
+		*side.into, err = configure(ab.Base, names, values)
+		if err != nil {
+			return nil, err
+		}
+		side.into.Name = side.name + ": " + side.into.Name
+		if len(names) == 0 {
+			side.into.Name = side.name + ": base"
+		}
+	}
+
+	costs := make([][2][]float64, len(ab.Instances))
+	for _, seed := range ab.Seeds {
+		// Every instance takes A and B in turn, so that both meet the same load.
+		experiment := Experiment{
+			Instances:      ab.Instances,
+			Configurations: []Configuration{report.A, report.B},
+			Seeds:          []int64{seed},
+			Parallelism:    ab.Parallelism,
+			TimeLimit:      timeLimit,
+		}
+		results, err := experiment.Run()
+		if err != nil {
+			return nil, err
+		}
+		for i := range ab.Instances {
+			for side := 0; side < 2; side++ {
+				costs[i][side] = append(costs[i][side], cost(ab.Objective, results[2*i+side:2*i+side+1], timeLimit))
+			}
+		}
+		report.Results = append(report.Results, results...)
+		if progress != nil {
+			progress(seed)
+		}
+	}
+
+	var all [2][]float64
+	for i, instance := range ab.Instances {
+		report.Outcomes = append(report.Outcomes, compare(InstanceName(instance), costs[i][0], costs[i][1]))
+		all[0] = append(all[0], costs[i][0]...)
+		all[1] = append(all[1], costs[i][1]...)
+	}
+	report.Overall = compare("all", all[0], all[1])
+	return &report, nil
+}
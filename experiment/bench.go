@@ -0,0 +1,170 @@
+package experiment
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// BestKnown holds the fewest colors a published coloring of each DIMACS
+// instance uses, by instance name. Most of them are chromatic numbers.
+var BestKnown = map[string]int{
+	"1-FullIns_3": 4, "2-FullIns_3": 5, "3-FullIns_3": 6, "4-FullIns_3": 7, "5-FullIns_3": 8,
+	"1-Insertions_4": 5, "2-Insertions_3": 4, "3-Insertions_3": 4,
+	"anna": 11, "david": 11, "homer": 13, "huck": 11, "jean": 10,
+	"games120": 9,
+	"miles250": 8, "miles500": 20, "miles750": 31, "miles1000": 42, "miles1500": 73,
+	"fpsol2.i.1": 65, "fpsol2.i.2": 30, "fpsol2.i.3": 30,
+	"inithx.i.1": 54, "inithx.i.2": 31, "inithx.i.3": 31,
+	"mulsol.i.1": 49, "mulsol.i.2": 31, "mulsol.i.3": 31, "mulsol.i.4": 31, "mulsol.i.5": 31,
+	"zeroin.i.1": 49, "zeroin.i.2": 30, "zeroin.i.3": 30,
+	"myciel3": 4, "myciel4": 5, "myciel5": 6, "myciel6": 7, "myciel7": 8,
+	"mug88_1": 4, "mug88_25": 4, "mug100_1": 4, "mug100_25": 4,
+	"queen5_5": 5, "queen6_6": 7, "queen7_7": 7, "queen8_8": 9, "queen8_12": 12,
+	"queen9_9": 10, "queen10_10": 11, "queen11_11": 11, "queen12_12": 12, "queen13_13": 13,
+	"school1": 14, "school1_nsh": 14,
+	"le450_5a": 5, "le450_5b": 5, "le450_5c": 5, "le450_5d": 5,
+	"le450_15a": 15, "le450_15b": 15, "le450_15c": 15, "le450_15d": 15,
+	"le450_25a": 25, "le450_25b": 25, "le450_25c": 25, "le450_25d": 25,
+	"DSJC125.1": 5, "DSJC125.5": 17, "DSJC125.9": 44,
+	"DSJC250.1": 8, "DSJC250.5": 28, "DSJC250.9": 72,
+	"DSJC500.1": 12, "DSJC500.5": 47, "DSJC500.9": 126,
+	"DSJC1000.1": 20, "DSJC1000.5": 82, "DSJC1000.9": 222,
+	"DSJR500.1": 12, "DSJR500.1c": 85, "DSJR500.5": 122,
+	"R125.1": 5, "R125.1c": 46, "R125.5": 36,
+	"R250.1": 8, "R250.1c": 64, "R250.5": 65,
+	"R1000.1": 20, "R1000.1c": 98, "R1000.5": 234,
+	"flat300_20_0": 20, "flat300_26_0": 26, "flat300_28_0": 28,
+	"flat1000_50_0": 50, "flat1000_60_0": 60, "flat1000_76_0": 81,
+	"latin_square_10": 97,
+}
+
+// QuickSuite lists small instances of BestKnown that a health check can run
+// in minutes.
+var QuickSuite = []string{
+	"myciel3", "myciel4", "myciel5", "queen5_5", "queen6_6", "queen7_7",
+	"anna", "david", "huck", "jean", "games120", "miles250",
+	"1-FullIns_3", "2-Insertions_3", "mug88_1", "DSJC125.1",
+}
+
+// Bench finds the fewest colors the solver, set up as Base, can color each
+// of Instances with. Starting from a greedy coloring it retries with one
+// color less for as long as one of Seeds succeeds, and stops early at the
+// best known count. TimeLimit, if set, stops every attempt.
+type Bench struct {
+	Instances   []string
+	Base        ga.SolverConfig
+	Seeds       []int64
+	Parallelism int
+	TimeLimit   time.Duration
+}
+
+// BenchResult is what a bench achieved on one instance. BestKnown is 0 for
+// instances missing from the table.
+type BenchResult struct {
+	Instance  string
+	Nodes     int
+	BestKnown int
+	Greedy    int
+	Colors    int
+	Attempts  int
+	Elapsed   time.Duration
+	Err       string `json:",omitempty"`
+}
+
+// Gap is how many colors above the best known result the bench ended, or -1
+// if that is not known.
+func (result BenchResult) Gap() int {
+	if result.BestKnown == 0 {
+		return -1
+	}
+	return result.Colors - result.BestKnown
+}
+
+// Run benches the instances in parallel, passing every result to progress as
+// it is done, and returns the results in instance order.
+func (bench *Bench) Run(progress func(BenchResult)) []BenchResult {
+	parallelism := bench.Parallelism
+	if parallelism < 1 {
+		parallelism = runtime.NumCPU()
+	}
+
+	results := make([]BenchResult, len(bench.Instances))
+	var mutex sync.Mutex
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = bench.instance(bench.Instances[i])
+				if progress != nil {
+					mutex.Lock()
+					progress(results[i])
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range bench.Instances {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+func (bench *Bench) instance(filename string) BenchResult {
+	started := time.Now()
+	name := InstanceName(filename)
+	result := BenchResult{Instance: name, BestKnown: BestKnown[name]}
+	g, err := encoding.LoadGraph(filename)
+	if err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	result.Nodes = g.NodeCount()
+	for _, color := range graph.Greedy(g) {
+		result.Greedy = max(result.Greedy, color+1)
+	}
+
+	result.Colors = result.Greedy
+	for colors := result.Greedy - 1; colors >= 1 && colors >= result.BestKnown; colors-- {
+		solved, err := bench.attempt(g, colors, &result)
+		if err != nil {
+			result.Err = err.Error()
+			break
+		}
+		if !solved {
+			break
+		}
+		result.Colors = colors
+	}
+	result.Elapsed = time.Since(started)
+	return result
+}
+
+// attempt reports whether one of the seeds colors g with colors colors. The
+// coloring is checked edge by edge, independently of the fitness in use.
+func (bench *Bench) attempt(g *graph.Graph, colors int, result *BenchResult) (bool, error) {
+	config := bench.Base
+	config.Colors = colors
+	for _, seed := range bench.Seeds {
+		config.Seed = seed
+		options, err := config.Options()
+		if err != nil {
+			return false, err
+		}
+		result.Attempts++
+		solution := solve(ga.NewSolver(g, options...), bench.TimeLimit, time.Time{})
+		if solution.Score == 0 && graph.CountConflicts(g, solution.Coloring) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
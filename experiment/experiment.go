@@ -0,0 +1,417 @@
+// Package experiment runs every combination of instances, solver
+// configurations and seeds, in parallel, and aggregates the results.
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+// Configuration is one way of setting up the solver. Zero fields keep the
+// solver defaults and the seed comes from the experiment, overriding any seed
+// in the config. Options are applied after the config and cannot be read from
+// a manifest.
+type Configuration struct {
+	Name    string      `json:"name"`
+	Options []ga.Option `json:"-"`
+
+	ga.SolverConfig
+}
+
+func (configuration *Configuration) options(seed int64) ([]ga.Option, error) {
+	options, err := configuration.SolverConfig.Options()
+	if err != nil {
+		return nil, fmt.Errorf("configuration %q: %w", configuration.Name, err)
+	}
+	options = append(options, ga.WithSeed(seed))
+	return append(options, configuration.Options...), nil
+}
+
+// Experiment solves every instance with every configuration once per seed.
+// Instances are DIMACS files; Parallelism defaults to the number of CPUs.
+// TimeLimit, if set, stops every run after that long with its best solution,
+// and Deadline stops every run still going at that time. KeepHistory keeps
+// the statistics of every generation in the results.
+//
+// Results names a JSON Lines file that every finished run is appended to.
+// Runs already in it, by instance, configuration name and seed, are not run
+// again, so an interrupted experiment continues where it stopped. Solutions
+// names a directory to save the solution of every run to, and Summary a CSV
+// file for the summaries of the results.
+type Experiment struct {
+	Instances      []string        `json:"instances"`
+	Configurations []Configuration `json:"configurations"`
+	Seeds          []int64         `json:"seeds"`
+	Parallelism    int             `json:"parallelism,omitempty"`
+	TimeLimit      time.Duration   `json:"-"`
+	Deadline       time.Time       `json:"-"`
+	KeepHistory    bool            `json:"-"`
+	Results        string          `json:"results,omitempty"`
+	Solutions      string          `json:"solutions,omitempty"`
+	Summary        string          `json:"summary,omitempty"`
+}
+
+// LoadManifest reads an experiment from a JSON manifest, in which time_limit
+// is a duration such as "30s".
+func LoadManifest(filename string) (*Experiment, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := struct {
+		Experiment
+		TimeLimit string `json:"time_limit"`
+	}{}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, err
+	}
+	experiment := manifest.Experiment
+	if manifest.TimeLimit != "" {
+		experiment.TimeLimit, err = time.ParseDuration(manifest.TimeLimit)
+		if err != nil {
+			return nil, fmt.Errorf("time_limit: %w", err)
+		}
+	}
+
+	// Instances and outputs are relative to the manifest, not to the working
+	// directory.
+	for i := range experiment.Instances {
+		experiment.Instances[i] = relativeTo(filename, experiment.Instances[i])
+	}
+	for _, output := range []*string{&experiment.Results, &experiment.Solutions, &experiment.Summary} {
+		if *output != "" {
+			*output = relativeTo(filename, *output)
+		}
+	}
+	return &experiment, nil
+}
+
+func relativeTo(manifest string, name string) string {
+	if filepath.IsAbs(name) || storage.IsRemote(name) {
+		return name
+	}
+	return filepath.Join(filepath.Dir(manifest), name)
+}
+
+// Result is one run. History is kept only if the experiment asks for it, and
+// is not saved to the results file.
+type Result struct {
+	Instance      string
+	Configuration string
+	Seed          int64
+	Solution      ga.GraphColoringSolution
+	History       ga.History `json:"-"`
+}
+
+func InstanceName(filename string) string {
+	return strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+}
+
+type cell struct {
+	index         int
+	instance      int
+	configuration int
+	seed          int64
+}
+
+// runKey identifies a run in the results file.
+type runKey struct {
+	instance      string
+	configuration string
+	seed          int64
+}
+
+// completed reads the runs already in the results file. A last line cut short
+// by an interruption is ignored.
+func (experiment *Experiment) completed() (map[runKey]Result, error) {
+	done := map[runKey]Result{}
+	if experiment.Results == "" {
+		return done, nil
+	}
+	data, err := os.ReadFile(experiment.Results)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		result := Result{}
+		err := json.Unmarshal([]byte(line), &result)
+		if err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("%s:%d: %w", experiment.Results, i+1, err)
+		}
+		done[runKey{result.Instance, result.Configuration, result.Seed}] = result
+	}
+	return done, nil
+}
+
+// Remaining returns the number of runs the experiment still has to make.
+func (experiment *Experiment) Remaining() (int, error) {
+	done, err := experiment.completed()
+	if err != nil {
+		return 0, err
+	}
+	remaining := 0
+	for _, instance := range experiment.Instances {
+		for _, configuration := range experiment.Configurations {
+			for _, seed := range experiment.Seeds {
+				if _, exists := done[runKey{InstanceName(instance), configuration.Name, seed}]; !exists {
+					remaining++
+				}
+			}
+		}
+	}
+	return remaining, nil
+}
+
+// record appends result to the results file and saves its solution.
+func (experiment *Experiment) record(results *os.File, result Result) error {
+	if experiment.Solutions != "" {
+		name := fmt.Sprintf("%s-%s-%d.json", result.Instance, result.Configuration, result.Seed)
+		name = strings.Map(func(r rune) rune {
+			if r == '/' || r == ' ' || r == '"' {
+				return '_'
+			}
+			return r
+		}, name)
+		filename := filepath.Join(experiment.Solutions, name)
+		if storage.IsRemote(experiment.Solutions) {
+			filename = strings.TrimSuffix(experiment.Solutions, "/") + "/" + name
+		}
+		err := encoding.SaveSolution(filename, &result.Solution)
+		if err != nil {
+			return err
+		}
+	}
+	if results == nil {
+		return nil
+	}
+	line, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = results.Write(append(line, '\n'))
+	return err
+}
+
+// Run loads every instance once and shares it between all runs on it. Results
+// come back in instance, configuration, seed order regardless of which run
+// finishes first, including those read back from the results file.
+func (experiment *Experiment) Run() ([]Result, error) {
+	if len(experiment.Instances) == 0 || len(experiment.Configurations) == 0 || len(experiment.Seeds) == 0 {
+		return nil, errors.New("experiment needs at least one instance, configuration and seed")
+	}
+
+	for i := range experiment.Configurations {
+		_, err := experiment.Configurations[i].options(0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	done, err := experiment.completed()
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	var cells []cell
+	for i, instance := range experiment.Instances {
+		for j, configuration := range experiment.Configurations {
+			for _, seed := range experiment.Seeds {
+				result, exists := done[runKey{InstanceName(instance), configuration.Name, seed}]
+				if !exists {
+					cells = append(cells, cell{index: len(results), instance: i, configuration: j, seed: seed})
+				}
+				results = append(results, result)
+			}
+		}
+	}
+
+	// Only instances with runs left are loaded, each into one compact copy
+	// that all of its runs share.
+	graphs := make([]graph.Interface, len(experiment.Instances))
+	for _, c := range cells {
+		if graphs[c.instance] != nil {
+			continue
+		}
+		g, err := encoding.LoadGraph(experiment.Instances[c.instance])
+		if err != nil {
+			return nil, err
+		}
+		graphs[c.instance] = graph.Compact(g)
+	}
+
+	var journal *os.File
+	if experiment.Results != "" && len(cells) > 0 {
+		journal, err = os.OpenFile(experiment.Results, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, err
+		}
+		defer journal.Close()
+	}
+	if experiment.Solutions != "" && !storage.IsRemote(experiment.Solutions) {
+		err = os.MkdirAll(experiment.Solutions, 0700)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var mutex sync.Mutex
+	var recordErr error
+
+	parallelism := experiment.Parallelism
+	if parallelism < 1 {
+		parallelism = runtime.NumCPU()
+	}
+
+	queue := make(chan cell)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range queue {
+				configuration := &experiment.Configurations[c.configuration]
+				// Every run gets fresh operators; the names were checked above.
+				options, _ := configuration.options(c.seed)
+				solver := ga.NewSolver(graphs[c.instance], options...)
+				solution := solve(solver, experiment.TimeLimit, experiment.Deadline)
+				solution.Metadata.Instance = InstanceName(experiment.Instances[c.instance])
+				result := Result{
+					Instance:      solution.Metadata.Instance,
+					Configuration: configuration.Name,
+					Seed:          c.seed,
+					Solution:      solution,
+				}
+				if experiment.KeepHistory {
+					result.History = solver.History
+				}
+				results[c.index] = result
+				mutex.Lock()
+				if recordErr == nil {
+					recordErr = experiment.record(journal, result)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	for _, c := range cells {
+		queue <- c
+	}
+	close(queue)
+	wg.Wait()
+
+	return results, recordErr
+}
+
+// solve runs solver, for at most timeLimit and until deadline if those are
+// set.
+func solve(solver *ga.GraphColoringSolver, timeLimit time.Duration, deadline time.Time) ga.GraphColoringSolution {
+	ctx := context.Background()
+	if timeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeLimit)
+		defer cancel()
+	}
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	return solver.SolveContext(ctx)
+}
+
+// Summary aggregates the runs of one configuration on one instance. A run is
+// solved when it ends without conflicts.
+type Summary struct {
+	Instance      string
+	Configuration string
+	Runs          int
+	Solved        int
+	BestScore     int
+	MeanScore     float64
+	MeanElapsed   time.Duration
+}
+
+func Summarize(results []Result) []Summary {
+	var summaries []Summary
+	index := make(map[[2]string]int)
+	for _, result := range results {
+		key := [2]string{result.Instance, result.Configuration}
+		i, exists := index[key]
+		if !exists {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, Summary{
+				Instance:      result.Instance,
+				Configuration: result.Configuration,
+				BestScore:     result.Solution.Score,
+			})
+		}
+
+		summary := &summaries[i]
+		summary.Runs++
+		if result.Solution.Score == 0 {
+			summary.Solved++
+		}
+		if result.Solution.Score < summary.BestScore {
+			summary.BestScore = result.Solution.Score
+		}
+		summary.MeanScore += float64(result.Solution.Score)
+		summary.MeanElapsed += result.Solution.Metadata.Elapsed
+	}
+
+	for i := range summaries {
+		summaries[i].MeanScore /= float64(summaries[i].Runs)
+		summaries[i].MeanElapsed /= time.Duration(summaries[i].Runs)
+	}
+	return summaries
+}
+
+// SaveSummaries writes summaries as CSV, with times in seconds.
+func SaveSummaries(filename string, summaries []Summary) error {
+	buffer := bytes.Buffer{}
+	w := csv.NewWriter(&buffer)
+	w.Write([]string{"instance", "configuration", "runs", "solved", "best", "mean", "mean_elapsed"})
+	for _, summary := range summaries {
+		w.Write([]string{
+			summary.Instance,
+			summary.Configuration,
+			strconv.Itoa(summary.Runs),
+			strconv.Itoa(summary.Solved),
+			strconv.Itoa(summary.BestScore),
+			strconv.FormatFloat(summary.MeanScore, 'f', -1, 64),
+			strconv.FormatFloat(summary.MeanElapsed.Seconds(), 'f', 6, 64),
+		})
+	}
+	w.Flush()
+	if w.Error() != nil {
+		return w.Error()
+	}
+	return storage.WriteFile(context.Background(), filename, buffer.Bytes())
+}
@@ -0,0 +1,190 @@
+package experiment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+// Grid sweeps solver parameters: every combination of the values listed in
+// Parameters, keyed by their name in the JSON solver config, is applied on
+// top of Base and run on every instance with every seed.
+type Grid struct {
+	Instances   []string                     `json:"instances"`
+	Seeds       []int64                      `json:"seeds"`
+	Parallelism int                          `json:"parallelism,omitempty"`
+	Base        ga.SolverConfig              `json:"base"`
+	Parameters  map[string][]json.RawMessage `json:"parameters"`
+}
+
+func LoadGrid(filename string) (*Grid, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := Grid{}
+	err = json.Unmarshal(data, &grid)
+	if err != nil {
+		return nil, err
+	}
+	for i, instance := range grid.Instances {
+		grid.Instances[i] = relativeTo(filename, instance)
+	}
+	return &grid, nil
+}
+
+// Experiment returns the experiment running every combination of the grid as
+// a configuration named after its parameter values.
+func (grid *Grid) Experiment() (*Experiment, error) {
+	names := make([]string, 0, len(grid.Parameters))
+	for name, values := range grid.Parameters {
+		if name == "seed" {
+			return nil, errors.New("the seeds of a grid are listed under seeds, not parameters")
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("parameter %q has no values", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	experiment := &Experiment{Instances: grid.Instances, Seeds: grid.Seeds, Parallelism: grid.Parallelism}
+	// indices counts through the combinations like an odometer.
+	indices := make([]int, len(names))
+	values := make([]json.RawMessage, len(names))
+	for {
+		for i, name := range names {
+			values[i] = grid.Parameters[name][indices[i]]
+		}
+		configuration, err := configure(grid.Base, names, values)
+		if err != nil {
+			return nil, err
+		}
+		experiment.Configurations = append(experiment.Configurations, configuration)
+
+		i := len(indices) - 1
+		for ; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(grid.Parameters[names[i]]) {
+				break
+			}
+			indices[i] = 0
+		}
+		if i < 0 {
+			return experiment, nil
+		}
+	}
+}
+
+// configure returns base with the JSON config fields names set to values, as
+// a configuration named after them.
+func configure(base ga.SolverConfig, names []string, values []json.RawMessage) (Configuration, error) {
+	data, err := json.Marshal(base)
+	if err != nil {
+		return Configuration{}, err
+	}
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return Configuration{}, err
+	}
+	labels := make([]string, len(names))
+	for i, name := range names {
+		fields[name] = values[i]
+		compact := bytes.Buffer{}
+		err = json.Compact(&compact, values[i])
+		if err != nil {
+			return Configuration{}, fmt.Errorf("%s: %w", name, err)
+		}
+		label := compact.String()
+		// Operator names read better without their quotes.
+		_ = json.Unmarshal(values[i], &label)
+		// Spaces separate the labels of a name, which parameters splits on.
+		if strings.ContainsFunc(label, unicode.IsSpace) {
+			return Configuration{}, fmt.Errorf("%s value %q must not contain whitespace", name, label)
+		}
+		labels[i] = name + "=" + label
+	}
+
+	configuration := Configuration{Name: strings.Join(labels, " ")}
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return Configuration{}, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&configuration.SolverConfig)
+	if err != nil {
+		return Configuration{}, fmt.Errorf("configuration %q: %w", configuration.Name, err)
+	}
+	return configuration, nil
+}
+
+// Ranking aggregates the runs of one configuration over every instance and
+// seed.
+type Ranking struct {
+	Configuration   string
+	Runs            int
+	Solved          int
+	BestScore       int
+	MeanScore       float64
+	ScoreStdDev     float64
+	MeanGenerations float64
+	MeanElapsed     time.Duration
+}
+
+// Rank orders the configurations of results from best to worst: by the runs
+// they solved, then by mean score, then by mean time.
+func Rank(results []Result) []Ranking {
+	var rankings []Ranking
+	scores := map[string][]float64{}
+	index := map[string]int{}
+	for _, result := range results {
+		i, exists := index[result.Configuration]
+		if !exists {
+			i = len(rankings)
+			index[result.Configuration] = i
+			rankings = append(rankings, Ranking{Configuration: result.Configuration, BestScore: result.Solution.Score})
+		}
+
+		ranking := &rankings[i]
+		ranking.Runs++
+		if result.Solution.Score == 0 {
+			ranking.Solved++
+		}
+		ranking.BestScore = min(ranking.BestScore, result.Solution.Score)
+		ranking.MeanGenerations += float64(result.Solution.Metadata.Generations)
+		ranking.MeanElapsed += result.Solution.Metadata.Elapsed
+		scores[result.Configuration] = append(scores[result.Configuration], float64(result.Solution.Score))
+	}
+
+	for i := range rankings {
+		ranking := &rankings[i]
+		ranking.MeanScore, ranking.ScoreStdDev = meanStdDev(scores[ranking.Configuration])
+		ranking.MeanGenerations /= float64(ranking.Runs)
+		ranking.MeanElapsed /= time.Duration(ranking.Runs)
+	}
+	slices.SortStableFunc(rankings, func(a, b Ranking) int {
+		switch {
+		case a.Solved != b.Solved:
+			return b.Solved - a.Solved
+		case a.MeanScore != b.MeanScore:
+			if a.MeanScore < b.MeanScore {
+				return -1
+			}
+			return 1
+		}
+		return int(a.MeanElapsed - b.MeanElapsed)
+	})
+	return rankings
+}
@@ -0,0 +1,125 @@
+package experiment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// hyperband runs successive halving brackets. A bracket of n configurations
+// with s halvings runs them all with the time limit divided by Eta s times,
+// keeps the best 1/Eta of them for a rerun with Eta times the limit, and so on
+// until the survivors run with the full limit. Most of the time thus goes to
+// the configurations that look best, at the risk of dropping slow starters.
+//
+// MethodHalving runs brackets that halve as often as MinTimeLimit allows, with
+// Budget configurations or Eta to that power. MethodHyperband hedges against
+// dropping slow starters by cycling through brackets that halve less often
+// but on fewer configurations, down to plainly running a few with the full
+// limit. Without a wall clock the search stops after one round of brackets;
+// with one it starts over until the time or the budget is spent.
+func (search *Search) hyperband(state *searchState, timeLimit time.Duration) ([]Trial, error) {
+	eta := search.Eta
+	if eta == 0 {
+		eta = 3
+	}
+	if eta < 2 {
+		return nil, fmt.Errorf("eta must be at least 2, got %d", eta)
+	}
+	minLimit := timeLimit / time.Duration(eta*eta*eta)
+	if search.MinTimeLimit != "" {
+		var err error
+		minLimit, err = time.ParseDuration(search.MinTimeLimit)
+		if err != nil {
+			return nil, fmt.Errorf("min_time_limit: %w", err)
+		}
+	}
+	if minLimit <= 0 || minLimit > timeLimit {
+		return nil, errors.New("min_time_limit must be positive and at most time_limit")
+	}
+	halvings := 0
+	for limit := minLimit * time.Duration(eta); limit <= timeLimit; limit *= time.Duration(eta) {
+		halvings++
+	}
+
+	var trials []Trial
+	index := 0
+	for {
+		brackets := []int{halvings}
+		if search.Method == MethodHyperband {
+			brackets = brackets[:0]
+			for s := halvings; s >= 0; s-- {
+				brackets = append(brackets, s)
+			}
+		}
+		for _, s := range brackets {
+			n := power(eta, s)
+			switch {
+			case search.Method == MethodHyperband:
+				n = ((halvings+1)*power(eta, s) + s) / (s + 1)
+			case search.Budget > 0:
+				n = search.Budget
+			}
+			if search.Budget > 0 {
+				n = min(n, search.Budget-index)
+			}
+			if n <= 0 {
+				return trials, nil
+			}
+			evaluated, finished, err := search.bracket(state, index, n, s, eta, timeLimit)
+			if err != nil {
+				return nil, err
+			}
+			trials = append(trials, evaluated...)
+			index += len(evaluated)
+			if !finished {
+				return trials, nil
+			}
+		}
+		if state.deadline.IsZero() {
+			return trials, nil
+		}
+	}
+}
+
+// bracket samples n configurations and halves them s times. It returns the
+// trials that ran at least once, and whether it finished before the wall
+// clock ran out.
+func (search *Search) bracket(state *searchState, index int, n int, s int, eta int, timeLimit time.Duration) ([]Trial, bool, error) {
+	candidates := make([]Trial, n)
+	alive := make([]*Trial, n)
+	for i := range candidates {
+		var err error
+		candidates[i], err = search.sample(state, index+i, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		alive[i] = &candidates[i]
+	}
+
+	for rung := 0; rung <= s; rung++ {
+		limit := timeLimit / time.Duration(power(eta, s-rung))
+		finished, err := search.evaluate(state, alive, limit)
+		if err != nil {
+			return nil, false, err
+		}
+		if !finished {
+			if rung == 0 {
+				return nil, false, nil
+			}
+			return candidates, false, nil
+		}
+		slices.SortStableFunc(alive, func(a, b *Trial) int { return byCost(*a, *b) })
+		alive = alive[:max(1, len(alive)/eta)]
+	}
+	return candidates, true, nil
+}
+
+func power(base int, exponent int) int {
+	result := 1
+	for i := 0; i < exponent; i++ {
+		result *= base
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package experiment
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+// Race runs candidate configurations on one instance and seed after another,
+// and after every such block drops the candidates that the Friedman test
+// finds significantly worse than the best one, so that budget goes to the
+// promising ones. Candidates are the listed Configurations, or every
+// combination of Parameters on top of Base as in a grid.
+//
+// Blocks take every instance with the first seed, then with the next one.
+// Tests start after FirstTest blocks, 5 by default, at significance Alpha,
+// 0.05 by default. Budget, if set, caps the number of runs. Objective and
+// TimeLimit are as for a search.
+type Race struct {
+	Instances      []string                     `json:"instances"`
+	Seeds          []int64                      `json:"seeds"`
+	Parallelism    int                          `json:"parallelism,omitempty"`
+	Configurations []Configuration              `json:"configurations,omitempty"`
+	Base           ga.SolverConfig              `json:"base"`
+	Parameters     map[string][]json.RawMessage `json:"parameters,omitempty"`
+	Objective      string                       `json:"objective,omitempty"`
+	TimeLimit      string                       `json:"time_limit,omitempty"`
+	Alpha          float64                      `json:"alpha,omitempty"`
+	FirstTest      int                          `json:"first_test,omitempty"`
+	Budget         int                          `json:"budget,omitempty"`
+}
+
+func LoadRace(filename string) (*Race, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	race := Race{Objective: ObjectiveScore, Alpha: 0.05, FirstTest: 5}
+	err = json.Unmarshal(data, &race)
+	if err != nil {
+		return nil, err
+	}
+	for i, instance := range race.Instances {
+		race.Instances[i] = relativeTo(filename, instance)
+	}
+	return &race, nil
+}
+
+// Contender is a candidate of a race. Costs holds its objective on every block
+// it ran; Eliminated is the number of blocks after which it was dropped, 0 if
+// it survived. MeanRank is its mean rank among the candidates that ran the
+// same blocks, on those blocks.
+type Contender struct {
+	Configuration Configuration
+	Costs         []float64
+	MeanRank      float64
+	MeanCost      float64
+	Eliminated    int
+}
+
+// Run races the candidates, passing the number of blocks run, the candidates
+// still in and those just dropped to progress after every block. It returns
+// the survivors by mean rank, followed by the dropped candidates from the last
+// dropped to the first.
+func (race *Race) Run(progress func(blocks int, alive []*Contender, dropped []*Contender)) ([]Contender, error) {
+	timeLimit, err := checkObjective(race.Objective, race.TimeLimit)
+	if err != nil {
+		return nil, err
+	}
+	if race.Alpha <= 0 || race.Alpha >= 1 {
+		return nil, fmt.Errorf("alpha must be between 0 and 1, got %g", race.Alpha)
+	}
+	candidates := race.Configurations
+	if len(race.Parameters) > 0 {
+		if len(candidates) > 0 {
+			return nil, errors.New("a race takes either configurations or parameters, not both")
+		}
+		grid := Grid{Instances: race.Instances, Seeds: race.Seeds, Base: race.Base, Parameters: race.Parameters}
+		experiment, err := grid.Experiment()
+		if err != nil {
+			return nil, err
+		}
+		candidates = experiment.Configurations
+	}
+	if len(candidates) < 2 {
+		return nil, errors.New("a race needs at least two candidates")
+	}
+
+	contenders := make([]Contender, len(candidates))
+	alive := make([]*Contender, len(candidates))
+	for i, configuration := range candidates {
+		contenders[i] = Contender{Configuration: configuration}
+		alive[i] = &contenders[i]
+	}
+
+	runs := 0
+	blocks := 0
+	for _, seed := range race.Seeds {
+		for _, instance := range race.Instances {
+			if len(alive) < 2 || (race.Budget > 0 && runs+len(alive) > race.Budget) {
+				return race.standings(contenders, alive, blocks), nil
+			}
+
+			experiment := Experiment{Instances: []string{instance}, Seeds: []int64{seed}, Parallelism: race.Parallelism, TimeLimit: timeLimit}
+			for _, contender := range alive {
+				experiment.Configurations = append(experiment.Configurations, contender.Configuration)
+			}
+			results, err := experiment.Run()
+			if err != nil {
+				return nil, err
+			}
+			for i, contender := range alive {
+				contender.Costs = append(contender.Costs, cost(race.Objective, results[i:i+1], timeLimit))
+			}
+			runs += len(alive)
+			blocks++
+
+			var dropped []*Contender
+			if blocks >= race.FirstTest {
+				alive, dropped = race.eliminate(alive, blocks)
+			}
+			if progress != nil {
+				progress(blocks, alive, dropped)
+			}
+		}
+	}
+	return race.standings(contenders, alive, blocks), nil
+}
+
+// eliminate drops the candidates whose rank sum exceeds the best one by more
+// than the least significant difference, if the Friedman test rejects that
+// all candidates are alike.
+func (race *Race) eliminate(alive []*Contender, blocks int) ([]*Contender, []*Contender) {
+	costs := make([][]float64, len(alive))
+	for i, contender := range alive {
+		costs[i] = contender.Costs
+	}
+	sums, p, difference := friedman(costs, race.Alpha)
+	if p >= race.Alpha {
+		return alive, nil
+	}
+
+	best := slices.Min(sums)
+	var kept, dropped []*Contender
+	for i, contender := range alive {
+		contender.MeanRank = sums[i] / float64(blocks)
+		if sums[i]-best > difference {
+			contender.Eliminated = blocks
+			dropped = append(dropped, contender)
+		} else {
+			kept = append(kept, contender)
+		}
+	}
+	return kept, dropped
+}
+
+func (race *Race) standings(contenders []Contender, alive []*Contender, blocks int) []Contender {
+	for i := range contenders {
+		contender := &contenders[i]
+		contender.MeanCost, _ = meanStdDev(contender.Costs)
+	}
+	if len(alive) > 0 && blocks > 0 {
+		costs := make([][]float64, len(alive))
+		for i, contender := range alive {
+			costs[i] = contender.Costs
+		}
+		sums, _, _ := friedman(costs, race.Alpha)
+		for i, contender := range alive {
+			contender.MeanRank = sums[i] / float64(blocks)
+		}
+	}
+
+	standings := slices.Clone(contenders)
+	slices.SortStableFunc(standings, func(a, b Contender) int {
+		switch {
+		case a.Eliminated == b.Eliminated:
+			return cmp.Compare(a.MeanRank, b.MeanRank)
+		case a.Eliminated == 0:
+			return -1
+		case b.Eliminated == 0:
+			return 1
+		}
+		return b.Eliminated - a.Eliminated
+	})
+	return standings
+}
@@ -0,0 +1,420 @@
+package experiment
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+// Range is the space one solver parameter is searched over: either the listed
+// Values, or the numbers from Min to Max, sampled on a log scale if Log is
+// set and rounded if Integer is set.
+type Range struct {
+	Values  []json.RawMessage `json:"values,omitempty"`
+	Min     float64           `json:"min,omitempty"`
+	Max     float64           `json:"max,omitempty"`
+	Log     bool              `json:"log,omitempty"`
+	Integer bool              `json:"integer,omitempty"`
+}
+
+func (r Range) categorical() bool {
+	return len(r.Values) > 0
+}
+
+func (r Range) check() error {
+	switch {
+	case r.categorical():
+		return nil
+	case r.Min > r.Max:
+		return fmt.Errorf("min %g is over max %g", r.Min, r.Max)
+	case r.Log && r.Min <= 0:
+		return errors.New("a log scale needs a positive min")
+	}
+	return nil
+}
+
+// value maps u in [0, 1] into the range.
+func (r Range) value(u float64) json.RawMessage {
+	if r.categorical() {
+		return r.Values[min(int(u*float64(len(r.Values))), len(r.Values)-1)]
+	}
+	value := r.Min + u*(r.Max-r.Min)
+	if r.Log {
+		value = math.Exp(math.Log(r.Min) + u*(math.Log(r.Max)-math.Log(r.Min)))
+	}
+	if r.Integer {
+		return json.RawMessage(strconv.FormatInt(int64(math.Round(value)), 10))
+	}
+	return json.RawMessage(strconv.FormatFloat(value, 'g', 4, 64))
+}
+
+// Objectives a search minimizes. ObjectiveScore is the mean score the runs end
+// with; ObjectiveTime is the mean time to reach zero conflicts, counting runs
+// that do not within the time limit at ten times the limit.
+const (
+	ObjectiveScore = "score"
+	ObjectiveTime  = "time"
+)
+
+// Search methods. MethodRandom samples every configuration uniformly;
+// MethodTPE samples the first ones uniformly and the rest with a
+// tree-structured Parzen estimator, which favours configurations that look
+// like the best ones so far and unlike the rest.
+//
+// MethodHalving and MethodHyperband sample uniformly too, but first run many
+// configurations with a fraction of the time limit and give more time only to
+// the best of them; see Search.hyperband.
+const (
+	MethodRandom    = "random"
+	MethodTPE       = "tpe"
+	MethodHalving   = "halving"
+	MethodHyperband = "hyperband"
+)
+
+// Search tries Budget configurations drawn from Parameters on top of Base,
+// running each on every instance with every seed. TimeLimit, such as "10s",
+// stops every run and is required by ObjectiveTime and by the halving
+// methods. Seed seeds the choice of configurations and 0 seeds it from the
+// clock.
+//
+// WallClock, such as "1h", bounds the whole search: no run goes on past it
+// and the search returns the trials finished by then. With it, Budget may be
+// 0 for no limit on the number of configurations. Eta and MinTimeLimit tune
+// the halving methods, defaulting to 3 and to TimeLimit over Eta cubed.
+type Search struct {
+	Instances   []string         `json:"instances"`
+	Seeds       []int64          `json:"seeds"`
+	Parallelism int              `json:"parallelism,omitempty"`
+	Base        ga.SolverConfig  `json:"base"`
+	Parameters  map[string]Range `json:"parameters"`
+	Budget      int              `json:"budget"`
+	Method      string           `json:"method,omitempty"`
+	Objective   string           `json:"objective,omitempty"`
+	TimeLimit   string           `json:"time_limit,omitempty"`
+	Seed        int64            `json:"seed,omitempty"`
+
+	WallClock    string `json:"wall_clock,omitempty"`
+	Eta          int    `json:"eta,omitempty"`
+	MinTimeLimit string `json:"min_time_limit,omitempty"`
+}
+
+func LoadSearch(filename string) (*Search, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	search := Search{Method: MethodTPE, Objective: ObjectiveScore}
+	err = json.Unmarshal(data, &search)
+	if err != nil {
+		return nil, err
+	}
+	for i, instance := range search.Instances {
+		search.Instances[i] = relativeTo(filename, instance)
+	}
+	return &search, nil
+}
+
+// Trial is one configuration a search tried, with its runs aggregated. Cost
+// is the objective, lower being better, with runs stopped after TimeLimit.
+type Trial struct {
+	Index         int
+	Configuration Configuration
+	Ranking       Ranking
+	Cost          float64
+	TimeLimit     time.Duration
+
+	point []float64
+}
+
+// searchState is what the methods of a search share while it runs.
+type searchState struct {
+	names    []string
+	ranges   []Range
+	rng      *rand.Rand
+	deadline time.Time
+	progress func(Trial)
+}
+
+// sample draws a configuration at point, or uniformly if point is nil.
+func (search *Search) sample(state *searchState, index int, point []float64) (Trial, error) {
+	if point == nil {
+		point = randomPoint(state.rng, len(state.ranges))
+	}
+	values := make([]json.RawMessage, len(state.names))
+	for i, r := range state.ranges {
+		values[i] = r.value(point[i])
+	}
+	configuration, err := configure(search.Base, state.names, values)
+	if err != nil {
+		return Trial{}, err
+	}
+	return Trial{Index: index, Configuration: configuration, point: point}, nil
+}
+
+// evaluate runs every trial on every instance with every seed, stopping runs
+// after timeLimit, and sets their costs. It reports false, leaving the trials
+// as they were, if the wall clock ran out first.
+func (search *Search) evaluate(state *searchState, trials []*Trial, timeLimit time.Duration) (bool, error) {
+	if !state.deadline.IsZero() && !time.Now().Before(state.deadline) {
+		return false, nil
+	}
+	experiment := Experiment{
+		Instances:   search.Instances,
+		Seeds:       search.Seeds,
+		Parallelism: search.Parallelism,
+		TimeLimit:   timeLimit,
+		Deadline:    state.deadline,
+	}
+	for _, trial := range trials {
+		experiment.Configurations = append(experiment.Configurations, trial.Configuration)
+	}
+	results, err := experiment.Run()
+	if err != nil {
+		return false, err
+	}
+	if !state.deadline.IsZero() && time.Now().After(state.deadline) {
+		// Some runs were cut short by the wall clock, not by their limit.
+		return false, nil
+	}
+
+	// Results come in instance, configuration, seed order.
+	seeds := len(search.Seeds)
+	for c, trial := range trials {
+		var own []Result
+		for i := range search.Instances {
+			start := (i*len(trials) + c) * seeds
+			own = append(own, results[start:start+seeds]...)
+		}
+		trial.Ranking = Rank(own)[0]
+		trial.Cost = cost(search.Objective, own, timeLimit)
+		trial.TimeLimit = timeLimit
+		if state.progress != nil {
+			state.progress(*trial)
+		}
+	}
+	return true, nil
+}
+
+// Run tries the configurations, passing every trial to progress as it
+// finishes, and returns the trials from best to worst. Trials that ran with a
+// longer time limit come first, as their costs are not comparable with those
+// of shorter runs.
+func (search *Search) Run(progress func(Trial)) ([]Trial, error) {
+	names := make([]string, 0, len(search.Parameters))
+	for name, r := range search.Parameters {
+		if name == "seed" {
+			return nil, errors.New("the seeds of a search are listed under seeds, not parameters")
+		}
+		err := r.check()
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", name, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	timeLimit, err := checkObjective(search.Objective, search.TimeLimit)
+	if err != nil {
+		return nil, err
+	}
+	var wallClock time.Duration
+	if search.WallClock != "" {
+		wallClock, err = time.ParseDuration(search.WallClock)
+		if err != nil {
+			return nil, fmt.Errorf("wall_clock: %w", err)
+		}
+	}
+	halving := search.Method == MethodHalving || search.Method == MethodHyperband
+	switch {
+	case search.Method != MethodRandom && search.Method != MethodTPE && !halving:
+		return nil, fmt.Errorf("unknown search method %q, expected %s, %s, %s or %s", search.Method, MethodRandom, MethodTPE, MethodHalving, MethodHyperband)
+	case search.Budget < 0 || (search.Budget == 0 && wallClock <= 0 && !halving):
+		return nil, errors.New("search needs a budget of at least one configuration or a wall_clock")
+	case halving && timeLimit <= 0:
+		return nil, fmt.Errorf("the %s method needs a time_limit to divide", search.Method)
+	}
+
+	seed := search.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	state := searchState{names: names, rng: rand.New(rand.NewSource(seed)), progress: progress}
+	for _, name := range names {
+		state.ranges = append(state.ranges, search.Parameters[name])
+	}
+	if wallClock > 0 {
+		state.deadline = time.Now().Add(wallClock)
+	}
+
+	var trials []Trial
+	if halving {
+		trials, err = search.hyperband(&state, timeLimit)
+	} else {
+		trials, err = search.sequential(&state, timeLimit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(trials, func(a, b Trial) int {
+		if a.TimeLimit != b.TimeLimit {
+			return cmp.Compare(b.TimeLimit, a.TimeLimit)
+		}
+		return byCost(a, b)
+	})
+	return trials, nil
+}
+
+// sequential tries one configuration after another with the full time limit.
+func (search *Search) sequential(state *searchState, timeLimit time.Duration) ([]Trial, error) {
+	var trials []Trial
+	for index := 0; search.Budget == 0 || index < search.Budget; index++ {
+		var point []float64
+		if search.Method == MethodTPE && index >= startupTrials(search.Budget) {
+			point = suggest(state.rng, state.ranges, trials)
+		}
+		trial, err := search.sample(state, index, point)
+		if err != nil {
+			return nil, err
+		}
+		finished, err := search.evaluate(state, []*Trial{&trial}, timeLimit)
+		if err != nil {
+			return nil, err
+		}
+		if !finished {
+			break
+		}
+		trials = append(trials, trial)
+	}
+	return trials, nil
+}
+
+func byCost(a, b Trial) int {
+	switch {
+	case a.Cost < b.Cost:
+		return -1
+	case a.Cost > b.Cost:
+		return 1
+	}
+	return 0
+}
+
+// checkObjective checks objective and returns the parsed time limit.
+func checkObjective(objective string, limit string) (time.Duration, error) {
+	if objective != ObjectiveScore && objective != ObjectiveTime {
+		return 0, fmt.Errorf("unknown objective %q, expected %s or %s", objective, ObjectiveScore, ObjectiveTime)
+	}
+	var timeLimit time.Duration
+	if limit != "" {
+		var err error
+		timeLimit, err = time.ParseDuration(limit)
+		if err != nil {
+			return 0, fmt.Errorf("time_limit: %w", err)
+		}
+	}
+	if objective == ObjectiveTime && timeLimit <= 0 {
+		return 0, errors.New("the time objective needs a time_limit")
+	}
+	return timeLimit, nil
+}
+
+// cost is the mean objective over results.
+func cost(objective string, results []Result, timeLimit time.Duration) float64 {
+	total := 0.0
+	for _, result := range results {
+		switch {
+		case objective == ObjectiveScore:
+			total += float64(result.Solution.Score)
+		case result.Solution.Score == 0:
+			total += result.Solution.Metadata.Elapsed.Seconds()
+		default:
+			total += 10 * timeLimit.Seconds()
+		}
+	}
+	return total / float64(len(results))
+}
+
+// startupTrials is how many configurations TPE samples uniformly before it
+// has enough trials to model, with 0 for a search bounded by time only.
+func startupTrials(budget int) int {
+	if budget == 0 {
+		return 10
+	}
+	return min(max(5, budget/5), budget)
+}
+
+func randomPoint(rng *rand.Rand, dimensions int) []float64 {
+	point := make([]float64, dimensions)
+	for i := range point {
+		point[i] = rng.Float64()
+	}
+	return point
+}
+
+const (
+	// tpeGood is the share of trials that count as good.
+	tpeGood       = 0.25
+	tpeCandidates = 24
+	tpeBandwidth  = 0.15
+)
+
+// suggest returns the candidate point, drawn around the good trials, with the
+// highest ratio of its density under the good trials to that under the rest.
+func suggest(rng *rand.Rand, ranges []Range, trials []Trial) []float64 {
+	sorted := slices.Clone(trials)
+	slices.SortStableFunc(sorted, byCost)
+	split := max(1, int(math.Ceil(tpeGood*float64(len(sorted)))))
+	good, bad := sorted[:split], sorted[split:]
+
+	var best []float64
+	bestRatio := math.Inf(-1)
+	for c := 0; c < tpeCandidates; c++ {
+		around := good[rng.Intn(len(good))].point
+		candidate := make([]float64, len(ranges))
+		for i, r := range ranges {
+			switch {
+			case r.categorical() && rng.Float64() < 0.8:
+				candidate[i] = around[i]
+			case r.categorical():
+				candidate[i] = rng.Float64()
+			default:
+				candidate[i] = math.Min(math.Max(around[i]+rng.NormFloat64()*tpeBandwidth, 0), 1)
+			}
+		}
+		ratio := logDensity(ranges, good, candidate) - logDensity(ranges, bad, candidate)
+		if ratio > bestRatio {
+			best, bestRatio = candidate, ratio
+		}
+	}
+	return best
+}
+
+// logDensity estimates the log density of point among the points of trials,
+// dimension by dimension, with a uniform prior counting as one more trial.
+func logDensity(ranges []Range, trials []Trial, point []float64) float64 {
+	total := 0.0
+	for i, r := range ranges {
+		density := 1.0
+		for _, trial := range trials {
+			if r.categorical() {
+				if string(r.value(trial.point[i])) == string(r.value(point[i])) {
+					density += float64(len(r.Values))
+				}
+				continue
+			}
+			distance := (point[i] - trial.point[i]) / tpeBandwidth
+			density += math.Exp(-distance*distance/2) / (tpeBandwidth * math.Sqrt(2*math.Pi))
+		}
+		total += math.Log(density / float64(len(trials)+1))
+	}
+	return total
+}
@@ -0,0 +1,156 @@
+package experiment
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Level is the mean cost of the runs that set a parameter to Value.
+type Level struct {
+	Value    string
+	Runs     int
+	Solved   int
+	MeanCost float64
+}
+
+// Effect is the main effect of a parameter: its levels in sweep order and the
+// share of the variance in cost they explain, from 0 to 1.
+type Effect struct {
+	Parameter string
+	Levels    []Level
+	Share     float64
+}
+
+// Spread is the difference between the worst and the best level.
+func (effect Effect) Spread() float64 {
+	low, high := effect.Levels[0].MeanCost, effect.Levels[0].MeanCost
+	for _, level := range effect.Levels[1:] {
+		low, high = min(low, level.MeanCost), max(high, level.MeanCost)
+	}
+	return high - low
+}
+
+// LoadResults reads results as written by experiment or tune grid -out, a
+// JSON array, or as a results file of a manifest, JSON Lines.
+func LoadResults(filename string) ([]Result, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &results)
+		return results, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		result := Result{}
+		err := decoder.Decode(&result)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// parameters reads back the parameter values a grid put in a configuration
+// name, as labels separated by single spaces.
+func parameters(name string) (map[string]string, error) {
+	values := map[string]string{}
+	if name == "" {
+		return values, nil
+	}
+	for _, label := range strings.Split(name, " ") {
+		parameter, value, found := strings.Cut(label, "=")
+		if !found {
+			return nil, fmt.Errorf("configuration %q does not come from a grid", name)
+		}
+		values[parameter] = value
+	}
+	return values, nil
+}
+
+// Sensitivity attributes the variance in cost of the results of a sweep to
+// its parameters, most influential first. Costs are measured against the mean
+// on their instance first, so that hard and easy instances do not count as
+// variance. In a full grid the main effects are independent, and the share
+// they leave unexplained, returned as well, is due to interactions and noise.
+func Sensitivity(results []Result, objective string, timeLimit time.Duration) ([]Effect, float64, error) {
+	if len(results) == 0 {
+		return nil, 0, errors.New("no results to analyze")
+	}
+	costs := make([]float64, len(results))
+	byInstance := map[string][]float64{}
+	for i, result := range results {
+		costs[i] = cost(objective, results[i:i+1], timeLimit)
+		byInstance[result.Instance] = append(byInstance[result.Instance], costs[i])
+	}
+	means := map[string]float64{}
+	for instance, values := range byInstance {
+		means[instance], _ = meanStdDev(values)
+	}
+	total := 0.0
+	residuals := make([]float64, len(results))
+	for i, result := range results {
+		residuals[i] = costs[i] - means[result.Instance]
+		total += residuals[i] * residuals[i]
+	}
+
+	// sums holds the residual sum of every level of every effect.
+	var effects []Effect
+	var sums [][]float64
+	index := map[string]int{}
+	for i, result := range results {
+		values, err := parameters(result.Configuration)
+		if err != nil {
+			return nil, 0, err
+		}
+		for parameter, value := range values {
+			if _, exists := index[parameter]; !exists {
+				index[parameter] = len(effects)
+				effects = append(effects, Effect{Parameter: parameter})
+				sums = append(sums, nil)
+			}
+			e := index[parameter]
+			effect := &effects[e]
+			j := slices.IndexFunc(effect.Levels, func(level Level) bool { return level.Value == value })
+			if j < 0 {
+				j = len(effect.Levels)
+				effect.Levels = append(effect.Levels, Level{Value: value})
+				sums[e] = append(sums[e], 0)
+			}
+			level := &effect.Levels[j]
+			level.Runs++
+			level.MeanCost += costs[i]
+			if result.Solution.Score == 0 {
+				level.Solved++
+			}
+			sums[e][j] += residuals[i]
+		}
+	}
+
+	unexplained := 1.0
+	for e := range effects {
+		effect := &effects[e]
+		for j := range effect.Levels {
+			level := &effect.Levels[j]
+			level.MeanCost /= float64(level.Runs)
+			if total > 0 {
+				effect.Share += sums[e][j] * sums[e][j] / float64(level.Runs) / total
+			}
+		}
+		unexplained -= effect.Share
+	}
+	slices.SortStableFunc(effects, func(a, b Effect) int {
+		return cmp.Compare(b.Share, a.Share)
+	})
+	return effects, max(unexplained, 0), nil
+}
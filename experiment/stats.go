@@ -0,0 +1,364 @@
+package experiment
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// meanStdDev returns the mean and sample standard deviation of values.
+func meanStdDev(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)-1))
+}
+
+// ranks returns the rank of every value from 1 for the smallest, giving tied
+// values the mean of their ranks.
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+
+	result := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, index := range order[start:end] {
+			result[index] = rank
+		}
+		start = end
+	}
+	return result
+}
+
+// friedman is the Friedman test on costs, indexed by treatment and then by
+// block, followed by the Conover post-hoc comparison. It returns the rank sum
+// of every treatment, the p-value of the hypothesis that all treatments are
+// alike, and the least difference between two rank sums that is significant
+// at alpha. A p-value of 1 means the ranks are tied in every block.
+func friedman(costs [][]float64, alpha float64) (sums []float64, p float64, difference float64) {
+	k := len(costs)
+	b := len(costs[0])
+	sums = make([]float64, k)
+	squares := 0.0
+	for block := 0; block < b; block++ {
+		values := make([]float64, k)
+		for treatment := range costs {
+			values[treatment] = costs[treatment][block]
+		}
+		for treatment, rank := range ranks(values) {
+			sums[treatment] += rank
+			squares += rank * rank
+		}
+	}
+
+	sumSquares := 0.0
+	for _, sum := range sums {
+		sumSquares += sum * sum
+	}
+	correction := float64(b*k) * float64(k+1) * float64(k+1) / 4
+	if squares <= correction || b < 2 {
+		return sums, 1, math.Inf(1)
+	}
+	statistic := float64(k-1) * (sumSquares - float64(b)*correction) / (squares - correction)
+	p = chiSquareSurvival(statistic, float64(k-1))
+
+	df := float64((b - 1) * (k - 1))
+	variance := 2 * (float64(b)*squares - sumSquares) / df
+	if variance <= 0 {
+		return sums, p, 0
+	}
+	return sums, p, studentTQuantile(1-alpha/2, df) * math.Sqrt(variance)
+}
+
+// chiSquareSurvival is the probability that a chi-squared variable with df
+// degrees of freedom exceeds x.
+func chiSquareSurvival(x float64, df float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return 1 - lowerGamma(df/2, x/2)
+}
+
+// lowerGamma is the regularized lower incomplete gamma function P(a, x).
+func lowerGamma(a float64, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	if x < a+1 {
+		// The series converges quickly below a+1.
+		term := 1 / a
+		sum := term
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-14 {
+				break
+			}
+		}
+		return sum * math.Exp(-x+a*math.Log(x)-lgamma)
+	}
+	// Above it, the continued fraction for the upper function does.
+	c := 1 / 1e-300
+	d := 1 / (x + 1 - a)
+	h := d
+	for n := 1; n < 500; n++ {
+		an := -float64(n) * (float64(n) - a)
+		bn := x + 2*float64(n) + 1 - a
+		d = bn + an*d
+		if math.Abs(d) < 1e-300 {
+			d = 1e-300
+		}
+		c = bn + an/c
+		if math.Abs(c) < 1e-300 {
+			c = 1e-300
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-14 {
+			break
+		}
+	}
+	return 1 - h*math.Exp(-x+a*math.Log(x)-lgamma)
+}
+
+// studentTQuantile returns t such that a Student t variable with df degrees
+// of freedom is below t with probability q, for q above one half.
+func studentTQuantile(q float64, df float64) float64 {
+	low, high := 0.0, 1.0
+	for studentTCDF(high, df) < q {
+		high *= 2
+	}
+	for i := 0; i < 100; i++ {
+		middle := (low + high) / 2
+		if studentTCDF(middle, df) < q {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return (low + high) / 2
+}
+
+// studentTCDF is the probability that a Student t variable with df degrees of
+// freedom is below t, for t of at least 0.
+func studentTCDF(t float64, df float64) float64 {
+	return 1 - incompleteBeta(df/2, 0.5, df/(df+t*t))/2
+}
+
+// incompleteBeta is the regularized incomplete beta function I_x(a, b).
+func incompleteBeta(a float64, b float64, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	if x > (a+1)/(a+b+2) {
+		// The continued fraction converges on the other side of the mean.
+		return 1 - front*betaFraction(b, a, 1-x)/b
+	}
+	return front * betaFraction(a, b, x) / a
+}
+
+func betaFraction(a float64, b float64, x float64) float64 {
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < 1e-300 {
+		d = 1e-300
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m < 500; m++ {
+		fm := float64(m)
+		for _, an := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + an*d
+			if math.Abs(d) < 1e-300 {
+				d = 1e-300
+			}
+			c = 1 + an/c
+			if math.Abs(c) < 1e-300 {
+				c = 1e-300
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-14 {
+			break
+		}
+	}
+	return h
+}
+
+// Distribution describes a sample. It is all zero for an empty sample.
+type Distribution struct {
+	N      int
+	Mean   float64
+	Median float64
+	StdDev float64
+	Min    float64
+	Max    float64
+}
+
+func describe(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	distribution := Distribution{N: len(sorted), Min: sorted[0], Max: sorted[len(sorted)-1]}
+	distribution.Mean, distribution.StdDev = meanStdDev(sorted)
+	middle := len(sorted) / 2
+	distribution.Median = sorted[middle]
+	if len(sorted)%2 == 0 {
+		distribution.Median = (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return distribution
+}
+
+// Statistics describes repeated runs. A run is feasible when it ends without
+// conflicts, and as the solver stops there, its time to feasible is its time;
+// TimeToFeasible, in seconds, covers the feasible runs only.
+type Statistics struct {
+	Runs           int
+	Solved         int
+	SuccessRate    float64
+	Score          Distribution
+	TimeToFeasible Distribution
+	Generations    Distribution
+}
+
+func Describe(results []Result) Statistics {
+	var scores, times, generations []float64
+	for _, result := range results {
+		scores = append(scores, float64(result.Solution.Score))
+		generations = append(generations, float64(result.Solution.Metadata.Generations))
+		if result.Solution.Score == 0 {
+			times = append(times, result.Solution.Metadata.Elapsed.Seconds())
+		}
+	}
+	statistics := Statistics{
+		Runs:           len(results),
+		Solved:         len(times),
+		Score:          describe(scores),
+		TimeToFeasible: describe(times),
+		Generations:    describe(generations),
+	}
+	if len(results) > 0 {
+		statistics.SuccessRate = float64(len(times)) / float64(len(results))
+	}
+	return statistics
+}
+
+// signTest is the two-sided p-value of the exact sign test for wins against
+// losses, ties left out.
+func signTest(wins int, losses int) float64 {
+	n := wins + losses
+	if n == 0 {
+		return 1
+	}
+	k := min(wins, losses)
+	tail := 0.0
+	for i := 0; i <= k; i++ {
+		tail += math.Exp(logChoose(n, i) - float64(n)*math.Ln2)
+	}
+	return min(1, 2*tail)
+}
+
+func logChoose(n int, k int) float64 {
+	ln, _ := math.Lgamma(float64(n + 1))
+	lk, _ := math.Lgamma(float64(k + 1))
+	lnk, _ := math.Lgamma(float64(n - k + 1))
+	return ln - lk - lnk
+}
+
+// wilcoxon is the two-sided p-value of the Wilcoxon signed-rank test that
+// differences are centered on zero. Zero differences are left out. Small
+// samples without ties get the exact distribution, others the normal
+// approximation with tie and continuity corrections.
+func wilcoxon(differences []float64) float64 {
+	var magnitudes, signs []float64
+	for _, difference := range differences {
+		if difference != 0 {
+			magnitudes = append(magnitudes, math.Abs(difference))
+			signs = append(signs, math.Copysign(1, difference))
+		}
+	}
+	n := len(magnitudes)
+	if n == 0 {
+		return 1
+	}
+	ranked := ranks(magnitudes)
+	positive := 0.0
+	tied := false
+	for i, rank := range ranked {
+		if signs[i] > 0 {
+			positive += rank
+		}
+		if rank != math.Trunc(rank) {
+			tied = true
+		}
+	}
+	total := float64(n*(n+1)) / 2
+	statistic := min(positive, total-positive)
+
+	if n <= 25 && !tied {
+		// counts[s] is the number of sign assignments with positive rank sum s.
+		counts := make([]float64, int(total)+1)
+		counts[0] = 1
+		for rank := 1; rank <= n; rank++ {
+			for s := int(total); s >= rank; s-- {
+				counts[s] += counts[s-rank]
+			}
+		}
+		tail := 0.0
+		for s := 0; s <= int(statistic); s++ {
+			tail += counts[s]
+		}
+		return min(1, 2*tail/math.Pow(2, float64(n)))
+	}
+
+	// A tie of t magnitudes shrinks the variance by (t^3 - t) / 48.
+	variance := float64(n*(n+1)*(2*n+1)) / 24
+	sorted := slices.Clone(magnitudes)
+	slices.Sort(sorted)
+	for start := 0; start < n; {
+		end := start + 1
+		for end < n && sorted[end] == sorted[start] {
+			end++
+		}
+		t := float64(end - start)
+		variance -= (t*t*t - t) / 48
+		start = end
+	}
+	if variance <= 0 {
+		return 1
+	}
+	z := (total/2 - statistic - 0.5) / math.Sqrt(variance)
+	return min(1, math.Erfc(max(z, 0)/math.Sqrt2))
+}
@@ -0,0 +1,81 @@
+package experiment
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+// runColumns identify a run in every table of results.
+var runColumns = []string{"instance", "configuration", "seed"}
+
+// RunsTable has a row per run, followed by the solver settings it ran with in
+// columns named like the fields of a config file.
+func RunsTable(results []Result) encoding.Table {
+	names, _ := settings(ga.SolverConfig{})
+	columns := []string{"score", "solved", "generations", "evaluations", "elapsed", "graph_hash"}
+	table := encoding.Table{Columns: slices.Concat(runColumns, columns, names)}
+	for _, result := range results {
+		solution := result.Solution
+		_, values := settings(solution.Metadata.Config)
+		row := []any{
+			result.Instance,
+			result.Configuration,
+			int(result.Seed),
+			solution.Score,
+			solution.Score == 0,
+			solution.Metadata.Generations,
+			solution.Metadata.Evaluations,
+			solution.Metadata.Elapsed.Seconds(),
+			solution.Metadata.GraphHash,
+		}
+		table.Rows = append(table.Rows, append(row, values...))
+	}
+	return table
+}
+
+// GenerationsTable has a row per generation of every run that kept its
+// history.
+func GenerationsTable(results []Result) encoding.Table {
+	table := encoding.Table{Columns: slices.Concat(runColumns, encoding.HistoryColumns)}
+	for _, result := range results {
+		for _, stats := range result.History {
+			row := []any{result.Instance, result.Configuration, int(result.Seed)}
+			table.Rows = append(table.Rows, append(row, encoding.HistoryRow(stats)...))
+		}
+	}
+	return table
+}
+
+// settings lists the fields of config by their JSON names, leaving out the
+// seed, which every run table has already.
+func settings(config ga.SolverConfig) ([]string, []any) {
+	var names []string
+	var values []any
+	var walk func(value reflect.Value)
+	walk = func(value reflect.Value) {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.Anonymous {
+				walk(value.Field(i))
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" || name == "seed" {
+				continue
+			}
+			names = append(names, name)
+			switch field.Type.Kind() {
+			case reflect.Int, reflect.Int64:
+				values = append(values, int(value.Field(i).Int()))
+			default:
+				values = append(values, value.Field(i).Interface())
+			}
+		}
+	}
+	walk(reflect.ValueOf(config))
+	return names, values
+}
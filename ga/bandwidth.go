@@ -0,0 +1,31 @@
+package ga
+
+import (
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// BandwidthFitness scores colorings for bandwidth coloring, where the colors
+// of the ends of every edge must differ by at least its weight: it adds up by
+// how much every edge falls short of that. On unweighted graphs it counts
+// conflicts, as ConflictFitness does.
+type BandwidthFitness struct{}
+
+func (BandwidthFitness) Evaluate(g graph.Interface, chromosome Chromosome) int {
+	weighted, _ := g.(graph.Weighted)
+	score := 0
+	for i := 0; i < g.NodeCount(); i++ {
+		var weights []int
+		if weighted != nil {
+			weights = weighted.EdgeWeights(i)
+		}
+		for k, j := range g.Edges(i) {
+			weight := 1
+			if weights != nil {
+				weight = weights[k]
+			}
+			difference := chromosome[i] - chromosome[j]
+			score += max(weight-max(difference, -difference), 0)
+		}
+	}
+	return score
+}
@@ -0,0 +1,106 @@
+package ga
+
+import (
+	"slices"
+
+	"github.com/packedbread/gen-alg-graph-coloring/evo"
+)
+
+// fitnessCache remembers the scores of the chromosomes scored in the current
+// generation and the one before, which is where a converging population
+// repeats itself. Chromosomes are looked up by hash and compared gene by gene,
+// so a hit always has the score Fitness would give.
+type fitnessCache struct {
+	current  map[uint64][]cachedScore
+	previous map[uint64][]cachedScore
+	// spare holds the copies of chromosomes that aged out, to be reused.
+	spare Population
+	hits  int
+}
+
+type cachedScore struct {
+	chromosome Chromosome
+	score      int
+}
+
+func newFitnessCache() *fitnessCache {
+	return &fitnessCache{current: make(map[uint64][]cachedScore), previous: make(map[uint64][]cachedScore)}
+}
+
+// hashChromosome is FNV-1a over the colors of chr.
+func hashChromosome(chr Chromosome) uint64 {
+	hash := uint64(14695981039346656037)
+	for _, color := range chr {
+		hash ^= uint64(color)
+		hash *= 1099511628211
+	}
+	return hash
+}
+
+func (cache *fitnessCache) lookup(hash uint64, chr Chromosome) (int, bool) {
+	for _, entries := range []map[uint64][]cachedScore{cache.current, cache.previous} {
+		for _, entry := range entries[hash] {
+			if slices.Equal(entry.chromosome, chr) {
+				cache.hits++
+				return entry.score, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// store keeps a copy of chr, which the solver may recycle.
+func (cache *fitnessCache) store(hash uint64, chr Chromosome, score int) {
+	var chromosome Chromosome
+	if n := len(cache.spare); n > 0 {
+		chromosome, cache.spare = cache.spare[n-1], cache.spare[:n-1]
+	}
+	chromosome = append(chromosome[:0], chr...)
+	cache.current[hash] = append(cache.current[hash], cachedScore{chromosome: chromosome, score: score})
+}
+
+// age forgets the generation before the current one, which becomes the
+// previous one.
+func (cache *fitnessCache) age() {
+	for hash, entries := range cache.previous {
+		for _, entry := range entries {
+			cache.spare = append(cache.spare, entry.chromosome)
+		}
+		delete(cache.previous, hash)
+	}
+	cache.previous, cache.current = cache.current, cache.previous
+}
+
+// deduplicate replaces every chromosome of the population, which is sorted
+// by scores, that repeats a better or equal one with a random chromosome, and
+// sorts the population again.
+func (solver *GraphColoringSolver) deduplicate(generation int, population Population, scores []int) {
+	seen := make(map[uint64][]int, len(population))
+	var scored []evo.Scored[Chromosome]
+	replaced := 0
+	for i, chr := range population {
+		hash := hashChromosome(chr)
+		duplicate := slices.ContainsFunc(seen[hash], func(j int) bool {
+			return slices.Equal(population[j], chr)
+		})
+		if !duplicate {
+			seen[hash] = append(seen[hash], i)
+			continue
+		}
+		if scored == nil {
+			scored = make([]evo.Scored[Chromosome], len(population))
+			for j := range population {
+				scored[j] = evo.Scored[Chromosome]{Genome: population[j], Score: scores[j]}
+			}
+		}
+		solver.scratch.spare = append(solver.scratch.spare, chr)
+		random := solver.randomChromosome()
+		scored[i] = evo.Scored[Chromosome]{Genome: random, Score: solver.CalculateFitness(random)}
+		replaced++
+	}
+	if replaced == 0 {
+		return
+	}
+	evo.Truncate(population, scores, scored)
+	solver.Logger.Debug("replaced duplicates", "generation", generation, "replaced", replaced)
+}
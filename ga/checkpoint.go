@@ -0,0 +1,116 @@
+package ga
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// Config.Seed is the seed the run started with and Seed the one the solver was
+// reseeded with when the checkpoint was taken.
+type Checkpoint struct {
+	NodeCount   int
+	GraphHash   string
+	Generation  int
+	Population  Population
+	Seed        int64
+	Config      SolverConfig
+	Evaluations int
+	Elapsed     time.Duration
+}
+
+// A math/rand source cannot be snapshotted, so taking a checkpoint reseeds the
+// solver with a fresh seed that is stored alongside the population: reseeding
+// with the same value on resume continues the exact same stream.
+func (solver *GraphColoringSolver) checkpoint(generation int, population Population, elapsed time.Duration) Checkpoint {
+	seed := solver.Rand.Int63()
+	solver.Rand.Seed(seed)
+
+	config := solver.Config()
+	config.PopSize = len(population)
+	return Checkpoint{
+		NodeCount:   solver.Graph.NodeCount(),
+		GraphHash:   graph.Hash(solver.Graph),
+		Generation:  generation,
+		Population:  clonePopulation(population),
+		Seed:        seed,
+		Config:      config,
+		Evaluations: solver.evaluations,
+		Elapsed:     elapsed,
+	}
+}
+
+// Validate checks that the checkpoint can be resumed on g by a solver set up
+// as config, and lists the settings the checkpoint overrides or that changed.
+func (checkpoint *Checkpoint) Validate(g graph.Interface, config SolverConfig) ([]string, error) {
+	saved := checkpoint.Config
+	if checkpoint.NodeCount != g.NodeCount() {
+		return nil, fmt.Errorf("checkpoint is for a graph with %d nodes, got %d", checkpoint.NodeCount, g.NodeCount())
+	}
+	if checkpoint.GraphHash != graph.Hash(g) {
+		return nil, errors.New("checkpoint was taken on a different graph")
+	}
+	if saved.Colors != config.Colors {
+		return nil, fmt.Errorf("checkpoint uses %d colors, got %d", saved.Colors, config.Colors)
+	}
+	if len(checkpoint.Population) != saved.PopSize {
+		return nil, fmt.Errorf("checkpoint holds %d chromosomes, expected %d", len(checkpoint.Population), saved.PopSize)
+	}
+	for _, chr := range checkpoint.Population {
+		if len(chr) != g.NodeCount() {
+			return nil, fmt.Errorf("checkpoint chromosome has %d genes, expected %d", len(chr), g.NodeCount())
+		}
+		for _, color := range chr {
+			if color < 0 || color >= saved.Colors {
+				return nil, fmt.Errorf("checkpoint chromosome uses color %d outside of [0, %d)", color, saved.Colors)
+			}
+		}
+	}
+
+	var warnings []string
+	if saved.PopSize != config.PopSize {
+		warnings = append(warnings, fmt.Sprintf("continuing with the checkpoint population size %d instead of %d", saved.PopSize, config.PopSize))
+	}
+	if saved.Iterations != config.Iterations {
+		warnings = append(warnings, fmt.Sprintf("iteration budget changed from %d to %d", saved.Iterations, config.Iterations))
+	}
+	if checkpoint.Generation >= config.Iterations {
+		warnings = append(warnings, fmt.Sprintf("checkpoint is already at generation %d of %d", checkpoint.Generation, config.Iterations))
+	}
+	for _, operator := range [][3]string{
+		{"selector", saved.Selector, config.Selector},
+		{"crossover", saved.Crossover, config.Crossover},
+		{"mutator", saved.Mutator, config.Mutator},
+		{"fitness", saved.Fitness, config.Fitness},
+	} {
+		if operator[1] != operator[2] {
+			warnings = append(warnings, fmt.Sprintf("%s changed from %q to %q", operator[0], operator[1], operator[2]))
+		}
+	}
+	return warnings, nil
+}
+
+func (solver *GraphColoringSolver) Restore(checkpoint *Checkpoint) {
+	solver.seed = checkpoint.Config.Seed
+	solver.Rand = rand.New(rand.NewSource(checkpoint.Seed))
+
+	solver.PopSize = checkpoint.Config.PopSize
+	solver.population = clonePopulation(checkpoint.Population)
+	solver.generation = checkpoint.Generation
+	solver.evaluations = checkpoint.Evaluations
+	solver.elapsed = checkpoint.Elapsed
+}
+
+// clonePopulation copies population and its chromosomes, which the solver
+// recycles once they leave the population it runs, into a checkpoint or
+// out of one.
+func clonePopulation(population Population) Population {
+	clone := make(Population, len(population))
+	for i, chr := range population {
+		clone[i] = append(Chromosome(nil), chr...)
+	}
+	return clone
+}
@@ -0,0 +1,268 @@
+package ga
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SolverConfig is the serializable description of a solver, shared by the
+// command line, config files, checkpoints and run metadata. Colors 0 picks one
+// from the bounds of graph.ColorBounds, Seed 0 seeds from the clock,
+// MutationRate 0 recolors one gene per child on average and CrossoverRate 0
+// recombines every child. MaxTime, such as "5m", and TargetScore end the run
+// early as an anytime search, returning the best coloring so far. The Adapt
+// fields let the run tune those rates as it goes, starting from the
+// configured ones, the mutation rate by MutationPolicy.
+type SolverConfig struct {
+	Colors          int     `json:"colors,omitempty" yaml:"colors,omitempty"`
+	Iterations      int     `json:"iterations,omitempty" yaml:"iterations,omitempty"`
+	PopSize         int     `json:"popsize,omitempty" yaml:"popsize,omitempty"`
+	MaxTime         string  `json:"max_time,omitempty" yaml:"max_time,omitempty"`
+	TargetScore     int     `json:"target_score,omitempty" yaml:"target_score,omitempty"`
+	Seed            int64   `json:"seed,omitempty" yaml:"seed,omitempty"`
+	CheckpointEvery int     `json:"checkpoint_every,omitempty" yaml:"checkpoint_every,omitempty"`
+	MutationRate    float64 `json:"mutation_rate,omitempty" yaml:"mutation_rate,omitempty"`
+	CrossoverRate   float64 `json:"crossover_rate,omitempty" yaml:"crossover_rate,omitempty"`
+	AdaptMutation   bool    `json:"adapt_mutation,omitempty" yaml:"adapt_mutation,omitempty"`
+	AdaptCrossover  bool    `json:"adapt_crossover,omitempty" yaml:"adapt_crossover,omitempty"`
+	MutationPolicy  string  `json:"mutation_policy,omitempty" yaml:"mutation_policy,omitempty"`
+	Elitism         int     `json:"elitism,omitempty" yaml:"elitism,omitempty"`
+	Truncation      string  `json:"truncation,omitempty" yaml:"truncation,omitempty"`
+	SteadyState     bool    `json:"steady_state,omitempty" yaml:"steady_state,omitempty"`
+	Crowding        int     `json:"crowding,omitempty" yaml:"crowding,omitempty"`
+	TabuIterations  int     `json:"tabu_iterations,omitempty" yaml:"tabu_iterations,omitempty"`
+	Repair          bool    `json:"repair,omitempty" yaml:"repair,omitempty"`
+	FitnessCache    bool    `json:"fitness_cache,omitempty" yaml:"fitness_cache,omitempty"`
+	Deduplicate     bool    `json:"deduplicate,omitempty" yaml:"deduplicate,omitempty"`
+
+	// StagnationLimit 0 never acts on stagnation; StagnationAction "" means
+	// immigrants and StagnationShare 0 means half of the population.
+	StagnationLimit  int     `json:"stagnation_limit,omitempty" yaml:"stagnation_limit,omitempty"`
+	StagnationAction string  `json:"stagnation_action,omitempty" yaml:"stagnation_action,omitempty"`
+	StagnationShare  float64 `json:"stagnation_share,omitempty" yaml:"stagnation_share,omitempty"`
+
+	OperatorNames `yaml:",inline"`
+}
+
+func DefaultConfig() SolverConfig {
+	config := SolverConfig{}
+	config.Defaults()
+	return config
+}
+
+// Defaults fills every unset field that has a fixed default.
+func (config *SolverConfig) Defaults() {
+	if config.Iterations == 0 {
+		config.Iterations = DefaultNumIterations
+	}
+	if config.PopSize == 0 {
+		config.PopSize = DefaultPopSize
+	}
+	if config.Selector == "" {
+		config.Selector = "random"
+	}
+	if config.Crossover == "" {
+		config.Crossover = "segment"
+	}
+	if config.Mutator == "" {
+		config.Mutator = "random"
+	}
+	if config.Fitness == "" {
+		config.Fitness = "conflicts"
+	}
+}
+
+// Validate reports every invalid field at once.
+func (config *SolverConfig) Validate() error {
+	var problems []error
+	if config.Colors < 0 {
+		problems = append(problems, fmt.Errorf("colors must not be negative, got %d (use 0 to pick it from the graph)", config.Colors))
+	}
+	if config.Iterations < 0 {
+		problems = append(problems, fmt.Errorf("iterations must not be negative, got %d", config.Iterations))
+	}
+	if config.PopSize < 0 {
+		problems = append(problems, fmt.Errorf("popsize must not be negative, got %d", config.PopSize))
+	}
+	if config.MaxTime != "" {
+		maxTime, err := time.ParseDuration(config.MaxTime)
+		if err != nil || maxTime <= 0 {
+			problems = append(problems, fmt.Errorf("max_time must be a positive duration such as 5m, got %q", config.MaxTime))
+		}
+	}
+	if config.TargetScore < 0 {
+		problems = append(problems, fmt.Errorf("target_score must not be negative, got %d", config.TargetScore))
+	}
+	if config.CheckpointEvery < 0 {
+		problems = append(problems, fmt.Errorf("checkpoint_every must not be negative, got %d (use 0 to disable checkpoints)", config.CheckpointEvery))
+	}
+	if config.MutationRate < 0 || config.MutationRate > 1 {
+		problems = append(problems, fmt.Errorf("mutation_rate must be between 0 and 1, got %g", config.MutationRate))
+	}
+	if config.CrossoverRate < 0 || config.CrossoverRate > 1 {
+		problems = append(problems, fmt.Errorf("crossover_rate must be between 0 and 1, got %g", config.CrossoverRate))
+	}
+	if config.MutationPolicy != "" && !slices.Contains(MutationPolicies, config.MutationPolicy) {
+		problems = append(problems, fmt.Errorf("unknown mutation_policy %q, expected one of %s", config.MutationPolicy, strings.Join(MutationPolicies, ", ")))
+	}
+	if config.Elitism < 0 {
+		problems = append(problems, fmt.Errorf("elitism must not be negative, got %d", config.Elitism))
+	}
+	if config.PopSize > 0 && config.Elitism >= config.PopSize {
+		problems = append(problems, fmt.Errorf("elitism must be less than popsize %d, got %d", config.PopSize, config.Elitism))
+	}
+	if config.Truncation != "" && !slices.Contains(Truncations, config.Truncation) {
+		problems = append(problems, fmt.Errorf("unknown truncation %q, expected one of %s", config.Truncation, strings.Join(Truncations, ", ")))
+	}
+	if config.SteadyState && config.Elitism > 0 {
+		problems = append(problems, fmt.Errorf("elitism does not apply to steady_state runs, which always keep the best chromosome, got %d", config.Elitism))
+	}
+	if config.Crowding < 0 {
+		problems = append(problems, fmt.Errorf("crowding must not be negative, got %d (use 0 to disable crowding)", config.Crowding))
+	}
+	if config.Crowding > 0 && config.SteadyState {
+		problems = append(problems, errors.New("crowding and steady_state are different replacements and cannot be combined"))
+	}
+	if config.Crowding > 0 && config.Elitism > 0 {
+		problems = append(problems, fmt.Errorf("elitism does not apply to crowding runs, which always keep the best chromosome, got %d", config.Elitism))
+	}
+	if config.TabuIterations < 0 {
+		problems = append(problems, fmt.Errorf("tabu_iterations must not be negative, got %d (use 0 to disable tabu search)", config.TabuIterations))
+	}
+	if fitness, err := lookup(registry.fitnesses, "fitness", cmp.Or(config.Fitness, "conflicts")); err == nil && !countsConflicts(fitness) {
+		if config.TabuIterations > 0 {
+			problems = append(problems, fmt.Errorf("tabu_iterations only applies to the conflicts fitness, whose conflicts tabu search minimizes, got fitness %q", config.Fitness))
+		}
+		if config.Repair {
+			problems = append(problems, fmt.Errorf("repair only applies to the conflicts fitness, whose conflicts it removes, got fitness %q", config.Fitness))
+		}
+	}
+	if config.StagnationLimit < 0 {
+		problems = append(problems, fmt.Errorf("stagnation_limit must not be negative, got %d (use 0 to ignore stagnation)", config.StagnationLimit))
+	}
+	if config.StagnationAction != "" && !slices.Contains(StagnationActions, config.StagnationAction) {
+		problems = append(problems, fmt.Errorf("unknown stagnation_action %q, expected one of %s", config.StagnationAction, strings.Join(StagnationActions, ", ")))
+	}
+	if config.StagnationShare < 0 || config.StagnationShare > 1 {
+		problems = append(problems, fmt.Errorf("stagnation_share must be between 0 and 1, got %g", config.StagnationShare))
+	}
+	_, err := config.OperatorNames.Options()
+	if err != nil {
+		problems = append(problems, err)
+	}
+	return errors.Join(problems...)
+}
+
+// Options validates the config and turns it into solver options.
+func (config *SolverConfig) Options() ([]Option, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := config.OperatorNames.Options()
+	if err != nil {
+		return nil, err
+	}
+	if config.Colors > 0 {
+		options = append(options, WithColors(config.Colors))
+	}
+	if config.Iterations > 0 {
+		options = append(options, WithIterations(config.Iterations))
+	}
+	if config.PopSize > 0 {
+		options = append(options, WithPopulation(config.PopSize))
+	}
+	if config.MaxTime != "" {
+		maxTime, _ := time.ParseDuration(config.MaxTime)
+		options = append(options, WithMaxTime(maxTime))
+	}
+	if config.TargetScore > 0 {
+		options = append(options, WithTargetScore(config.TargetScore))
+	}
+	if config.Seed != 0 {
+		options = append(options, WithSeed(config.Seed))
+	}
+	if config.CheckpointEvery > 0 {
+		options = append(options, WithCheckpointEvery(config.CheckpointEvery))
+	}
+	if config.MutationRate > 0 {
+		options = append(options, WithMutationRate(config.MutationRate))
+	}
+	if config.CrossoverRate > 0 {
+		options = append(options, WithCrossoverRate(config.CrossoverRate))
+	}
+	if config.Elitism > 0 {
+		options = append(options, WithElitism(config.Elitism))
+	}
+	if config.Truncation != "" {
+		options = append(options, WithTruncation(config.Truncation))
+	}
+	if config.SteadyState {
+		options = append(options, WithSteadyState())
+	}
+	if config.Crowding > 0 {
+		options = append(options, WithCrowding(config.Crowding))
+	}
+	if config.TabuIterations > 0 {
+		options = append(options, WithTabuSearch(config.TabuIterations))
+	}
+	if config.Repair {
+		options = append(options, WithRepair())
+	}
+	if config.FitnessCache {
+		options = append(options, WithFitnessCache())
+	}
+	if config.Deduplicate {
+		options = append(options, WithDeduplication())
+	}
+	if config.StagnationLimit > 0 {
+		options = append(options, WithStagnation(config.StagnationLimit, config.StagnationAction, config.StagnationShare))
+	}
+	if config.AdaptMutation || config.AdaptCrossover {
+		options = append(options, WithAdaptation(config.AdaptMutation, config.AdaptCrossover))
+	}
+	if config.MutationPolicy != "" {
+		options = append(options, WithMutationPolicy(config.MutationPolicy))
+	}
+	return options, nil
+}
+
+// Config describes the solver as it is currently set up. Operators that were
+// not picked by name are left out.
+func (solver *GraphColoringSolver) Config() SolverConfig {
+	var maxTime string
+	if solver.MaxTime > 0 {
+		maxTime = solver.MaxTime.String()
+	}
+	return SolverConfig{
+		Colors:           solver.NumColors,
+		Iterations:       solver.NumIterations,
+		PopSize:          solver.PopSize,
+		MaxTime:          maxTime,
+		TargetScore:      solver.TargetScore,
+		Seed:             solver.seed,
+		CheckpointEvery:  solver.CheckpointEvery,
+		MutationRate:     solver.MutationRate,
+		CrossoverRate:    solver.CrossoverRate,
+		AdaptMutation:    solver.AdaptMutation,
+		AdaptCrossover:   solver.AdaptCrossover,
+		MutationPolicy:   solver.MutationPolicy,
+		Elitism:          solver.Elitism,
+		Truncation:       solver.Truncation,
+		SteadyState:      solver.SteadyState,
+		Crowding:         solver.Crowding,
+		TabuIterations:   solver.TabuIterations,
+		Repair:           solver.Repair,
+		FitnessCache:     solver.FitnessCache,
+		Deduplicate:      solver.Deduplicate,
+		StagnationLimit:  solver.StagnationLimit,
+		StagnationAction: solver.StagnationAction,
+		StagnationShare:  solver.StagnationShare,
+		OperatorNames:    solver.operatorNames,
+	}
+}
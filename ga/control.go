@@ -0,0 +1,178 @@
+package ga
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+)
+
+// The policies by which AdaptMutation can adapt the mutation rate.
+const (
+	// MutationSuccess follows the 1/5 success rule.
+	MutationSuccess = "success"
+	// MutationDiversity raises the mutation rate while the best score
+	// stalls or the population has converged and lowers it back while the
+	// best score improves.
+	MutationDiversity = "diversity"
+)
+
+// MutationPolicies lists the valid values of MutationPolicy.
+var MutationPolicies = []string{MutationSuccess, MutationDiversity}
+
+// Bounds and step sizes of the parameter control.
+const (
+	// successRate is the share of children better than their better parent
+	// the 1/5 rule aims for.
+	successRate = 0.2
+	// mutationStep is the factor the mutation rate moves by per generation.
+	mutationStep = 0.85
+	maxMutation  = 0.5
+	// minCrossover keeps both crossover and cloning in use, so that their
+	// survival can still be compared.
+	minCrossover = 0.05
+	maxCrossover = 1 - minCrossover
+	// qualityDecay weighs the latest generation in the survival averages.
+	qualityDecay = 0.3
+	// stallGenerations is how many generations without a better best score
+	// MutationDiversity counts as a stall, lowDiversity the Diversity below
+	// which it counts the population as converged and highDiversity the one
+	// from which it stops raising the mutation rate.
+	stallGenerations = 10
+	lowDiversity     = 0.05
+	highDiversity    = 0.2
+)
+
+// control holds the mutation and crossover rates of a run. With AdaptMutation
+// and MutationSuccess the mutation rate follows the 1/5 success rule: it grows
+// while more than a fifth of the children beat their better parent and shrinks
+// otherwise. With MutationDiversity it grows after every stallGenerations
+// generations without a better best score and every generation whose Diversity
+// is below lowDiversity, unless Diversity is highDiversity or more, and
+// shrinks back towards the starting rate whenever the best score improves.
+// With AdaptCrossover the crossover rate matches the share of survivors bred
+// by crossover rather than by cloning a parent. The rates start over from the
+// configured values when a checkpoint is resumed.
+type control struct {
+	adaptMutation  bool
+	adaptCrossover bool
+	mutationPolicy string
+	genes          int
+	mutationRate   float64
+	crossoverRate  float64
+	// baseMutation is the mutation rate the run started with.
+	baseMutation float64
+
+	// crossed is whether the child being bred was recombined.
+	crossed   bool
+	children  []bred
+	successes int
+	// best is the best score so far and stalled how many generations it
+	// has not improved for, for MutationDiversity.
+	best    int
+	stalled int
+
+	crossoverQuality float64
+	cloneQuality     float64
+}
+
+type bred struct {
+	score   int
+	crossed bool
+}
+
+func (solver *GraphColoringSolver) newControl() *control {
+	control := &control{
+		adaptMutation:  solver.AdaptMutation,
+		adaptCrossover: solver.AdaptCrossover,
+		mutationPolicy: cmp.Or(solver.MutationPolicy, MutationSuccess),
+		genes:          solver.Graph.NodeCount(),
+		mutationRate:   solver.MutationRate,
+		crossoverRate:  solver.CrossoverRate,
+		best:           -1,
+	}
+	if control.mutationRate <= 0 {
+		control.mutationRate = 1 / float64(max(control.genes, 1))
+	}
+	control.baseMutation = control.mutationRate
+	if control.crossoverRate <= 0 {
+		control.crossoverRate = 1
+	}
+	if control.adaptCrossover {
+		control.crossoverRate = math.Min(math.Max(control.crossoverRate, minCrossover), maxCrossover)
+		control.crossoverQuality, control.cloneQuality = 0.5, 0.5
+	}
+	return control
+}
+
+// cross draws whether the next child is recombined rather than cloned.
+func (control *control) cross(rng *rand.Rand) bool {
+	control.crossed = control.crossoverRate >= 1 || rng.Float64() < control.crossoverRate
+	return control.crossed
+}
+
+// child records a bred child and the best score among its parents.
+func (control *control) child(score int, parentScore int) {
+	control.children = append(control.children, bred{score: score, crossed: control.crossed})
+	if score < parentScore {
+		control.successes++
+	}
+}
+
+// adapt updates the rates from the children of the generation that produced
+// the population with scores, sorted best first, which has diversity.
+func (control *control) adapt(scores []int, diversity float64) {
+	if len(control.children) == 0 {
+		return
+	}
+	if control.adaptMutation {
+		switch control.mutationPolicy {
+		case MutationSuccess:
+			rate := float64(control.successes) / float64(len(control.children))
+			switch {
+			case rate > successRate:
+				control.mutationRate /= mutationStep
+			case rate < successRate:
+				control.mutationRate *= mutationStep
+			}
+		case MutationDiversity:
+			if control.best < 0 || scores[0] < control.best {
+				control.best, control.stalled = scores[0], 0
+				control.mutationRate = math.Max(control.mutationRate*mutationStep, control.baseMutation)
+			} else if control.stalled++; (control.stalled >= stallGenerations || diversity < lowDiversity) && diversity < highDiversity {
+				control.stalled = 0
+				control.mutationRate /= mutationStep
+			}
+		}
+		control.mutationRate = math.Min(math.Max(control.mutationRate, 0.1/float64(max(control.genes, 1))), maxMutation)
+	}
+	if control.adaptCrossover {
+		// Children that tie with the worst survivor count as survivors.
+		worst := scores[len(scores)-1]
+		var crossed, crossedSurvived, cloned, clonedSurvived int
+		for _, child := range control.children {
+			survived := 0
+			if child.score <= worst {
+				survived = 1
+			}
+			if child.crossed {
+				crossed++
+				crossedSurvived += survived
+			} else {
+				cloned++
+				clonedSurvived += survived
+			}
+		}
+		if crossed > 0 {
+			control.crossoverQuality += qualityDecay * (float64(crossedSurvived)/float64(crossed) - control.crossoverQuality)
+		}
+		if cloned > 0 {
+			control.cloneQuality += qualityDecay * (float64(clonedSurvived)/float64(cloned) - control.cloneQuality)
+		}
+		total := control.crossoverQuality + control.cloneQuality
+		if total > 0 {
+			control.crossoverRate = minCrossover + (maxCrossover-minCrossover)*control.crossoverQuality/total
+		}
+	}
+	control.children = control.children[:0]
+	control.successes = 0
+}
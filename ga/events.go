@@ -0,0 +1,67 @@
+package ga
+
+import (
+	"time"
+)
+
+// Event is one of GenerationCompleted, NewBest, Restarted, Checkpointed or
+// Terminated, delivered synchronously to every subscriber from the goroutine
+// running Solve. The chromosomes of GenerationCompleted and NewBest
+// are reused for later children once they leave the population, so
+// subscribers that keep them must copy them.
+type Event interface {
+	event()
+}
+
+type GenerationCompleted struct {
+	Stats GenerationStats
+	Best  Chromosome
+}
+
+// NewBest is emitted after GenerationCompleted whenever the best score
+// improves on every earlier generation of the current Solve call.
+type NewBest struct {
+	Generation int
+	Score      int
+	Coloring   Chromosome
+	Elapsed    time.Duration
+}
+
+// Restarted is emitted when the population is replaced by a fresh one.
+type Restarted struct {
+	Generation int
+}
+
+type Checkpointed struct {
+	Checkpoint Checkpoint
+}
+
+type Terminated struct {
+	Solution GraphColoringSolution
+}
+
+func (GenerationCompleted) event() {}
+func (NewBest) event()             {}
+func (Restarted) event()           {}
+func (Checkpointed) event()        {}
+func (Terminated) event()          {}
+
+type Subscriber interface {
+	Notify(event Event)
+}
+
+type SubscriberFunc func(event Event)
+
+func (f SubscriberFunc) Notify(event Event) {
+	f(event)
+}
+
+func (solver *GraphColoringSolver) Subscribe(subscriber Subscriber) {
+	solver.subscribers = append(solver.subscribers, subscriber)
+}
+
+func (solver *GraphColoringSolver) emit(event Event) {
+	for _, subscriber := range solver.subscribers {
+		subscriber.Notify(event)
+	}
+}
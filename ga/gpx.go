@@ -0,0 +1,67 @@
+package ga
+
+// GPXCrossover is the greedy partition crossover of Galinier and Hao. Colors
+// only name the classes of a partition, so rather than copying genes it
+// builds the child class by class: color c goes to the largest class left in
+// parent c mod len(parents), whose nodes are then dropped from every parent.
+// Nodes left once solver.NumColors classes are taken get random colors.
+type GPXCrossover struct{}
+
+func (GPXCrossover) Recombine(solver *GraphColoringSolver, parents []Chromosome) Chromosome {
+	length := len(parents[0])
+	child := solver.newChromosome(length)
+	for i := range child {
+		child[i] = Unassigned
+	}
+
+	// sizes[p*palette+color] is how many nodes left parent p gives color.
+	palette := solver.NumColors
+	for _, parent := range parents {
+		for _, color := range parent {
+			palette = max(palette, color+1)
+		}
+	}
+	sizes := cleared(solver.scratch.sizes, len(parents)*palette)
+	solver.scratch.sizes = sizes
+	for p, parent := range parents {
+		for _, color := range parent {
+			if color >= 0 {
+				sizes[p*palette+color]++
+			}
+		}
+	}
+
+	left := length
+	for color := 0; color < solver.NumColors && left > 0; color++ {
+		p := color % len(parents)
+		classes := sizes[p*palette : (p+1)*palette]
+		largest := 0
+		for class, size := range classes {
+			if size > classes[largest] {
+				largest = class
+			}
+		}
+		if classes[largest] == 0 {
+			continue
+		}
+		for i, class := range parents[p] {
+			if child[i] != Unassigned || class != largest {
+				continue
+			}
+			child[i] = color
+			left--
+			for q, parent := range parents {
+				if parent[i] >= 0 {
+					sizes[q*palette+parent[i]]--
+				}
+			}
+		}
+	}
+
+	for i := range child {
+		if child[i] == Unassigned {
+			child[i] = solver.Rand.Intn(solver.NumColors)
+		}
+	}
+	return child
+}
@@ -0,0 +1,93 @@
+package ga
+
+import (
+	"time"
+)
+
+type GenerationStats struct {
+	Generation  int
+	Best        int
+	Mean        float64
+	Worst       int
+	Diversity   float64
+	Evaluations int
+	Elapsed     time.Duration
+	// MutationRate and CrossoverRate are the rates the generation was bred
+	// with.
+	MutationRate  float64
+	CrossoverRate float64
+}
+
+func (solver *GraphColoringSolver) generationStats(generation int, population Population, scores []int, elapsed time.Duration) GenerationStats {
+	stats := GenerationStats{
+		Generation:  generation,
+		Best:        scores[0],
+		Worst:       scores[0],
+		Evaluations: solver.evaluations,
+		Elapsed:     elapsed,
+	}
+	if solver.control != nil {
+		stats.MutationRate = solver.control.mutationRate
+		stats.CrossoverRate = solver.control.crossoverRate
+	}
+
+	total := 0
+	for _, score := range scores {
+		if score < stats.Best {
+			stats.Best = score
+		}
+		if score > stats.Worst {
+			stats.Worst = score
+		}
+		total += score
+	}
+	stats.Mean = float64(total) / float64(len(scores))
+	stats.Diversity = solver.Diversity(population)
+
+	return stats
+}
+
+// Diversity is the share of genes that differ from the most common color at
+// their position, averaged over all positions: 0 for a population of clones.
+func (solver *GraphColoringSolver) Diversity(population Population) float64 {
+	if len(population) == 0 {
+		return 0
+	}
+
+	nodeCount := len(population[0])
+	if nodeCount == 0 {
+		return 0
+	}
+
+	counts := make([]int, solver.NumColors)
+	total := 0
+	for i := 0; i < nodeCount; i++ {
+		for j := range counts {
+			counts[j] = 0
+		}
+		mostCommon := 0
+		for _, chr := range population {
+			counts[chr[i]]++
+			if counts[chr[i]] > mostCommon {
+				mostCommon = counts[chr[i]]
+			}
+		}
+		total += len(population) - mostCommon
+	}
+
+	return float64(total) / float64(nodeCount*len(population))
+}
+
+type History []GenerationStats
+
+// hammingDistance counts the genes in which a and b differ, which is how
+// Crowding tells chromosomes apart.
+func hammingDistance(a Chromosome, b Chromosome) int {
+	distance := 0
+	for i, color := range a {
+		if b[i] != color {
+			distance++
+		}
+	}
+	return distance
+}
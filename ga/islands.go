@@ -0,0 +1,86 @@
+package ga
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// SolveIslands runs the solvers in parallel as the islands of a ring: every
+// every generations each island sends copies of its count best chromosomes
+// to the next one and takes in those the previous one sent last. Islands do
+// not wait for their neighbours, so a slow island never holds the others up;
+// immigrants that arrive before it looks are replaced by newer ones. All
+// islands stop once one reaches its TargetScore.
+//
+// For the run only, the Migration of every solver is replaced by the ring's
+// and its Logger names the island, both restored once it ends.
+//
+// The solution is the best coloring any island found, with the evaluations
+// of all of them.
+func SolveIslands(ctx context.Context, solvers []*GraphColoringSolver, every, count int) GraphColoringSolution {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mutex sync.Mutex
+	// mailboxes[i] holds the emigrants island i has yet to take in.
+	mailboxes := make([]Population, len(solvers))
+	solutions := make([]GraphColoringSolution, len(solvers))
+	var wg sync.WaitGroup
+	for i, solver := range solvers {
+		logger, migration := solver.Logger, solver.Migration
+		solver.Logger = cmp.Or(logger, slog.Default()).With("island", i)
+		solver.Migration = nil
+		if len(solvers) > 1 && every > 0 && count > 0 {
+			next := (i + 1) % len(solvers)
+			solver.Migration = &Migration{Every: every, Count: count, Exchange: func(generation int, emigrants Population) Population {
+				mutex.Lock()
+				defer mutex.Unlock()
+				mailboxes[next] = emigrants
+				immigrants := mailboxes[i]
+				mailboxes[i] = nil
+				return immigrants
+			}}
+		}
+		// The final population need not hold the best coloring of the run,
+		// so the island keeps it as it is found.
+		var best *NewBest
+		keepBest := SubscriberFunc(func(event Event) {
+			improvement, ok := event.(NewBest)
+			if !ok {
+				return
+			}
+			improvement.Coloring = append(Chromosome(nil), improvement.Coloring...)
+			best = &improvement
+			if improvement.Score <= solver.TargetScore {
+				cancel()
+			}
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			solution := solver.solve(ctx, keepBest)
+			if best != nil && best.Score < solution.Score {
+				solution.Coloring = best.Coloring
+				solution.Score = best.Score
+			}
+			solver.Logger.Debug("island finished", "score", solution.Score, "generations", solution.Metadata.Generations)
+			solver.Logger, solver.Migration = logger, migration
+			solutions[i] = solution
+		}()
+	}
+	wg.Wait()
+
+	best, evaluations := 0, 0
+	for i, solution := range solutions {
+		evaluations += solution.Metadata.Evaluations
+		if solution.Score < solutions[best].Score {
+			best = i
+		}
+	}
+	solution := solutions[best]
+	solution.Metadata.Evaluations = evaluations
+	return solution
+}
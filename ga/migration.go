@@ -0,0 +1,54 @@
+package ga
+
+import (
+	"github.com/packedbread/gen-alg-graph-coloring/evo"
+)
+
+// Migration connects the population to others, as in the island model: every
+// Every generations Exchange receives copies of the best Count chromosomes and
+// returns immigrants, which replace the worst chromosomes. Traces do not
+// record immigrants, so runs with migration cannot be replayed.
+type Migration struct {
+	Every    int
+	Count    int
+	Exchange func(generation int, emigrants Population) Population
+}
+
+func (solver *GraphColoringSolver) migrate(generation int, population Population, scores []int) {
+	count := min(solver.Migration.Count, len(population))
+	emigrants := make(Population, count)
+	for i := range emigrants {
+		emigrants[i] = append(Chromosome(nil), population[i]...)
+	}
+	immigrants := solver.Migration.Exchange(generation, emigrants)
+
+	scored := make([]evo.Scored[Chromosome], len(population))
+	for i := range population {
+		scored[i] = evo.Scored[Chromosome]{Genome: population[i], Score: scores[i]}
+	}
+	replaced := 0
+	for _, chr := range immigrants {
+		if replaced == len(scored) || !solver.validChromosome(chr) {
+			continue
+		}
+		chr = append(Chromosome(nil), chr...)
+		replaced++
+		scored[len(scored)-replaced] = evo.Scored[Chromosome]{Genome: chr, Score: solver.CalculateFitness(chr)}
+	}
+	if replaced < len(immigrants) {
+		solver.Logger.Warn("dropped immigrants", "generation", generation, "count", len(immigrants)-replaced)
+	}
+	evo.Truncate(population, scores, scored)
+}
+
+func (solver *GraphColoringSolver) validChromosome(chr Chromosome) bool {
+	if len(chr) != solver.Graph.NodeCount() {
+		return false
+	}
+	for _, color := range chr {
+		if color < 0 || color >= solver.NumColors {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package ga
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// MinimizeColors searches for the fewest colors that admit a proper coloring
+// within the budget of NumIterations generations and MaxTime, shared by all
+// color counts; TargetScore is ignored.
+// Starting from a DSATUR coloring it removes one color class at a time: the
+// population for k colors is bred from the best coloring with k+1 by moving the
+// nodes of one of its classes to the other classes, and evolves with one color
+// to spare under PenaltyFitness until a proper coloring with k colors turns up.
+// With MinimizeFromRandom every color count starts from a random population
+// instead.
+// It stops at the clique lower bound, when a color count is not reached in
+// the budget, or when ctx is done, and returns the proper coloring with the
+// fewest colors, whose Config records how many.
+func (solver *GraphColoringSolver) MinimizeColors(ctx context.Context) GraphColoringSolution {
+	solver.acquire()
+	defer solver.running.Unlock()
+	solver.setDefaults()
+	base, target := solver.Fitness, solver.TargetScore
+	solver.TargetScore = 0
+	defer func() {
+		solver.Fitness, solver.TargetScore = base, target
+	}()
+
+	bounds := graph.ColorBounds(solver.Graph)
+	colors := bounds.DSatur
+	solution := GraphColoringSolution{Coloring: graph.DSatur(solver.Graph)}
+	startedAt := time.Now()
+	solution.Metadata = RunMetadata{GraphHash: graph.Hash(solver.Graph), Config: solver.Config(), StartedAt: startedAt}
+	solution.Metadata.Config.Colors = colors
+	solver.Logger.Info("minimizing colors", "dsatur", bounds.DSatur, "lower", bounds.Lower)
+
+	for colors > bounds.Lower && solver.generation < solver.NumIterations && (solver.MaxTime == 0 || solver.elapsed < solver.MaxTime) && ctx.Err() == nil {
+		solver.NumColors = colors
+		solver.Fitness = PenaltyFitness{Fitness: base, Colors: colors - 1}
+		solver.population = nil
+		if !solver.MinimizeFromRandom {
+			solver.population = solver.removeClasses(solution.Coloring)
+		}
+		solver.emit(Restarted{Generation: solver.generation})
+		attempt := solver.evolve(ctx)
+		solver.population = nil
+		solver.generation = attempt.Metadata.Generations
+		solver.elapsed = attempt.Metadata.Elapsed
+		if attempt.Score != 0 {
+			break
+		}
+		colors--
+		attempt.Metadata.Config.Colors = colors
+		attempt.Metadata.StartedAt = startedAt
+		solution = attempt
+		solver.Logger.Info("colors reduced", "colors", colors, "generation", solver.generation, "elapsed", solver.elapsed)
+	}
+	solution.Metadata.FinishedAt = time.Now()
+	solution.Metadata.Elapsed = solver.elapsed
+	solution.Metadata.Generations = solver.generation
+	solution.Metadata.Evaluations = solver.evaluations
+	solver.emit(Terminated{Solution: solution})
+	return solution
+}
+
+// removeClasses returns a population with one color fewer than coloring, which
+// uses solver.NumColors: its first members each remove one of the color
+// classes of coloring, smallest first, and the others are mutated copies of
+// those.
+func (solver *GraphColoringSolver) removeClasses(coloring Chromosome) Population {
+	sizes := make([]int, solver.NumColors)
+	for _, color := range coloring {
+		sizes[color]++
+	}
+	classes := make([]int, solver.NumColors)
+	for i := range classes {
+		classes[i] = i
+	}
+	slices.SortStableFunc(classes, func(a, b int) int {
+		return sizes[a] - sizes[b]
+	})
+
+	population := make(Population, solver.PopSize)
+	for i := range population {
+		population[i] = removeClass(solver.Graph, coloring, classes[i%len(classes)])
+		if i >= len(classes) {
+			population[i] = solver.Mutator.Mutate(solver, population[i])
+		}
+	}
+	return population
+}
+
+// removeClass returns a copy of coloring without the color class, moving every
+// node of it to the color fewest of its neighbours have, and numbering the
+// colors above class one lower.
+func removeClass(g graph.Interface, coloring Chromosome, class int) Chromosome {
+	colors := 0
+	for _, color := range coloring {
+		colors = max(colors, color+1)
+	}
+	result := make(Chromosome, len(coloring))
+	for node, color := range coloring {
+		switch {
+		case color > class:
+			result[node] = color - 1
+		case color < class:
+			result[node] = color
+		default:
+			result[node] = -1
+		}
+	}
+	counts := make([]int, max(colors-1, 1))
+	for node, color := range coloring {
+		if color != class {
+			continue
+		}
+		clear(counts)
+		for _, neighbour := range g.Neighbors(node) {
+			if result[neighbour] >= 0 {
+				counts[result[neighbour]]++
+			}
+		}
+		result[node] = 0
+		for color, count := range counts {
+			if count < counts[result[node]] {
+				result[node] = color
+			}
+		}
+	}
+	return result
+}
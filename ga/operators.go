@@ -0,0 +1,282 @@
+package ga
+
+import (
+	"slices"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// Selector picks the parents of one child and returns their indices in the
+// population. scores[i] is the fitness of population[i], lower is better.
+// The indices are only read until the next call, so it may reuse the slice.
+//
+// Like the other operators it receives the solver to draw from solver.Rand and
+// read solver.Graph and solver.NumColors.
+type Selector interface {
+	Select(solver *GraphColoringSolver, population Population, scores []int) []int
+}
+
+// Crossover combines the selected parents into a new child, which must not
+// share its backing array with any parent.
+type Crossover interface {
+	Recombine(solver *GraphColoringSolver, parents []Chromosome) Chromosome
+}
+
+// Mutator changes a freshly recombined child in place and returns it.
+type Mutator interface {
+	Mutate(solver *GraphColoringSolver, child Chromosome) Chromosome
+}
+
+// Fitness scores a coloring, lower is better and 0 means the coloring is
+// proper.
+type Fitness interface {
+	Evaluate(g graph.Interface, chromosome Chromosome) int
+}
+
+// DeltaFitness is a Fitness that can score a chromosome from the score of one
+// it differs from only in the genes listed in changed, in increasing order.
+// It must return what Evaluate would.
+type DeltaFitness interface {
+	Fitness
+	Delta(g graph.Interface, from Chromosome, to Chromosome, changed []int) int
+}
+
+// RandomSelector picks Count distinct parents uniformly at random.
+type RandomSelector struct {
+	Count int
+}
+
+func (selector RandomSelector) Select(solver *GraphColoringSolver, population Population, scores []int) []int {
+	popSize := len(population)
+
+	parentsCount := selector.Count
+	if parentsCount < 1 {
+		parentsCount = 2
+	}
+	parents := solver.scratch.selected[:0]
+	for i := 0; i < parentsCount; i++ {
+		var parentIndex int
+		for j := 0; j < 10; j++ {
+			parentIndex = solver.Rand.Intn(popSize)
+			if !slices.Contains(parents, parentIndex) {
+				break
+			}
+		}
+		parents = append(parents, parentIndex)
+	}
+	solver.scratch.selected = parents
+
+	return parents
+}
+
+// SegmentCrossover cuts the chromosome into one equal segment per parent and
+// copies every segment from a randomly chosen parent.
+type SegmentCrossover struct{}
+
+func (SegmentCrossover) Recombine(solver *GraphColoringSolver, parents []Chromosome) Chromosome {
+	chromosomeLength := len(parents[0])
+	res := solver.newChromosome(chromosomeLength)
+	partsCount := len(parents)
+	partLength := chromosomeLength / partsCount
+	if partLength < 1 {
+		partLength = 1
+	}
+
+	for currentIndex := 0; currentIndex < chromosomeLength; {
+		nextIndex := currentIndex + partLength
+		if chromosomeLength < nextIndex {
+			nextIndex = chromosomeLength
+		}
+		parentIndex := solver.Rand.Intn(len(parents))
+		copy(res[currentIndex:nextIndex], parents[parentIndex][currentIndex:nextIndex])
+
+		currentIndex = nextIndex
+	}
+
+	return res
+}
+
+// UniformCrossover copies every gene from a randomly chosen parent.
+type UniformCrossover struct{}
+
+func (UniformCrossover) Recombine(solver *GraphColoringSolver, parents []Chromosome) Chromosome {
+	child := solver.newChromosome(len(parents[0]))
+	for i := range child {
+		child[i] = parents[solver.Rand.Intn(len(parents))][i]
+	}
+	return child
+}
+
+// PointCrossover cuts the chromosome at Points random places, 1 if unset, and
+// copies the pieces from the parents in turn, starting from a random one.
+type PointCrossover struct {
+	Points int
+}
+
+func (crossover PointCrossover) Recombine(solver *GraphColoringSolver, parents []Chromosome) Chromosome {
+	chromosomeLength := len(parents[0])
+	points := max(crossover.Points, 1)
+	cuts := cleared(solver.scratch.cuts, chromosomeLength)
+	solver.scratch.cuts = cuts
+	if chromosomeLength > 1 {
+		solver.scratch.order = perm(solver, solver.scratch.order, chromosomeLength-1)
+		for _, cut := range solver.scratch.order[:min(points, chromosomeLength-1)] {
+			cuts[cut+1] = true
+		}
+	}
+
+	child := solver.newChromosome(chromosomeLength)
+	parentIndex := solver.Rand.Intn(len(parents))
+	for i := range child {
+		if cuts[i] {
+			parentIndex = (parentIndex + 1) % len(parents)
+		}
+		child[i] = parents[parentIndex][i]
+	}
+	return child
+}
+
+// RandomMutator recolors every gene with probability solver.MutationRate, or
+// 1/len(child) if that is unset, or with the adapted rate while the solver
+// adapts it.
+type RandomMutator struct{}
+
+func (RandomMutator) Mutate(solver *GraphColoringSolver, child Chromosome) Chromosome {
+	mutationProb := mutationProbability(solver, child)
+	for i := 0; i < len(child); i++ {
+		if solver.Rand.Float32() < mutationProb {
+			child[i] = solver.Rand.Intn(solver.NumColors)
+		}
+	}
+
+	return child
+}
+
+// ConflictMutator recolors only nodes that share a color with a neighbour,
+// each to the color fewest of its neighbours have, ties broken at random. It
+// recolors as many genes on average as RandomMutator would, spread over the
+// conflicting nodes, and all of them once there are fewer.
+type ConflictMutator struct{}
+
+func (ConflictMutator) Mutate(solver *GraphColoringSolver, child Chromosome) Chromosome {
+	conflicting := solver.scratch.conflicting[:0]
+	for i := 0; i < len(child); i++ {
+		for _, neighbour := range solver.Graph.Neighbors(i) {
+			if child[neighbour] == child[i] {
+				conflicting = append(conflicting, i)
+				break
+			}
+		}
+	}
+	solver.scratch.conflicting = conflicting
+	if len(conflicting) == 0 {
+		return child
+	}
+
+	mutationProb := min(mutationProbability(solver, child)*float32(len(child))/float32(len(conflicting)), 1)
+	counts := cleared(solver.scratch.counts, solver.NumColors)
+	solver.scratch.counts = counts
+	for _, i := range conflicting {
+		if solver.Rand.Float32() >= mutationProb {
+			continue
+		}
+		clear(counts)
+		for _, neighbour := range solver.Graph.Neighbors(i) {
+			if child[neighbour] >= 0 && child[neighbour] < len(counts) {
+				counts[child[neighbour]]++
+			}
+		}
+		best, ties := 0, 0
+		for color, count := range counts {
+			switch {
+			case color == 0 || count < counts[best]:
+				best, ties = color, 1
+			case count == counts[best]:
+				ties++
+				if solver.Rand.Intn(ties) == 0 {
+					best = color
+				}
+			}
+		}
+		child[i] = best
+	}
+	return child
+}
+
+// mutationProbability is the probability with which mutators change every
+// gene of child: the adapted rate while the solver adapts it, or
+// solver.MutationRate, or 1/len(child) if that is unset.
+func mutationProbability(solver *GraphColoringSolver, child Chromosome) float32 {
+	switch {
+	case solver.control != nil && solver.AdaptMutation:
+		return float32(solver.control.mutationRate)
+	case solver.MutationRate > 0:
+		return float32(solver.MutationRate)
+	}
+	return 1.0 / float32(len(child))
+}
+
+// ConflictFitness counts the edges whose endpoints share a color.
+type ConflictFitness struct{}
+
+func (ConflictFitness) Evaluate(g graph.Interface, chromosome Chromosome) int {
+	if counter, ok := g.(graph.ConflictCounter); ok {
+		return counter.CountConflicts(chromosome)
+	}
+	score := 0
+	for i := 0; i < g.NodeCount(); i++ {
+		for _, j := range g.Edges(i) {
+			if chromosome[i] == chromosome[j] {
+				score++
+			}
+		}
+	}
+	return score
+}
+
+// Delta counts the conflicts the changed genes add and remove. It relies on
+// Neighbors listing a node once for every edge to it, as graph.Graph does.
+func (ConflictFitness) Delta(g graph.Interface, from Chromosome, to Chromosome, changed []int) int {
+	delta := 0
+	for _, i := range changed {
+		for _, j := range g.Neighbors(i) {
+			// Edges between two changed nodes are counted from the larger one.
+			if j == i {
+				continue
+			}
+			if _, found := slices.BinarySearch(changed, j); found && j > i {
+				continue
+			}
+			if to[i] == to[j] {
+				delta++
+			}
+			if from[i] == from[j] {
+				delta--
+			}
+		}
+	}
+	return delta
+}
+
+// PenaltyFitness is Fitness, or ConflictFitness if that is nil, plus one for
+// every node with a color of Colors or more. Giving the solver one color more
+// than Colors lets the search pass through colorings that still use it, while
+// 0 still means a proper coloring with Colors colors.
+type PenaltyFitness struct {
+	Fitness Fitness
+	Colors  int
+}
+
+func (fitness PenaltyFitness) Evaluate(g graph.Interface, chromosome Chromosome) int {
+	base := fitness.Fitness
+	if base == nil {
+		base = ConflictFitness{}
+	}
+	score := base.Evaluate(g, chromosome)
+	for _, color := range chromosome {
+		if color >= fitness.Colors {
+			score++
+		}
+	}
+	return score
+}
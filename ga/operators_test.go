@@ -0,0 +1,98 @@
+package ga
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// deltaGraphs are the graphs Delta is checked on, in every representation
+// NewSolver may hand it.
+func deltaGraphs() map[string]graph.Interface {
+	rng := rand.New(rand.NewSource(1))
+	sparse := graph.NewRandomGraph(rng, 80, 0.05)
+	dense := graph.NewRandomGraph(rng, 60, 0.5)
+
+	corners := graph.New(6)
+	corners.AddEdge(0, 0)
+	corners.AddEdge(0, 1)
+	corners.AddEdge(1, 0)
+	corners.AddEdge(2, 3)
+	corners.AddEdge(2, 3)
+	corners.AddEdge(4, 5)
+
+	graphs := map[string]graph.Interface{}
+	for name, g := range map[string]*graph.Graph{"sparse": &sparse, "dense": &dense, "corners": corners} {
+		graphs[name] = g
+		graphs[name+"/csr"] = graph.NewCSR(g)
+		if bitset, ok := graph.NewBitset(g); ok {
+			graphs[name+"/bitset"] = bitset
+		}
+	}
+	return graphs
+}
+
+// randomChange recolors a random set of genes of from, returning the child
+// and the genes that changed in increasing order.
+func randomChange(rng *rand.Rand, from Chromosome, colors int, genes int) (Chromosome, []int) {
+	to := slices.Clone(from)
+	var changed []int
+	for _, i := range rng.Perm(len(from))[:genes] {
+		to[i] = (from[i] + 1 + rng.Intn(colors-1)) % colors
+		changed = append(changed, i)
+	}
+	slices.Sort(changed)
+	return to, changed
+}
+
+func TestConflictDeltaMatchesEvaluate(t *testing.T) {
+	fitness := ConflictFitness{}
+	for name, g := range deltaGraphs() {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(2))
+			nodeCount := g.NodeCount()
+			for range 200 {
+				from := make(Chromosome, nodeCount)
+				for i := range from {
+					from[i] = rng.Intn(3)
+				}
+				to, changed := randomChange(rng, from, 3, rng.Intn(nodeCount+1))
+				want := fitness.Evaluate(g, to)
+				if got := fitness.Evaluate(g, from) + fitness.Delta(g, from, to, changed); got != want {
+					t.Fatalf("score from delta over %v = %d, want %d", changed, got, want)
+				}
+			}
+		})
+	}
+}
+
+func benchmarkConflictScore(b *testing.B, delta bool) {
+	rng := rand.New(rand.NewSource(1))
+	lists := graph.NewRandomGraph(rng, 1000, 0.05)
+	g := graph.NewCSR(&lists)
+	from := make(Chromosome, g.NodeCount())
+	for i := range from {
+		from[i] = rng.Intn(20)
+	}
+	// A child of RandomMutator differs from its parent in a gene or two.
+	to, changed := randomChange(rng, from, 20, 2)
+	fitness := ConflictFitness{}
+	score := fitness.Evaluate(g, from)
+	for b.Loop() {
+		if delta {
+			_ = score + fitness.Delta(g, from, to, changed)
+		} else {
+			fitness.Evaluate(g, to)
+		}
+	}
+}
+
+func BenchmarkConflictEvaluate(b *testing.B) {
+	benchmarkConflictScore(b, false)
+}
+
+func BenchmarkConflictDelta(b *testing.B) {
+	benchmarkConflictScore(b, true)
+}
@@ -0,0 +1,262 @@
+package ga
+
+import (
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+const (
+	DefaultNumIterations = 100000
+	DefaultPopSize       = 200
+)
+
+type Option func(solver *GraphColoringSolver)
+
+// NewSolver returns a solver for g with options applied in order, seeded from
+// the current time and using the default parameters and operators for what
+// they leave unset. Without WithColors the color budget is only picked once a
+// run starts, see setColors. A *graph.Graph is solved as the graph.Compact
+// copy of it, which scores faster; callers running many solvers on one graph
+// can share one copy.
+func NewSolver(g graph.Interface, options ...Option) *GraphColoringSolver {
+	if lists, ok := g.(*graph.Graph); ok && lists != nil {
+		g = graph.Compact(lists)
+	}
+	solver := &GraphColoringSolver{Graph: g}
+	for _, option := range options {
+		option(solver)
+	}
+	solver.setDefaults()
+	return solver
+}
+
+func WithColors(numColors int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.NumColors = numColors
+	}
+}
+
+func WithIterations(numIterations int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.NumIterations = numIterations
+	}
+}
+
+func WithPopulation(popSize int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.PopSize = popSize
+	}
+}
+
+func WithSeed(seed int64) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Seed(seed)
+	}
+}
+
+// WithRand makes the solver draw from rng instead of a source it seeds itself.
+// The run metadata then records seed 0, as the seed is not known.
+func WithRand(rng *rand.Rand) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.seed = 0
+		solver.Rand = rng
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Logger = logger
+	}
+}
+
+// WithTracer records the spans of every run with tracer instead of the global
+// OpenTelemetry tracer provider.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Tracer = tracer
+	}
+}
+
+func WithSelector(selector Selector) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Selector = selector
+		solver.operatorNames.Selector = ""
+	}
+}
+
+func WithCrossover(crossover Crossover) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Crossover = crossover
+		solver.operatorNames.Crossover = ""
+	}
+}
+
+func WithMutator(mutator Mutator) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Mutator = mutator
+		solver.operatorNames.Mutator = ""
+	}
+}
+
+func WithFitness(fitness Fitness) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Fitness = fitness
+		solver.operatorNames.Fitness = ""
+	}
+}
+
+// WithPrecoloring keeps the colored nodes of precoloring at their colors and
+// searches colors for the Unassigned ones only.
+func WithPrecoloring(precoloring Chromosome) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Precoloring = precoloring
+	}
+}
+
+func WithTrace(trace *Trace) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Trace = trace
+	}
+}
+
+func WithSubscriber(subscriber Subscriber) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Subscribe(subscriber)
+	}
+}
+
+func WithMutationRate(rate float64) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.MutationRate = rate
+	}
+}
+
+func WithCrossoverRate(rate float64) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.CrossoverRate = rate
+	}
+}
+
+// WithElitism carries the count best chromosomes of every generation into the
+// next one.
+func WithElitism(count int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Elitism = count
+	}
+}
+
+// WithTruncation picks the surviving children as truncation, one of
+// Truncations, says.
+func WithTruncation(truncation string) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Truncation = truncation
+	}
+}
+
+// WithSteadyState replaces the worst chromosome with every child as it is
+// bred rather than the population once a generation.
+func WithSteadyState() Option {
+	return func(solver *GraphColoringSolver) {
+		solver.SteadyState = true
+	}
+}
+
+// WithCrowding replaces the population by deterministic crowding with
+// crowding factor factor, see Crowding.
+func WithCrowding(factor int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Crowding = factor
+	}
+}
+
+// WithRepair greedily repairs every child bred by crossover before it is
+// mutated. Like WithTabuSearch it only applies to runs that minimize
+// conflicts.
+func WithRepair() Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Repair = true
+	}
+}
+
+// WithFitnessCache skips scoring chromosomes scored in the current or the
+// previous generation.
+func WithFitnessCache() Option {
+	return func(solver *GraphColoringSolver) {
+		solver.FitnessCache = true
+	}
+}
+
+// WithDeduplication replaces the chromosomes that repeat another in the
+// population with random ones every generation.
+func WithDeduplication() Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Deduplicate = true
+	}
+}
+
+// WithTabuSearch improves every child by up to iterations moves of tabu
+// search after mutation. It only applies to runs that minimize conflicts,
+// with ConflictFitness or PenaltyFitness over it.
+func WithTabuSearch(iterations int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.TabuIterations = iterations
+	}
+}
+
+// WithStagnation takes action, one of StagnationActions, on share of the
+// population whenever the best score has not improved for limit generations.
+func WithStagnation(limit int, action string, share float64) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.StagnationLimit = limit
+		solver.StagnationAction = action
+		solver.StagnationShare = share
+	}
+}
+
+// WithMaxTime stops the run once it has taken d.
+func WithMaxTime(d time.Duration) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.MaxTime = d
+	}
+}
+
+// WithTargetScore stops the run once the best score is score or lower.
+func WithTargetScore(score int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.TargetScore = score
+	}
+}
+
+// WithAdaptation turns on the online control of the mutation rate, the
+// crossover rate or both.
+func WithAdaptation(mutation bool, crossover bool) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.AdaptMutation = mutation
+		solver.AdaptCrossover = crossover
+	}
+}
+
+// WithMutationPolicy picks how WithAdaptation adapts the mutation rate, one
+// of MutationPolicies.
+func WithMutationPolicy(policy string) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.MutationPolicy = policy
+	}
+}
+
+func WithCheckpointEvery(every int) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.CheckpointEvery = every
+	}
+}
+
+func WithMigration(migration Migration) Option {
+	return func(solver *GraphColoringSolver) {
+		solver.Migration = &migration
+	}
+}
@@ -0,0 +1,48 @@
+package ga
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// CheckPrecoloring reports every reason precoloring cannot be extended to a
+// coloring of g with colors colors, 0 meaning any number, and returns the
+// edges between fixed nodes of the same color as warnings, since no
+// extension can resolve them.
+func CheckPrecoloring(g graph.Interface, precoloring Chromosome, colors int) ([]string, error) {
+	if len(precoloring) != g.NodeCount() {
+		return nil, fmt.Errorf("the precoloring has %d nodes, the graph %d", len(precoloring), g.NodeCount())
+	}
+	var problems []error
+	for node, color := range precoloring {
+		switch {
+		case color < Unassigned:
+			problems = append(problems, fmt.Errorf("node %d has color %d, but colors start at 0 and %d leaves a node unassigned", node+1, color, Unassigned))
+		case colors > 0 && color >= colors:
+			problems = append(problems, fmt.Errorf("node %d has color %d, outside of the %d colors", node+1, color, colors))
+		}
+	}
+	if err := errors.Join(problems...); err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for i, j := range graph.AllEdges(g) {
+		if precoloring[i] != Unassigned && precoloring[i] == precoloring[j] {
+			warnings = append(warnings, fmt.Sprintf("fixed nodes %d and %d share color %d", i+1, j+1, precoloring[i]))
+		}
+	}
+	return warnings, nil
+}
+
+// PrecoloredColors returns one more than the largest fixed color of
+// precoloring, the fewest colors a solver extending it needs.
+func PrecoloredColors(precoloring Chromosome) int {
+	colors := 0
+	for _, color := range precoloring {
+		colors = max(colors, color+1)
+	}
+	return colors
+}
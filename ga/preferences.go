@@ -0,0 +1,74 @@
+package ga
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// Preference asks for Node to have Color, at a cost of Weight when it does
+// not. A node may prefer several colors, and then pays for each of them it
+// misses.
+type Preference struct {
+	Node   int `json:"node"`
+	Color  int `json:"color"`
+	Weight int `json:"weight"`
+}
+
+// PreferenceFitness is Fitness, or ConflictFitness if that is nil, weighted
+// by ConflictWeight, plus the weight of every preference the coloring misses.
+// NewPreferenceFitness picks a ConflictWeight above the weight of all the
+// preferences together, so that no preference is worth a conflict. The score
+// is 0 only for proper colorings meeting every preference, so runs with
+// preferences that cannot all be met use their whole budget.
+type PreferenceFitness struct {
+	Fitness        Fitness
+	Preferences    []Preference
+	ConflictWeight int
+}
+
+func NewPreferenceFitness(fitness Fitness, preferences []Preference) PreferenceFitness {
+	weight := 1
+	for _, preference := range preferences {
+		weight += preference.Weight
+	}
+	return PreferenceFitness{Fitness: fitness, Preferences: preferences, ConflictWeight: weight}
+}
+
+func (fitness PreferenceFitness) Evaluate(g graph.Interface, chromosome Chromosome) int {
+	base := fitness.Fitness
+	if base == nil {
+		base = ConflictFitness{}
+	}
+	score := max(fitness.ConflictWeight, 1) * base.Evaluate(g, chromosome)
+	return score + fitness.Missed(chromosome)
+}
+
+// Missed returns the weight of the preferences chromosome misses.
+func (fitness PreferenceFitness) Missed(chromosome Chromosome) int {
+	missed := 0
+	for _, preference := range fitness.Preferences {
+		if chromosome[preference.Node] != preference.Color {
+			missed += preference.Weight
+		}
+	}
+	return missed
+}
+
+// CheckPreferences reports every preference that names a node g lacks, a
+// color outside of colors, 0 meaning any number, or a weight below 1.
+func CheckPreferences(g graph.Interface, preferences []Preference, colors int) error {
+	var problems []error
+	for i, preference := range preferences {
+		switch {
+		case preference.Node < 0 || preference.Node >= g.NodeCount():
+			problems = append(problems, fmt.Errorf("preference %d: node %d is outside of the %d nodes", i+1, preference.Node+1, g.NodeCount()))
+		case preference.Color < 0 || (colors > 0 && preference.Color >= colors):
+			problems = append(problems, fmt.Errorf("preference %d: color %d is outside of the %d colors", i+1, preference.Color, colors))
+		case preference.Weight < 1:
+			problems = append(problems, fmt.Errorf("preference %d: weight must be at least 1, got %d", i+1, preference.Weight))
+		}
+	}
+	return errors.Join(problems...)
+}
@@ -0,0 +1,153 @@
+package ga
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The registry maps names to operator factories so that configurations can
+// pick operators by name. Other packages add their own operators from init,
+// the same way database/sql drivers register themselves.
+var registry = struct {
+	sync.RWMutex
+	selectors  map[string]func() Selector
+	crossovers map[string]func() Crossover
+	mutators   map[string]func() Mutator
+	fitnesses  map[string]func() Fitness
+}{
+	selectors:  map[string]func() Selector{},
+	crossovers: map[string]func() Crossover{},
+	mutators:   map[string]func() Mutator{},
+	fitnesses:  map[string]func() Fitness{},
+}
+
+func init() {
+	RegisterSelector("random", func() Selector { return RandomSelector{Count: 2} })
+	RegisterSelector("roulette", func() Selector { return RouletteSelector{Count: 2} })
+	RegisterSelector("rank", func() Selector { return RankSelector{Count: 2} })
+	RegisterSelector("boltzmann", func() Selector { return BoltzmannSelector{Count: 2} })
+	RegisterCrossover("segment", func() Crossover { return SegmentCrossover{} })
+	RegisterCrossover("gpx", func() Crossover { return GPXCrossover{} })
+	RegisterCrossover("uniform", func() Crossover { return UniformCrossover{} })
+	RegisterCrossover("one-point", func() Crossover { return PointCrossover{Points: 1} })
+	RegisterCrossover("two-point", func() Crossover { return PointCrossover{Points: 2} })
+	RegisterMutator("random", func() Mutator { return RandomMutator{} })
+	RegisterMutator("lowest", func() Mutator { return LowestMutator{} })
+	RegisterMutator("conflict", func() Mutator { return ConflictMutator{} })
+	RegisterFitness("conflicts", func() Fitness { return ConflictFitness{} })
+	RegisterFitness("sum", func() Fitness { return SumFitness{} })
+	RegisterFitness("bandwidth", func() Fitness { return BandwidthFitness{} })
+}
+
+func register[T any](operators map[string]func() T, kind string, name string, factory func() T) {
+	registry.Lock()
+	defer registry.Unlock()
+	if factory == nil {
+		panic("ga: Register" + kind + " factory is nil")
+	}
+	_, exists := operators[name]
+	if exists {
+		panic(fmt.Sprintf("ga: %s %q registered twice", strings.ToLower(kind), name))
+	}
+	operators[name] = factory
+}
+
+func lookup[T any](operators map[string]func() T, kind string, name string) (T, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	factory, exists := operators[name]
+	if !exists {
+		var zero T
+		return zero, fmt.Errorf("unknown %s %q, expected one of %s", kind, name, strings.Join(names(operators), ", "))
+	}
+	return factory(), nil
+}
+
+func names[T any](operators map[string]func() T) []string {
+	var result []string
+	for name := range operators {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func RegisterSelector(name string, factory func() Selector) {
+	register(registry.selectors, "Selector", name, factory)
+}
+
+func RegisterCrossover(name string, factory func() Crossover) {
+	register(registry.crossovers, "Crossover", name, factory)
+}
+
+func RegisterMutator(name string, factory func() Mutator) {
+	register(registry.mutators, "Mutator", name, factory)
+}
+
+func RegisterFitness(name string, factory func() Fitness) {
+	register(registry.fitnesses, "Fitness", name, factory)
+}
+
+// Operators lists the registered names of every operator kind, sorted.
+func Operators() map[string][]string {
+	registry.RLock()
+	defer registry.RUnlock()
+	return map[string][]string{
+		"selector":  names(registry.selectors),
+		"crossover": names(registry.crossovers),
+		"mutator":   names(registry.mutators),
+		"fitness":   names(registry.fitnesses),
+	}
+}
+
+// OperatorNames picks registered operators by name; empty names keep the
+// defaults.
+type OperatorNames struct {
+	Selector  string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	Crossover string `json:"crossover,omitempty" yaml:"crossover,omitempty"`
+	Mutator   string `json:"mutator,omitempty" yaml:"mutator,omitempty"`
+	Fitness   string `json:"fitness,omitempty" yaml:"fitness,omitempty"`
+}
+
+func (operatorNames OperatorNames) Options() ([]Option, error) {
+	var options []Option
+	if operatorNames.Selector != "" {
+		selector, err := lookup(registry.selectors, "selector", operatorNames.Selector)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithSelector(selector), func(solver *GraphColoringSolver) {
+			solver.operatorNames.Selector = operatorNames.Selector
+		})
+	}
+	if operatorNames.Crossover != "" {
+		crossover, err := lookup(registry.crossovers, "crossover", operatorNames.Crossover)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithCrossover(crossover), func(solver *GraphColoringSolver) {
+			solver.operatorNames.Crossover = operatorNames.Crossover
+		})
+	}
+	if operatorNames.Mutator != "" {
+		mutator, err := lookup(registry.mutators, "mutator", operatorNames.Mutator)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithMutator(mutator), func(solver *GraphColoringSolver) {
+			solver.operatorNames.Mutator = operatorNames.Mutator
+		})
+	}
+	if operatorNames.Fitness != "" {
+		fitness, err := lookup(registry.fitnesses, "fitness", operatorNames.Fitness)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithFitness(fitness), func(solver *GraphColoringSolver) {
+			solver.operatorNames.Fitness = operatorNames.Fitness
+		})
+	}
+	return options, nil
+}
@@ -0,0 +1,82 @@
+package ga
+
+// repair greedily removes conflicts from a child fresh from crossover: it
+// visits the nodes that share a color with a neighbour in random order and
+// gives each one that still does the color fewest of its neighbours have,
+// ties broken at random. Precolored nodes keep their colors, and under
+// PenaltyFitness only its Colors are given out, as in tabuSearch. Like
+// tabuSearch it leaves children alone under fitnesses that do more than
+// count conflicts.
+func (solver *GraphColoringSolver) repair(child Chromosome) Chromosome {
+	if !countsConflicts(solver.Fitness) {
+		return child
+	}
+	colors := solver.NumColors
+	if penalty, ok := solver.Fitness.(PenaltyFitness); ok {
+		colors = penalty.Colors
+	}
+	if colors < 1 {
+		return child
+	}
+	fixed := cleared(solver.scratch.fixed, len(child))
+	solver.scratch.fixed = fixed
+	for node, color := range solver.Precoloring {
+		fixed[node] = color != Unassigned
+	}
+
+	conflicting := solver.scratch.conflicting[:0]
+	for node, color := range child {
+		if fixed[node] {
+			continue
+		}
+		for _, neighbour := range solver.Graph.Neighbors(node) {
+			if neighbour != node && child[neighbour] == color {
+				conflicting = append(conflicting, node)
+				break
+			}
+		}
+	}
+	solver.scratch.conflicting = conflicting
+	if len(conflicting) == 0 {
+		return child
+	}
+
+	order := perm(solver, solver.scratch.order, len(conflicting))
+	solver.scratch.order = order
+	counts := cleared(solver.scratch.counts, colors)
+	solver.scratch.counts = counts
+	for _, k := range order {
+		node := conflicting[k]
+		clear(counts)
+		conflicts := 0
+		for _, neighbour := range solver.Graph.Neighbors(node) {
+			if neighbour == node {
+				continue
+			}
+			if child[neighbour] == child[node] {
+				conflicts++
+			}
+			if child[neighbour] >= 0 && child[neighbour] < colors {
+				counts[child[neighbour]]++
+			}
+		}
+		// An earlier recoloring may have resolved the node already.
+		if conflicts == 0 {
+			continue
+		}
+		best, ties := 0, 0
+		for color, count := range counts {
+			switch {
+			case color == 0 || count < counts[best]:
+				best, ties = color, 1
+			case count == counts[best]:
+				ties++
+				if solver.Rand.Intn(ties) == 0 {
+					best = color
+				}
+			}
+		}
+		child[node] = best
+	}
+	return child
+}
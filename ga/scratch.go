@@ -0,0 +1,66 @@
+package ga
+
+// scratch is the memory the built-in operators reuse from child to child, so
+// that once the first generation is bred the next ones allocate next to
+// nothing. Like the rest of the solver it belongs to one run at a time.
+type scratch struct {
+	// spare holds the chromosomes that left the population, for
+	// newChromosome to hand out again.
+	spare Population
+	// selected holds the parents the selectors return, which weights and
+	// cumulative help draw.
+	selected   []int
+	weights    []float64
+	cumulative []float64
+	// changed and bestChanged list the genes ChildFitness compares.
+	changed     []int
+	bestChanged []int
+	// cuts and order are PointCrossover's, order also RankSelector's.
+	cuts  []bool
+	order []int
+	// conflicting and counts are ConflictMutator's, counts also
+	// tabuSearch's, whose other tables follow.
+	conflicting []int
+	counts      []int
+	adjacent    []int
+	tabu        []int
+	fixed       []bool
+	best        Chromosome
+	// taken is LowestMutator's, sizes GPXCrossover's.
+	taken []bool
+	sizes []int
+}
+
+// newChromosome returns a chromosome of length genes to overwrite, reusing a
+// spare one if there is one of that length.
+func (solver *GraphColoringSolver) newChromosome(length int) Chromosome {
+	spare := solver.scratch.spare
+	if n := len(spare); n > 0 && len(spare[n-1]) == length {
+		chr := spare[n-1]
+		solver.scratch.spare = spare[:n-1]
+		return chr
+	}
+	return make(Chromosome, length)
+}
+
+// cleared returns buffer resized to length and zeroed, growing it if needed.
+func cleared[T any](buffer []T, length int) []T {
+	if cap(buffer) < length {
+		return make([]T, length)
+	}
+	buffer = buffer[:length]
+	clear(buffer)
+	return buffer
+}
+
+// perm is solver.Rand.Perm(n) written into buffer, drawing the same numbers,
+// so that it gives the same permutation.
+func perm(solver *GraphColoringSolver, buffer []int, n int) []int {
+	buffer = cleared(buffer, n)
+	for i := 0; i < n; i++ {
+		j := solver.Rand.Intn(i + 1)
+		buffer[i] = buffer[j]
+		buffer[j] = i
+	}
+	return buffer
+}
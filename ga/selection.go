@@ -0,0 +1,120 @@
+package ga
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sort"
+)
+
+// RouletteSelector picks Count parents with probability proportional to
+// 1/(1+score), so a proper coloring is twice as likely as one with a single
+// conflict.
+type RouletteSelector struct {
+	Count int
+}
+
+func (selector RouletteSelector) Select(solver *GraphColoringSolver, population Population, scores []int) []int {
+	weights := cleared(solver.scratch.weights, len(population))
+	solver.scratch.weights = weights
+	for i, score := range scores {
+		weights[i] = 1 / (1 + float64(score))
+	}
+	return selectWeighted(solver, weights, selector.Count)
+}
+
+// RankSelector picks Count parents by linear ranking: the probability of a
+// chromosome grows linearly with its rank, from (2-Pressure)/n for the worst
+// to Pressure/n for the best, whatever the scores are. Pressure lies between
+// 1, which selects uniformly, and 2; 0 means 1.5.
+type RankSelector struct {
+	Count    int
+	Pressure float64
+}
+
+func (selector RankSelector) Select(solver *GraphColoringSolver, population Population, scores []int) []int {
+	popSize := len(population)
+	pressure := selector.Pressure
+	if pressure == 0 {
+		pressure = 1.5
+	}
+	pressure = min(max(pressure, 1), 2)
+
+	// order runs from the best to the worst, ties keeping population order.
+	order := cleared(solver.scratch.order, popSize)
+	solver.scratch.order = order
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(scores[a], scores[b])
+	})
+	weights := cleared(solver.scratch.weights, popSize)
+	solver.scratch.weights = weights
+	for rank, i := range order {
+		weights[i] = pressure
+		if popSize > 1 {
+			weights[i] -= 2 * (pressure - 1) * float64(rank) / float64(popSize-1)
+		}
+	}
+	return selectWeighted(solver, weights, selector.Count)
+}
+
+// BoltzmannSelector picks Count parents with probability proportional to
+// exp(-score/Temperature). Low temperatures favour the best chromosomes
+// strongly, high ones select almost uniformly; 0 means 1.
+type BoltzmannSelector struct {
+	Count       int
+	Temperature float64
+}
+
+func (selector BoltzmannSelector) Select(solver *GraphColoringSolver, population Population, scores []int) []int {
+	temperature := selector.Temperature
+	if temperature <= 0 {
+		temperature = 1
+	}
+	// Scores are taken relative to the best one so that the weights of
+	// large scores do not all underflow to 0.
+	best := scores[0]
+	for _, score := range scores {
+		best = min(best, score)
+	}
+	weights := cleared(solver.scratch.weights, len(population))
+	solver.scratch.weights = weights
+	for i, score := range scores {
+		weights[i] = math.Exp(-float64(score-best) / temperature)
+	}
+	return selectWeighted(solver, weights, selector.Count)
+}
+
+// selectWeighted draws count parents, 2 if count is unset, with probability
+// proportional to weights. Like RandomSelector it tries a few times to draw
+// a parent not drawn already before settling for a repeat.
+func selectWeighted(solver *GraphColoringSolver, weights []float64, count int) []int {
+	if count < 1 {
+		count = 2
+	}
+	cumulative := cleared(solver.scratch.cumulative, len(weights))
+	solver.scratch.cumulative = cumulative
+	total := 0.0
+	for i, weight := range weights {
+		total += weight
+		cumulative[i] = total
+	}
+
+	parents := solver.scratch.selected[:0]
+	for i := 0; i < count; i++ {
+		var parentIndex int
+		for j := 0; j < 10; j++ {
+			x := solver.Rand.Float64() * total
+			parentIndex = sort.Search(len(cumulative), func(k int) bool { return cumulative[k] > x })
+			parentIndex = min(parentIndex, len(weights)-1)
+			if !slices.Contains(parents, parentIndex) {
+				break
+			}
+		}
+		parents = append(parents, parentIndex)
+	}
+	solver.scratch.selected = parents
+	return parents
+}
@@ -0,0 +1,33 @@
+package ga
+
+import (
+	"time"
+)
+
+// RunMetadata describes how a solution was found. Input, the graph file, and
+// Problem, what was solved if not plain coloring, are set by callers that
+// know them, as the command line does.
+type RunMetadata struct {
+	Instance    string
+	Input       string `json:",omitempty"`
+	Problem     string `json:",omitempty"`
+	GraphHash   string
+	Config      SolverConfig
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Elapsed     time.Duration
+	Generations int
+	Evaluations int
+	GitRevision string
+}
+
+// GraphColoringSolution is the best coloring of a run. Edges is set for edge
+// and total colorings, solved as colorings of the line or total graph: it
+// holds the ends of the edge of the input graph each entry of Coloring
+// colors, which for total colorings come after the input nodes.
+type GraphColoringSolution struct {
+	Coloring Chromosome
+	Score    int
+	Metadata RunMetadata
+	Edges    [][2]int `json:",omitempty"`
+}
@@ -0,0 +1,568 @@
+// Package ga implements the genetic algorithm that searches for graph colorings.
+//
+// A solver keeps all state of its run to itself and never modifies its Graph,
+// so any number of solvers may share one loaded graph and Solve concurrently.
+// A single solver runs one Solve or Replay at a time; calling either while
+// another is in progress panics.
+package ga
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/packedbread/gen-alg-graph-coloring/evo"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// tracerName names the OpenTelemetry tracer solvers use by default.
+const tracerName = "github.com/packedbread/gen-alg-graph-coloring/ga"
+
+type Chromosome = []int
+
+// Unassigned marks the nodes a precoloring leaves to the solver.
+const Unassigned = -1
+
+type Population = []Chromosome
+
+// The ways a solver can pick the children that survive a generation.
+const (
+	// TruncationSelect quickselects the survivors and sorts only them, see
+	// evo.SelectTruncation.
+	TruncationSelect = "select"
+	// TruncationSort sorts all children, see evo.SortTruncation.
+	TruncationSort = "sort"
+)
+
+// Truncations lists the valid values of Truncation.
+var Truncations = []string{TruncationSelect, TruncationSort}
+
+type GraphColoringSolver struct {
+	Graph         graph.Interface
+	NumColors     int
+	NumIterations int
+	PopSize       int
+	// MaxTime, if set, stops the run once it has taken that long, counting
+	// the time before a checkpoint it resumed from. The run also stops once
+	// the best score is TargetScore or lower, 0 meaning a proper coloring.
+	MaxTime     time.Duration
+	TargetScore int
+	Rand        *rand.Rand
+	Trace       *Trace
+	Logger      *slog.Logger
+	Tracer      trace.Tracer
+
+	Selector  Selector
+	Crossover Crossover
+	Mutator   Mutator
+	Fitness   Fitness
+
+	// MutationRate is the probability with which RandomMutator recolors each
+	// gene; 0 means 1/len(child). CrossoverRate is the share of children that
+	// are recombined rather than cloned from their first parent; 0 means all.
+	MutationRate   float64
+	CrossoverRate  float64
+	AdaptMutation  bool
+	AdaptCrossover bool
+	// MutationPolicy, one of MutationPolicies, is how AdaptMutation adapts
+	// the mutation rate; "" means MutationSuccess.
+	MutationPolicy  string
+	CheckpointEvery int
+	// Elitism is how many of the best chromosomes of every generation are
+	// carried into the next one unchanged.
+	Elitism int
+	// Truncation, one of Truncations, is how the children that survive a
+	// generation are picked; "" means TruncationSelect. All of them keep the
+	// same children, so it only changes how fast a run goes.
+	Truncation string
+	// SteadyState breeds PopSize children a generation, each of which takes
+	// the place of the worst chromosome of the population right away if it
+	// is no worse, rather than replacing the population at once; it leaves
+	// no room for Elitism or Truncation.
+	SteadyState bool
+	// Crowding, if set, is the crowding factor of deterministic crowding:
+	// every child takes the place of the chromosome closest to it among its
+	// parents and Crowding-1 others drawn at random, if it is no worse, as it
+	// is bred. Like SteadyState, which it replaces, it breeds PopSize
+	// children a generation and leaves no room for Elitism or Truncation.
+	Crowding int
+	// StagnationLimit, if set, is how many generations without a better best
+	// score the solver waits before taking StagnationAction, one of
+	// StagnationActions, on StagnationShare of the population; see unstick.
+	StagnationLimit  int
+	StagnationAction string
+	StagnationShare  float64
+	// MinimizeFromRandom makes MinimizeColors start every color count from a
+	// random population rather than from the best coloring with one more.
+	MinimizeFromRandom bool
+	// TabuIterations, if set, makes the run memetic: every child is improved
+	// by that many moves of tabuSearch after mutation.
+	TabuIterations int
+	// Repair greedily recolors the conflicting nodes of every child bred by
+	// crossover before it is mutated; see repair.
+	Repair bool
+	// FitnessCache skips scoring chromosomes already scored in the current
+	// generation or the one before, and Deduplicate replaces the chromosomes
+	// that repeat another in the population with random ones; see
+	// fitnessCache and deduplicate. Like migration, deduplication is not
+	// recorded in traces.
+	FitnessCache bool
+	Deduplicate  bool
+	Migration    *Migration
+	// Precoloring, if set, has a color for every node whose color is fixed
+	// and Unassigned for the others; only the others are searched.
+	Precoloring   Chromosome
+	operatorNames OperatorNames
+	control       *control
+	cache         *fitnessCache
+	scratch       scratch
+	subscribers   []Subscriber
+	running       sync.Mutex
+
+	seed        int64
+	population  Population
+	generation  int
+	elapsed     time.Duration
+	History     History
+	evaluations int
+}
+
+func (solver *GraphColoringSolver) Seed(seed int64) {
+	solver.seed = seed
+	solver.Rand = rand.New(rand.NewSource(seed))
+}
+
+func (solver *GraphColoringSolver) randomChromosome() Chromosome {
+	nodeCount := solver.Graph.NodeCount()
+	chr := make(Chromosome, nodeCount)
+	for j := 0; j < nodeCount; j++ {
+		chr[j] = solver.Rand.Intn(solver.NumColors)
+	}
+	return solver.fix(chr)
+}
+
+// fix gives the nodes of the precoloring their fixed colors in chromosome.
+func (solver *GraphColoringSolver) fix(chromosome Chromosome) Chromosome {
+	for node, color := range solver.Precoloring {
+		if color != Unassigned {
+			chromosome[node] = color
+		}
+	}
+	return chromosome
+}
+
+func (solver *GraphColoringSolver) RandomPopulation(size int) Population {
+	pop := make(Population, size)
+	for i := 0; i < size; i++ {
+		pop[i] = solver.randomChromosome()
+	}
+	return pop
+}
+
+func (solver *GraphColoringSolver) CalculateFitness(chromosome Chromosome) int {
+	solver.evaluations++
+	return solver.Fitness.Evaluate(solver.Graph, chromosome)
+}
+
+// setDefaults fills in whatever a zero or partially configured solver is
+// missing, so that even GraphColoringSolver{Graph: g} can Solve; without a
+// Graph it solves the empty one. The color budget is left to setColors.
+func (solver *GraphColoringSolver) setDefaults() {
+	if solver.Graph == nil {
+		solver.Graph = graph.New(0)
+	}
+	if solver.Rand == nil {
+		solver.Seed(time.Now().UnixNano())
+	}
+	if solver.Logger == nil {
+		solver.Logger = slog.Default()
+	}
+	if solver.Tracer == nil {
+		solver.Tracer = otel.Tracer(tracerName)
+	}
+	if solver.NumIterations < 1 {
+		solver.NumIterations = DefaultNumIterations
+	}
+	if solver.PopSize < 1 {
+		solver.PopSize = DefaultPopSize
+	}
+	if solver.StagnationLimit > 0 && solver.StagnationAction == "" {
+		solver.StagnationAction = StagnationImmigrants
+	}
+	if solver.Selector == nil {
+		solver.Selector = RandomSelector{Count: 2}
+		solver.operatorNames.Selector = "random"
+	}
+	if solver.Crossover == nil {
+		solver.Crossover = SegmentCrossover{}
+		solver.operatorNames.Crossover = "segment"
+	}
+	if solver.Mutator == nil {
+		solver.Mutator = RandomMutator{}
+		solver.operatorNames.Mutator = "random"
+	}
+	if solver.Fitness == nil {
+		solver.Fitness = ConflictFitness{}
+		solver.operatorNames.Fitness = "conflicts"
+	}
+}
+
+// coloringProblem plugs the solver and its operators into the generic engine.
+// The operators draw from solver.Rand, which is also the engine's source.
+type coloringProblem struct {
+	solver *GraphColoringSolver
+	// edgeCount is the number of edges of the graph, which ChildFitness
+	// weighs the cost of a delta against.
+	edgeCount int
+}
+
+func (problem coloringProblem) RandomGenome(rng *rand.Rand) Chromosome {
+	return problem.solver.randomChromosome()
+}
+
+func (problem coloringProblem) Fitness(chromosome Chromosome) int {
+	solver := problem.solver
+	if solver.cache == nil {
+		return solver.CalculateFitness(chromosome)
+	}
+	hash := hashChromosome(chromosome)
+	score, ok := solver.cache.lookup(hash, chromosome)
+	if !ok {
+		score = solver.CalculateFitness(chromosome)
+		solver.cache.store(hash, chromosome, score)
+	}
+	return score
+}
+
+// Recycle keeps chromosome for newChromosome to reuse.
+func (problem coloringProblem) Recycle(chromosome Chromosome) {
+	problem.solver.scratch.spare = append(problem.solver.scratch.spare, chromosome)
+}
+
+// ChildFitness scores child by a delta from the parent it differs least
+// from, if the fitness is a DeltaFitness and the changed genes have fewer
+// edges than the graph, and from scratch otherwise, unless the fitness cache
+// has its score.
+func (problem coloringProblem) ChildFitness(parents []Chromosome, scores []int, child Chromosome) int {
+	solver := problem.solver
+	if solver.cache == nil {
+		return problem.childFitness(parents, scores, child)
+	}
+	hash := hashChromosome(child)
+	score, ok := solver.cache.lookup(hash, child)
+	if !ok {
+		score = problem.childFitness(parents, scores, child)
+		solver.cache.store(hash, child, score)
+	}
+	return score
+}
+
+func (problem coloringProblem) childFitness(parents []Chromosome, scores []int, child Chromosome) int {
+	solver := problem.solver
+	fitness, ok := solver.Fitness.(DeltaFitness)
+	if !ok {
+		return solver.CalculateFitness(child)
+	}
+
+	best, bestCost := -1, problem.edgeCount
+	for p, parent := range parents {
+		changed := solver.scratch.changed[:0]
+		cost := 0
+		for i, color := range child {
+			if parent[i] != color {
+				changed = append(changed, i)
+				cost += solver.Graph.Degree(i)
+				if cost >= bestCost {
+					break
+				}
+			}
+		}
+		solver.scratch.changed = changed
+		if cost < bestCost {
+			best, bestCost = p, cost
+			// The buffers swap so that the next parent does not overwrite
+			// the genes of the best one.
+			solver.scratch.changed, solver.scratch.bestChanged = solver.scratch.bestChanged, changed
+		}
+	}
+	if best < 0 {
+		return solver.CalculateFitness(child)
+	}
+	solver.evaluations++
+	return scores[best] + fitness.Delta(solver.Graph, parents[best], child, solver.scratch.bestChanged)
+}
+
+func (problem coloringProblem) Select(rng *rand.Rand, population Population, scores []int) []int {
+	return problem.solver.Selector.Select(problem.solver, population, scores)
+}
+
+func (problem coloringProblem) Recombine(rng *rand.Rand, parents []Chromosome) Chromosome {
+	solver := problem.solver
+	if solver.control != nil && !solver.control.cross(solver.Rand) {
+		child := solver.newChromosome(len(parents[0]))
+		copy(child, parents[0])
+		return child
+	}
+	child := solver.Crossover.Recombine(solver, parents)
+	if solver.Repair {
+		child = solver.repair(child)
+	}
+	return child
+}
+
+func (problem coloringProblem) Mutate(rng *rand.Rand, child Chromosome) Chromosome {
+	solver := problem.solver
+	child = solver.fix(solver.Mutator.Mutate(solver, child))
+	if solver.TabuIterations > 0 {
+		child = solver.tabuSearch(child, solver.TabuIterations)
+	}
+	return child
+}
+
+func (solver *GraphColoringSolver) acquire() {
+	if !solver.running.TryLock() {
+		panic("ga: solver is already running, use one solver per concurrent run")
+	}
+}
+
+func (solver *GraphColoringSolver) Solve() GraphColoringSolution {
+	return solver.solve(context.Background(), nil)
+}
+
+// SolveContext is Solve that also stops once ctx is done. The spans of the run
+// are children of the span in ctx.
+func (solver *GraphColoringSolver) SolveContext(ctx context.Context) GraphColoringSolution {
+	return solver.solve(ctx, nil)
+}
+
+// solve runs until the budget is spent, the target score is reached or ctx is
+// done. extra receives the events of this run only, after the regular
+// subscribers.
+func (solver *GraphColoringSolver) solve(ctx context.Context, extra Subscriber) GraphColoringSolution {
+	solver.acquire()
+	defer solver.running.Unlock()
+	if extra != nil {
+		subscribers := solver.subscribers
+		solver.subscribers = append(subscribers[:len(subscribers):len(subscribers)], extra)
+		defer func() {
+			solver.subscribers = subscribers
+		}()
+	}
+	solution := solver.evolve(ctx)
+	solver.emit(Terminated{Solution: solution})
+	return solution
+}
+
+// setColors picks a color budget from the bounds of graph.ColorBounds if the
+// solver has none. It is only called as a run starts, as the bounds take
+// quadratic time on large graphs.
+func (solver *GraphColoringSolver) setColors() {
+	if solver.NumColors < 1 {
+		solver.NumColors = graph.ColorBounds(solver.Graph).Pick()
+	}
+}
+
+// evolve runs the engine on the population the solver holds, or a random one,
+// without announcing the end of the run.
+func (solver *GraphColoringSolver) evolve(ctx context.Context) GraphColoringSolution {
+	solver.setDefaults()
+	solver.setColors()
+	solver.control = solver.newControl()
+	if solver.FitnessCache {
+		solver.cache = newFitnessCache()
+	}
+	defer func() {
+		solver.control = nil
+		solver.cache = nil
+		solver.scratch.spare = nil
+	}()
+	numIterations := solver.NumIterations
+	startedAt := time.Now()
+	start := startedAt.Add(-solver.elapsed)
+	if solver.MaxTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, start.Add(solver.MaxTime))
+		defer cancel()
+	}
+
+	problem := coloringProblem{solver: solver}
+	for i := 0; i < solver.Graph.NodeCount(); i++ {
+		problem.edgeCount += len(solver.Graph.Edges(i))
+	}
+	engine := evo.Engine[Chromosome]{
+		Problem:        problem,
+		Variation:      problem,
+		Rand:           solver.Rand,
+		PopSize:        solver.PopSize,
+		Elitism:        solver.Elitism,
+		Truncation:     evo.SelectTruncation[Chromosome],
+		SteadyState:    solver.SteadyState,
+		Crowding:       solver.Crowding,
+		Distance:       hammingDistance,
+		MaxGenerations: numIterations,
+		Target:         solver.TargetScore,
+		Population:     solver.population,
+		Generation:     solver.generation,
+	}
+	if solver.Truncation == TruncationSort {
+		engine.Truncation = evo.SortTruncation[Chromosome]
+	}
+	if engine.Population == nil {
+		engine.Init()
+	} else {
+		// A restored population was already scored before the checkpoint, so
+		// rescoring it does not count as new evaluations.
+		for _, chr := range engine.Population {
+			engine.Scores = append(engine.Scores, solver.Fitness.Evaluate(solver.Graph, chr))
+		}
+	}
+	popSize := len(engine.Population)
+	ctx, span := solver.Tracer.Start(ctx, "solve", trace.WithAttributes(
+		attribute.Int("graph.nodes", solver.Graph.NodeCount()),
+		attribute.Int("solver.colors", solver.NumColors),
+		attribute.Int("solver.popsize", popSize),
+		attribute.Int("solver.iterations", numIterations),
+		attribute.Int64("solver.seed", solver.seed),
+		attribute.Int("solver.first_generation", solver.generation),
+	))
+	defer span.End()
+	solver.Logger.Debug(
+		"solve started",
+		"colors", solver.NumColors,
+		"popsize", popSize,
+		"iterations", numIterations,
+		"seed", solver.seed,
+		"generation", solver.generation,
+	)
+
+	adapting := solver.AdaptMutation || solver.AdaptCrossover
+	if solver.Trace != nil {
+		solver.Trace.start(solver.Graph.NodeCount(), solver.NumColors, engine.Population, solver.generation, solver.Elitism, solver.SteadyState, solver.Crowding > 0)
+	}
+	if solver.Trace != nil || adapting {
+		engine.OnChild = func(parentIndices []int, child Chromosome, score int) {
+			if adapting {
+				parentScore := engine.Scores[parentIndices[0]]
+				for _, index := range parentIndices[1:] {
+					parentScore = min(parentScore, engine.Scores[index])
+				}
+				solver.control.child(score, parentScore)
+			}
+			if solver.Trace == nil {
+				return
+			}
+			parents := make(Population, len(parentIndices))
+			for i, index := range parentIndices {
+				parents[i] = engine.Population[index]
+			}
+			solver.Trace.derive(parentIndices, parents, child)
+			solver.Trace.compete(engine.Competitors())
+			solver.Trace.child(score)
+		}
+	}
+	bestScore := -1
+	// stagnantBest is the best score the stagnation counts are measured
+	// against, stagnant how many generations since it improved.
+	stagnantBest, stagnant := -1, 0
+	// A generation span starts where the previous one ended, since the engine
+	// only reports finished generations.
+	generationStart := time.Now()
+	engine.OnGeneration = func(generation int, population Population, scores []int) {
+		if solver.Trace != nil {
+			solver.Trace.generation(generation, scores[0])
+		}
+		if solver.Migration != nil && solver.Migration.Every > 0 && (generation+1)%solver.Migration.Every == 0 {
+			_, migrationSpan := solver.Tracer.Start(ctx, "migrate", trace.WithAttributes(attribute.Int("generation", generation+1)))
+			solver.migrate(generation+1, population, scores)
+			migrationSpan.End()
+		}
+		if solver.cache != nil {
+			solver.cache.age()
+		}
+		if solver.Deduplicate {
+			solver.deduplicate(generation+1, population, scores)
+		}
+		if solver.StagnationLimit > 0 {
+			if stagnantBest < 0 || scores[0] < stagnantBest {
+				stagnantBest, stagnant = scores[0], 0
+			} else if stagnant++; stagnant >= solver.StagnationLimit {
+				solver.unstick(generation+1, population, scores)
+				stagnant = 0
+			}
+		}
+		stats := solver.generationStats(generation, population, scores, time.Since(start))
+		_, generationSpan := solver.Tracer.Start(ctx, "generation", trace.WithTimestamp(generationStart), trace.WithAttributes(
+			attribute.Int("generation", generation),
+			attribute.Int("score.best", stats.Best),
+			attribute.Float64("score.mean", stats.Mean),
+			attribute.Float64("diversity", stats.Diversity),
+			attribute.Int("evaluations", stats.Evaluations),
+		))
+		generationSpan.End()
+		generationStart = time.Now()
+		if adapting {
+			solver.control.adapt(scores, stats.Diversity)
+			solver.Logger.Debug("parameters adapted", "generation", generation, "mutation_rate", solver.control.mutationRate, "crossover_rate", solver.control.crossoverRate)
+		}
+		solver.History = append(solver.History, stats)
+		solver.emit(GenerationCompleted{Stats: stats, Best: population[0]})
+		if bestScore < 0 || stats.Best < bestScore {
+			bestScore = stats.Best
+			solver.emit(NewBest{Generation: generation, Score: stats.Best, Coloring: population[0], Elapsed: stats.Elapsed})
+		}
+		if solver.CheckpointEvery > 0 && (generation+1)%solver.CheckpointEvery == 0 {
+			// Subscribers save the checkpoint as it is emitted, so the span
+			// covers writing it too.
+			_, checkpointSpan := solver.Tracer.Start(ctx, "checkpoint", trace.WithAttributes(attribute.Int("generation", generation+1)))
+			solver.emit(Checkpointed{Checkpoint: solver.checkpoint(generation+1, population, time.Since(start))})
+			checkpointSpan.End()
+		}
+	}
+	engine.Run(ctx)
+	if solver.MaxTime > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) && time.Since(start) >= solver.MaxTime {
+		solver.Logger.Info("time budget spent", "max_time", solver.MaxTime, "generation", engine.Generation, "score", engine.Scores[0])
+	}
+	// A run cut short also checkpoints where it stopped, so that resuming
+	// it loses no generations.
+	if solver.CheckpointEvery > 0 && ctx.Err() != nil && engine.Generation < numIterations && engine.Generation%solver.CheckpointEvery != 0 {
+		solver.emit(Checkpointed{Checkpoint: solver.checkpoint(engine.Generation, engine.Population, time.Since(start))})
+	}
+
+	population := engine.Population
+	score := solver.CalculateFitness(population[0])
+	solution := GraphColoringSolution{
+		Coloring: population[0],
+		Score:    score,
+		Metadata: RunMetadata{
+			GraphHash:   graph.Hash(solver.Graph),
+			Config:      solver.Config(),
+			StartedAt:   startedAt,
+			FinishedAt:  time.Now(),
+			Elapsed:     time.Since(start),
+			Generations: engine.Generation,
+			Evaluations: solver.evaluations,
+		},
+	}
+	span.SetAttributes(
+		attribute.Int("score", solution.Score),
+		attribute.Int("generations", solution.Metadata.Generations),
+		attribute.Int("evaluations", solution.Metadata.Evaluations),
+	)
+	solver.Logger.Debug(
+		"solve finished",
+		"score", solution.Score,
+		"generations", solution.Metadata.Generations,
+		"elapsed", solution.Metadata.Elapsed,
+	)
+	if solver.cache != nil {
+		solver.Logger.Debug("fitness cache", "hits", solver.cache.hits, "evaluations", solution.Metadata.Evaluations)
+	}
+	return solution
+}
@@ -0,0 +1,64 @@
+package ga
+
+import (
+	"github.com/packedbread/gen-alg-graph-coloring/evo"
+)
+
+// The actions a solver can take once its best score has not improved for
+// StagnationLimit generations. Every action keeps the Elitism best
+// chromosomes, or the best one without elitism.
+const (
+	// StagnationRestart replaces the rest of the population with random
+	// chromosomes.
+	StagnationRestart = "restart"
+	// StagnationBurst mutates the worst StagnationShare of the population
+	// with ten times the mutation probability.
+	StagnationBurst = "burst"
+	// StagnationImmigrants replaces the worst StagnationShare of the
+	// population with random chromosomes.
+	StagnationImmigrants = "immigrants"
+)
+
+// StagnationActions lists the valid values of StagnationAction.
+var StagnationActions = []string{StagnationImmigrants, StagnationBurst, StagnationRestart}
+
+// unstick takes the StagnationAction on the population, which is sorted by
+// scores, and sorts it again. Like migration it is not recorded in traces.
+func (solver *GraphColoringSolver) unstick(generation int, population Population, scores []int) {
+	elites := min(max(solver.Elitism, 1), len(population))
+	share := solver.StagnationShare
+	if share == 0 {
+		share = 0.5
+	}
+	affected := min(int(share*float64(len(population))+0.5), len(population)-elites)
+	if solver.StagnationAction == StagnationRestart {
+		affected = len(population) - elites
+	}
+
+	scored := make([]evo.Scored[Chromosome], len(population))
+	for i := range population {
+		scored[i] = evo.Scored[Chromosome]{Genome: population[i], Score: scores[i]}
+	}
+	for i := len(population) - affected; i < len(population); i++ {
+		var chr Chromosome
+		if solver.StagnationAction == StagnationBurst {
+			chr = append(Chromosome(nil), population[i]...)
+			mutationProb := 10 * mutationProbability(solver, chr)
+			for gene := range chr {
+				if solver.Rand.Float32() < mutationProb {
+					chr[gene] = solver.Rand.Intn(solver.NumColors)
+				}
+			}
+			chr = solver.fix(chr)
+		} else {
+			chr = solver.randomChromosome()
+		}
+		scored[i] = evo.Scored[Chromosome]{Genome: chr, Score: solver.CalculateFitness(chr)}
+	}
+	evo.Truncate(population, scores, scored)
+
+	solver.Logger.Info("population stagnated", "generation", generation, "action", solver.StagnationAction, "replaced", affected, "best", scores[0])
+	if solver.StagnationAction == StagnationRestart {
+		solver.emit(Restarted{Generation: generation})
+	}
+}
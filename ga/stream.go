@@ -0,0 +1,48 @@
+package ga
+
+import (
+	"context"
+	"time"
+)
+
+type Improvement struct {
+	Generation int
+	Score      int
+	Coloring   Chromosome
+	Elapsed    time.Duration
+}
+
+// SolveStream solves in a new goroutine and sends every improvement of the best
+// score on the first channel as it happens. Once the run ends, because the
+// budget is spent, the coloring is proper or ctx is done, the improvements
+// channel is closed and the final solution is sent on the second channel.
+// Improvements wait for the receiver, so a consumer that stops reading should
+// cancel ctx.
+func (solver *GraphColoringSolver) SolveStream(ctx context.Context) (<-chan Improvement, <-chan GraphColoringSolution) {
+	improvements := make(chan Improvement, 16)
+	solutions := make(chan GraphColoringSolution, 1)
+
+	go func() {
+		defer close(solutions)
+		solution := solver.solve(ctx, SubscriberFunc(func(event Event) {
+			best, ok := event.(NewBest)
+			if !ok {
+				return
+			}
+			improvement := Improvement{
+				Generation: best.Generation,
+				Score:      best.Score,
+				Coloring:   append(Chromosome(nil), best.Coloring...),
+				Elapsed:    best.Elapsed,
+			}
+			select {
+			case improvements <- improvement:
+			case <-ctx.Done():
+			}
+		}))
+		close(improvements)
+		solutions <- solution
+	}()
+
+	return improvements, solutions
+}
@@ -0,0 +1,68 @@
+package ga
+
+import (
+	"slices"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// SumFitness scores colorings for minimum sum coloring: the colors of all
+// nodes added up, plus ConflictWeight for every conflicting edge, or the node
+// count if that is unset, so that a conflict outweighs the colors any one
+// recoloring saves. Only an edgeless graph scores 0, so these runs use their
+// whole budget.
+type SumFitness struct {
+	ConflictWeight int
+}
+
+func (fitness SumFitness) Evaluate(g graph.Interface, chromosome Chromosome) int {
+	weight := fitness.ConflictWeight
+	if weight < 1 {
+		weight = len(chromosome)
+	}
+	score := weight * ConflictFitness{}.Evaluate(g, chromosome)
+	for _, color := range chromosome {
+		score += color
+	}
+	return score
+}
+
+// ChromaticSum returns the sum of the colors of coloring numbered from 1, as
+// minimum sum coloring counts them.
+func ChromaticSum(coloring Chromosome) int {
+	sum := 0
+	for _, color := range coloring {
+		sum += color + 1
+	}
+	return sum
+}
+
+// LowestMutator recolors every gene with the same probability as
+// RandomMutator, but to the lowest color none of its neighbours has, or to a
+// random color if they have them all. It pulls colorings towards low colors
+// while resolving conflicts, as minimum sum coloring wants.
+type LowestMutator struct{}
+
+func (LowestMutator) Mutate(solver *GraphColoringSolver, child Chromosome) Chromosome {
+	mutationProb := mutationProbability(solver, child)
+	taken := cleared(solver.scratch.taken, solver.NumColors)
+	solver.scratch.taken = taken
+	for i := 0; i < len(child); i++ {
+		if solver.Rand.Float32() >= mutationProb {
+			continue
+		}
+		clear(taken)
+		for _, neighbour := range solver.Graph.Neighbors(i) {
+			color := child[neighbour]
+			if neighbour != i && color >= 0 && color < len(taken) {
+				taken[color] = true
+			}
+		}
+		free := slices.Index(taken, false)
+		if free < 0 {
+			free = solver.Rand.Intn(solver.NumColors)
+		}
+		child[i] = free
+	}
+	return child
+}
@@ -0,0 +1,22 @@
+package ga
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// A self-loop must not take the node's own color, nor a neighbour's invalid
+// color any color at all.
+func TestLowestMutatorSkipsLoopsAndInvalidColors(t *testing.T) {
+	g := graph.New(3)
+	g.AddEdge(0, 0)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	solver := NewSolver(g, WithColors(3), WithMutationRate(1))
+	child := LowestMutator{}.Mutate(solver, Chromosome{0, -1, 5})
+	if want := (Chromosome{0, 1, 0}); !slices.Equal(child, want) {
+		t.Errorf("Mutate() = %v, want %v", child, want)
+	}
+}
@@ -0,0 +1,136 @@
+package ga
+
+// tabuSearch improves chromosome with at most iterations moves of TabuCol,
+// the tabu search of Hertz and de Werra: every move recolors the conflicting
+// node and color that remove the most conflicts, as ConflictFitness counts
+// them, and forbids giving the node its old color back for a number of moves
+// that grows with the conflicts left. A forbidden move is still taken if it
+// beats the best coloring so far. Precolored nodes keep their colors. It
+// leaves chromosome at the best coloring it passed through, and stops early
+// at a proper one.
+//
+// Under PenaltyFitness it searches the colorings with its Colors, first moving
+// every node of the spare colors to the color fewest of its neighbours have.
+// Under other fitnesses, which its moves could make worse, it leaves
+// chromosome alone.
+func (solver *GraphColoringSolver) tabuSearch(chromosome Chromosome, iterations int) Chromosome {
+	if !countsConflicts(solver.Fitness) {
+		return chromosome
+	}
+	nodeCount := len(chromosome)
+	colors := solver.NumColors
+	if penalty, ok := solver.Fitness.(PenaltyFitness); ok {
+		colors = penalty.Colors
+	}
+	if colors < 2 || nodeCount == 0 {
+		return chromosome
+	}
+	fixed := cleared(solver.scratch.fixed, nodeCount)
+	solver.scratch.fixed = fixed
+	for node, color := range solver.Precoloring {
+		fixed[node] = color != Unassigned
+	}
+	counts := cleared(solver.scratch.counts, colors)
+	solver.scratch.counts = counts
+	for node, color := range chromosome {
+		if fixed[node] || color < colors {
+			continue
+		}
+		clear(counts)
+		for _, neighbour := range solver.Graph.Neighbors(node) {
+			if neighbour != node && chromosome[neighbour] >= 0 && chromosome[neighbour] < colors {
+				counts[chromosome[neighbour]]++
+			}
+		}
+		chromosome[node] = 0
+		for color, count := range counts {
+			if count < counts[chromosome[node]] {
+				chromosome[node] = color
+			}
+		}
+	}
+
+	// adjacent[node*colors+color] is how many neighbours of node have color,
+	// tabu[node*colors+color] the move until which node may not take it.
+	adjacent := cleared(solver.scratch.adjacent, nodeCount*colors)
+	tabu := cleared(solver.scratch.tabu, nodeCount*colors)
+	solver.scratch.adjacent, solver.scratch.tabu = adjacent, tabu
+	conflicts := 0
+	for node := range chromosome {
+		for _, neighbour := range solver.Graph.Neighbors(node) {
+			// Self-loops conflict whatever the color, so moves ignore them.
+			if neighbour == node {
+				continue
+			}
+			if color := chromosome[neighbour]; color >= 0 && color < colors {
+				adjacent[node*colors+color]++
+				if color == chromosome[node] && node < neighbour {
+					conflicts++
+				}
+			}
+		}
+	}
+	best := append(solver.scratch.best[:0], chromosome...)
+	solver.scratch.best = best
+	bestConflicts := conflicts
+
+	for move := 0; move < iterations && conflicts > 0; move++ {
+		moveNode, moveColor, moveDelta, ties := -1, 0, 0, 0
+		for node, current := range chromosome {
+			if fixed[node] || current < 0 || current >= colors || adjacent[node*colors+current] == 0 {
+				continue
+			}
+			for color := 0; color < colors; color++ {
+				if color == current {
+					continue
+				}
+				delta := adjacent[node*colors+color] - adjacent[node*colors+current]
+				if tabu[node*colors+color] > move && conflicts+delta >= bestConflicts {
+					continue
+				}
+				switch {
+				case moveNode < 0 || delta < moveDelta:
+					moveNode, moveColor, moveDelta, ties = node, color, delta, 1
+				case delta == moveDelta:
+					ties++
+					if solver.Rand.Intn(ties) == 0 {
+						moveNode, moveColor = node, color
+					}
+				}
+			}
+		}
+		if moveNode < 0 {
+			break
+		}
+
+		old := chromosome[moveNode]
+		chromosome[moveNode] = moveColor
+		conflicts += moveDelta
+		for _, neighbour := range solver.Graph.Neighbors(moveNode) {
+			if neighbour == moveNode {
+				continue
+			}
+			adjacent[neighbour*colors+old]--
+			adjacent[neighbour*colors+moveColor]++
+		}
+		tabu[moveNode*colors+old] = move + 1 + solver.Rand.Intn(10) + 6*conflicts/10
+		if conflicts < bestConflicts {
+			bestConflicts = conflicts
+			copy(best, chromosome)
+		}
+	}
+	copy(chromosome, best)
+	return chromosome
+}
+
+// countsConflicts reports whether fitness is what tabuSearch and repair
+// minimize: ConflictFitness, or PenaltyFitness over it.
+func countsConflicts(fitness Fitness) bool {
+	switch fitness := fitness.(type) {
+	case ConflictFitness:
+		return true
+	case PenaltyFitness:
+		return fitness.Fitness == nil || countsConflicts(fitness.Fitness)
+	}
+	return false
+}
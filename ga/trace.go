@@ -0,0 +1,383 @@
+package ga
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/evo"
+)
+
+const replayLogEvery = 100
+
+// Trace records how every child of a run was bred as gzip-compressed lines:
+//
+//	t <nodes> <colors> <popSize> <first generation> [<elitism> [steady|crowding]]
+//	i <initial chromosome genes...>
+//	c <parents> <segments start:parent> <mutations gene=color> <score> [<competitors>]
+//	g <generation> <best score>
+//
+// Empty lists are written as "-", the elitism only if the run has one or is
+// steady-state or crowding, which are named only if the run is, and the
+// competitors other than the parents only if it is crowding. The fitness is
+// not recorded, so Replay rescores children with the fitness of its solver.
+type Trace struct {
+	file   *os.File
+	gzip   *gzip.Writer
+	writer *bufio.Writer
+
+	crowding    bool
+	parents     []string
+	segments    []string
+	mutations   []string
+	competitors []string
+}
+
+func CreateTrace(filename string) (*Trace, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := gzip.NewWriter(file)
+	return &Trace{
+		file:   file,
+		gzip:   compressed,
+		writer: bufio.NewWriter(compressed),
+	}, nil
+}
+
+func (trace *Trace) Close() error {
+	err := trace.writer.Flush()
+	if err != nil {
+		return err
+	}
+	err = trace.gzip.Close()
+	if err != nil {
+		return err
+	}
+	return trace.file.Close()
+}
+
+func (trace *Trace) start(nodeCount int, numColors int, population Population, generation int, elitism int, steady bool, crowding bool) {
+	trace.crowding = crowding
+	if crowding {
+		fmt.Fprintf(trace.writer, "t %d %d %d %d %d crowding\n", nodeCount, numColors, len(population), generation, elitism)
+	} else if steady {
+		fmt.Fprintf(trace.writer, "t %d %d %d %d %d steady\n", nodeCount, numColors, len(population), generation, elitism)
+	} else if elitism > 0 {
+		fmt.Fprintf(trace.writer, "t %d %d %d %d %d\n", nodeCount, numColors, len(population), generation, elitism)
+	} else {
+		fmt.Fprintf(trace.writer, "t %d %d %d %d\n", nodeCount, numColors, len(population), generation)
+	}
+	for _, chr := range population {
+		genes := make([]string, len(chr))
+		for i, color := range chr {
+			genes[i] = strconv.Itoa(color)
+		}
+		fmt.Fprintf(trace.writer, "i %s\n", strings.Join(genes, " "))
+	}
+}
+
+// derive describes child in terms of its parents: runs of genes copied from
+// one parent become segments and genes no parent has become mutations, so any
+// combination of operators can be replayed.
+func (trace *Trace) derive(parentIndices []int, parents []Chromosome, child Chromosome) {
+	for _, index := range parentIndices {
+		trace.parents = append(trace.parents, strconv.Itoa(index))
+	}
+
+	current := -1
+	for i, color := range child {
+		if current >= 0 && parents[current][i] == color {
+			continue
+		}
+		next := -1
+		for p, parent := range parents {
+			if parent[i] == color {
+				next = p
+				break
+			}
+		}
+		if next < 0 {
+			if current < 0 {
+				current = 0
+				trace.segments = append(trace.segments, fmt.Sprintf("%d:%d", i, current))
+			}
+			trace.mutations = append(trace.mutations, fmt.Sprintf("%d=%d", i, color))
+			continue
+		}
+		current = next
+		trace.segments = append(trace.segments, fmt.Sprintf("%d:%d", i, current))
+	}
+}
+
+// compete records the competitors of the child other than its parents.
+func (trace *Trace) compete(competitors []int) {
+	for _, index := range competitors {
+		trace.competitors = append(trace.competitors, strconv.Itoa(index))
+	}
+}
+
+func traceList(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, ",")
+}
+
+func (trace *Trace) child(score int) {
+	fmt.Fprintf(
+		trace.writer,
+		"c %s %s %s %d",
+		traceList(trace.parents),
+		traceList(trace.segments),
+		traceList(trace.mutations),
+		score,
+	)
+	if trace.crowding {
+		fmt.Fprintf(trace.writer, " %s", traceList(trace.competitors))
+	}
+	fmt.Fprintln(trace.writer)
+	trace.parents = trace.parents[:0]
+	trace.segments = trace.segments[:0]
+	trace.mutations = trace.mutations[:0]
+	trace.competitors = trace.competitors[:0]
+}
+
+func (trace *Trace) generation(generation int, best int) {
+	fmt.Fprintf(trace.writer, "g %d %d\n", generation, best)
+}
+
+type traceChild struct {
+	parents     []int
+	segments    [][2]int
+	mutations   [][2]int
+	score       int
+	competitors []int
+}
+
+func parseTraceInts(field string, separator string) ([][]int, error) {
+	if field == "-" {
+		return nil, nil
+	}
+
+	var values [][]int
+	for _, item := range strings.Split(field, ",") {
+		var parts []int
+		for _, token := range strings.Split(item, separator) {
+			value, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, value)
+		}
+		values = append(values, parts)
+	}
+	return values, nil
+}
+
+func parseTraceChild(tokens []string) (traceChild, error) {
+	child := traceChild{}
+	if len(tokens) != 5 && len(tokens) != 6 {
+		return child, fmt.Errorf("malformed child record %q", strings.Join(tokens, " "))
+	}
+
+	parents, err := parseTraceInts(tokens[1], ",")
+	if err != nil {
+		return child, err
+	}
+	for _, parent := range parents {
+		child.parents = append(child.parents, parent[0])
+	}
+
+	segments, err := parseTraceInts(tokens[2], ":")
+	if err != nil {
+		return child, err
+	}
+	for _, segment := range segments {
+		if len(segment) != 2 {
+			return child, fmt.Errorf("malformed segment in %q", tokens[2])
+		}
+		child.segments = append(child.segments, [2]int{segment[0], segment[1]})
+	}
+
+	mutations, err := parseTraceInts(tokens[3], "=")
+	if err != nil {
+		return child, err
+	}
+	for _, mutation := range mutations {
+		if len(mutation) != 2 {
+			return child, fmt.Errorf("malformed mutation in %q", tokens[3])
+		}
+		child.mutations = append(child.mutations, [2]int{mutation[0], mutation[1]})
+	}
+
+	child.score, err = strconv.Atoi(tokens[4])
+	if err != nil || len(tokens) == 5 {
+		return child, err
+	}
+	competitors, err := parseTraceInts(tokens[5], ",")
+	if err != nil {
+		return child, err
+	}
+	for _, competitor := range competitors {
+		child.competitors = append(child.competitors, competitor[0])
+	}
+	return child, nil
+}
+
+// apply builds the chromosome child describes, failing on segments and
+// mutations a corrupt trace may hold: segments must start at increasing
+// genes, and every gene must be one of the nodeCount.
+func (child *traceChild) apply(population Population, nodeCount int) (Chromosome, error) {
+	res := make(Chromosome, nodeCount)
+	for i, segment := range child.segments {
+		end := nodeCount
+		if i+1 < len(child.segments) {
+			end = child.segments[i+1][0]
+		}
+		if segment[0] < 0 || segment[0] >= end || end > nodeCount {
+			return nil, fmt.Errorf("segment start %d is out of order or outside of the %d genes", segment[0], nodeCount)
+		}
+		if segment[1] < 0 || segment[1] >= len(child.parents) || child.parents[segment[1]] < 0 || child.parents[segment[1]] >= len(population) {
+			return nil, errors.New("segment refers to an unknown parent")
+		}
+		parent := population[child.parents[segment[1]]]
+		copy(res[segment[0]:end], parent[segment[0]:end])
+	}
+	for _, mutation := range child.mutations {
+		if mutation[0] < 0 || mutation[0] >= nodeCount {
+			return nil, fmt.Errorf("mutation of gene %d is outside of the %d genes", mutation[0], nodeCount)
+		}
+		res[mutation[0]] = mutation[1]
+	}
+	return res, nil
+}
+
+// Replay re-executes a recorded trace against the graph without drawing any
+// random numbers, and fails at the first child or generation whose score
+// differs from the recording.
+func (solver *GraphColoringSolver) Replay(r io.Reader, out io.Writer, verbose bool) (int, error) {
+	compressed, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	scanner := bufio.NewScanner(compressed)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024*1024)
+
+	solver.acquire()
+	defer solver.running.Unlock()
+	solver.setDefaults()
+	nodeCount := solver.Graph.NodeCount()
+	var population Population
+	var scores []int
+	var scoredPopulation []evo.Scored[Chromosome]
+	popSize, elitism, steady, crowding := 0, 0, false, false
+	childIndex := 0
+	generations := 0
+
+	for scanner.Scan() {
+		tokens := strings.Fields(scanner.Text())
+		if len(tokens) == 0 {
+			continue
+		}
+
+		switch tokens[0] {
+		case "t":
+			if len(tokens) < 5 || len(tokens) > 7 || (len(tokens) == 7 && tokens[6] != "steady" && tokens[6] != "crowding") {
+				return generations, errors.New("malformed trace header")
+			}
+			traceNodes, _ := strconv.Atoi(tokens[1])
+			if traceNodes != nodeCount {
+				return generations, fmt.Errorf("trace is for a graph with %d nodes, got %d", traceNodes, nodeCount)
+			}
+			popSize, _ = strconv.Atoi(tokens[3])
+			if len(tokens) >= 6 {
+				elitism, _ = strconv.Atoi(tokens[5])
+			}
+			steady = len(tokens) == 7 && tokens[6] == "steady"
+			crowding = len(tokens) == 7 && tokens[6] == "crowding"
+		case "i":
+			if len(tokens)-1 != nodeCount {
+				return generations, fmt.Errorf("initial chromosome has %d genes, expected %d", len(tokens)-1, nodeCount)
+			}
+			chr := make(Chromosome, nodeCount)
+			for i, token := range tokens[1:] {
+				chr[i], err = strconv.Atoi(token)
+				if err != nil {
+					return generations, err
+				}
+			}
+			population = append(population, chr)
+			// Only elitism, steady-state and crowding runs need the scores
+			// of the population; the initial ones were not recorded.
+			scores = append(scores, solver.Fitness.Evaluate(solver.Graph, chr))
+		case "c":
+			child, err := parseTraceChild(tokens)
+			if err != nil {
+				return generations, err
+			}
+			chr, err := child.apply(population, nodeCount)
+			if err != nil {
+				return generations, err
+			}
+			score := solver.CalculateFitness(chr)
+			if score != child.score {
+				return generations, fmt.Errorf("child %d of generation %d scores %d, recorded %d", childIndex, generations, score, child.score)
+			}
+			if verbose {
+				fmt.Fprintf(out, "child %d: parents %v, segments %v, mutations %v, score %d\n", childIndex, child.parents, child.segments, child.mutations, score)
+			}
+			if crowding {
+				candidates := append(slices.Clone(child.parents), child.competitors...)
+				if len(candidates) == 0 || slices.ContainsFunc(candidates, func(index int) bool { return index < 0 || index >= popSize }) {
+					return generations, errors.New("child competes with an unknown chromosome")
+				}
+				evo.ReplaceNearest(population[:popSize], scores[:popSize], candidates, evo.Scored[Chromosome]{Genome: chr, Score: score}, hammingDistance)
+			} else if steady {
+				evo.ReplaceWorst(population[:popSize], scores[:popSize], evo.Scored[Chromosome]{Genome: chr, Score: score})
+			} else {
+				scoredPopulation = append(scoredPopulation, evo.Scored[Chromosome]{Genome: chr, Score: score})
+			}
+			childIndex++
+		case "g":
+			if len(tokens) != 3 {
+				return generations, errors.New("malformed generation record")
+			}
+			generation, _ := strconv.Atoi(tokens[1])
+			recordedBest, _ := strconv.Atoi(tokens[2])
+			if steady || crowding {
+				// A steady-state or crowding population is only sorted at the end of
+				// a generation.
+				for i, chr := range population[:popSize] {
+					scoredPopulation = append(scoredPopulation, evo.Scored[Chromosome]{Genome: chr, Score: scores[i]})
+				}
+				evo.Truncate(population[:popSize], scores[:popSize], scoredPopulation)
+			} else if len(scoredPopulation) < popSize {
+				return generations, fmt.Errorf("generation %d has %d children, expected at least %d", generation, len(scoredPopulation), popSize)
+			} else {
+				evo.Replace(population[:popSize], scores[:popSize], scoredPopulation, elitism, nil, nil)
+			}
+			if scoredPopulation[0].Score != recordedBest {
+				return generations, fmt.Errorf("generation %d best score %d, recorded %d", generation, scoredPopulation[0].Score, recordedBest)
+			}
+			if verbose || generation%replayLogEvery == 0 {
+				fmt.Fprintf(out, "generation %d: best score %d\n", generation, recordedBest)
+			}
+			scoredPopulation = scoredPopulation[:0]
+			childIndex = 0
+			generations++
+		default:
+			return generations, fmt.Errorf("unknown trace record %q", tokens[0])
+		}
+	}
+
+	return generations, scanner.Err()
+}
@@ -0,0 +1,38 @@
+package ga
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// A corrupt trace must fail the replay with an error rather than a panic.
+func TestReplayRejectsCorruptChildren(t *testing.T) {
+	for _, record := range []string{
+		"c 0,1 0:0,2:1 3=5 0",
+		"c 0,1 0:0,3:1 - 0",
+		"c 0,1 0:0,2:1,1:0 - 0",
+		"c 0,1 0:0,0:1 - 0",
+		"c 0,1 -1:0 - 0",
+		"c 0,1 0:0 -1=0 0",
+		"c 0,1 0:2 - 0",
+		"c 0,1 0:-1 - 0",
+		"c 0,-1 0:1 - 0",
+		"c 0,2 0:1 - 0",
+	} {
+		compressed := bytes.Buffer{}
+		writer := gzip.NewWriter(&compressed)
+		writer.Write([]byte("t 3 2 2 0\ni 0 1 0\ni 1 0 1\n" + record + "\n"))
+		writer.Close()
+
+		g := graph.New(3)
+		g.AddEdge(0, 1)
+		_, err := NewSolver(g, WithColors(2)).Replay(&compressed, io.Discard, false)
+		if err == nil {
+			t.Errorf("replaying %q succeeded", record)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package graph
+
+// GreedyBandwidth colors g for bandwidth coloring the Welsh-Powell way: nodes
+// in order of decreasing degree, each with the smallest color that differs
+// from the color of every colored neighbour by at least the weight of the
+// edge between them.
+func GreedyBandwidth(g Interface) []int {
+	order := make([]int, g.NodeCount())
+	for i := range order {
+		order[i] = i
+	}
+	sortByDegree(g, order)
+
+	// separations lists, for every node, its neighbours with the weights of
+	// the edges to them, whichever end the edges are stored at.
+	type separation struct{ node, weight int }
+	separations := make([][]separation, g.NodeCount())
+	for i := 0; i < g.NodeCount(); i++ {
+		for k, j := range g.Edges(i) {
+			weight := EdgeWeight(g, i, k)
+			separations[i] = append(separations[i], separation{j, weight})
+			separations[j] = append(separations[j], separation{i, weight})
+		}
+	}
+
+	colors := make([]int, g.NodeCount())
+	for i := range colors {
+		colors[i] = -1
+	}
+	for _, node := range order {
+		color := 0
+		for fits := false; !fits; {
+			fits = true
+			for _, s := range separations[node] {
+				if colors[s.node] >= 0 && abs(color-colors[s.node]) < s.weight {
+					fits = false
+					color = colors[s.node] + s.weight
+					break
+				}
+			}
+		}
+		colors[node] = color
+	}
+	return colors
+}
+
+// MaxWeight returns the largest edge weight of g, 0 if it has no edges.
+func MaxWeight(g Interface) int {
+	weight := 0
+	for i := 0; i < g.NodeCount(); i++ {
+		for k := range g.Edges(i) {
+			weight = max(weight, EdgeWeight(g, i, k))
+		}
+	}
+	return weight
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// DenseDensity is the share of all possible edges from which Compact stores
+// a graph as a Bitset: the bits then take little memory next to the edges,
+// and counting conflicts goes a word of nodes at a time.
+const DenseDensity = 0.1
+
+var _ ConflictCounter = (*Bitset)(nil)
+
+// Bitset is a CSR that also keeps the adjacency matrix of the graph as bits,
+// which answers HasEdge at once and counts conflicts with the AND and
+// popcount of every row and the color class of its node. It can only hold
+// graphs that list no edge twice, as the matrix would count it once.
+type Bitset struct {
+	*CSR
+	// words is the length of a row, upper[i*words:(i+1)*words] the row of
+	// node i with a bit for every neighbour j > i; loops counts the
+	// self-loops, which conflict whatever the coloring.
+	words int
+	upper []uint64
+	loops int
+	// classes pools the class bitmaps of CountConflicts, which solvers call
+	// from several goroutines at once.
+	classes sync.Pool
+}
+
+// NewBitset copies g into a Bitset, or returns false if g lists an edge
+// twice.
+func NewBitset(g Interface) (*Bitset, bool) {
+	nodeCount := g.NodeCount()
+	words := (nodeCount + 63) / 64
+	bitset := &Bitset{words: words, upper: make([]uint64, nodeCount*words)}
+	for i, j := range AllEdges(g) {
+		if i == j {
+			bitset.loops++
+			continue
+		}
+		u, v := min(i, j), max(i, j)
+		word, bit := &bitset.upper[u*words+v/64], uint64(1)<<(v%64)
+		if *word&bit != 0 {
+			return nil, false
+		}
+		*word |= bit
+	}
+	bitset.CSR = NewCSR(g)
+	return bitset, true
+}
+
+// Compact returns the representation solvers evaluate g fastest on: a Bitset
+// if it has DenseDensity of the possible edges or more and lists none twice,
+// a CSR otherwise.
+func Compact(g Interface) Interface {
+	nodeCount, edgeCount := g.NodeCount(), 0
+	for i := 0; i < nodeCount; i++ {
+		edgeCount += len(g.Edges(i))
+	}
+	if nodeCount > 1 && float64(edgeCount) >= DenseDensity*float64(nodeCount)*float64(nodeCount-1)/2 {
+		if bitset, ok := NewBitset(g); ok {
+			return bitset
+		}
+	}
+	return NewCSR(g)
+}
+
+func (bitset *Bitset) HasEdge(u int, v int) bool {
+	if u == v {
+		return bitset.CSR.HasEdge(u, v)
+	}
+	u, v = min(u, v), max(u, v)
+	return bitset.upper[u*bitset.words+v/64]&(uint64(1)<<(v%64)) != 0
+}
+
+// CountConflicts builds a bitmap of every color class and counts, for every
+// node, the neighbours above it in its own class. Colorings with negative
+// colors, or more colors than nodes, are counted as a CSR counts them.
+func (bitset *Bitset) CountConflicts(coloring []int) int {
+	colors := 0
+	for _, color := range coloring {
+		if color < 0 {
+			return bitset.CSR.CountConflicts(coloring)
+		}
+		colors = max(colors, color+1)
+	}
+	if colors > len(coloring) {
+		return bitset.CSR.CountConflicts(coloring)
+	}
+
+	words := bitset.words
+	buffer, _ := bitset.classes.Get().(*[]uint64)
+	if buffer == nil {
+		buffer = new([]uint64)
+	}
+	defer bitset.classes.Put(buffer)
+	if cap(*buffer) < colors*words {
+		*buffer = make([]uint64, colors*words)
+	}
+	classes := (*buffer)[:colors*words]
+	clear(classes)
+	for i, color := range coloring {
+		classes[color*words+i/64] |= uint64(1) << (i % 64)
+	}
+	conflicts := bitset.loops
+	for i, color := range coloring {
+		row := bitset.upper[i*words : (i+1)*words]
+		class := classes[color*words : (color+1)*words]
+		// Row i has no bits below i.
+		for w := i / 64; w < words; w++ {
+			conflicts += bits.OnesCount64(row[w] & class[w])
+		}
+	}
+	return conflicts
+}
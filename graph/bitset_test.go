@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBitsetMatchesGraph(t *testing.T) {
+	for name, g := range testGraphs() {
+		t.Run(name, func(t *testing.T) {
+			bitset, ok := NewBitset(g)
+			if name == "twice" {
+				if ok {
+					t.Fatal("NewBitset accepted a graph that lists an edge twice")
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("NewBitset rejected a graph that lists no edge twice")
+			}
+			for i := 0; i < g.NodeCount(); i++ {
+				for j := 0; j < g.NodeCount(); j++ {
+					if bitset.HasEdge(i, j) != g.HasEdge(i, j) {
+						t.Errorf("HasEdge(%d, %d) = %v, want %v", i, j, bitset.HasEdge(i, j), g.HasEdge(i, j))
+					}
+				}
+			}
+
+			rng := rand.New(rand.NewSource(2))
+			colorings := testColorings(rng, g, 4)
+			// Colorings the bitmaps cannot hold go through the CSR.
+			if n := g.NodeCount(); n > 0 {
+				negative := make([]int, n)
+				negative[0] = -1
+				many := make([]int, n)
+				for i := range many {
+					many[i] = 2 * n
+				}
+				colorings = append(colorings, negative, many)
+			}
+			for _, coloring := range colorings {
+				if got, want := bitset.CountConflicts(coloring), plainConflicts(g, coloring); got != want {
+					t.Fatalf("CountConflicts(%v) = %d, want %d", coloring, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompactPicksByDensity(t *testing.T) {
+	graphs := testGraphs()
+	for _, test := range []struct {
+		name   string
+		bitset bool
+	}{
+		{"sparse", false},
+		{"dense", true},
+		{"wide", true},
+		// Dense, but with an edge listed twice.
+		{"twice", false},
+	} {
+		_, isBitset := Compact(graphs[test.name]).(*Bitset)
+		if isBitset != test.bitset {
+			t.Errorf("Compact(%s) is a Bitset: %v, want %v", test.name, isBitset, test.bitset)
+		}
+	}
+}
+
+func BenchmarkConflictsDenseGraph(b *testing.B) {
+	benchmarkConflicts(b, 0.5, func(g *Graph) Interface { return g })
+}
+
+func BenchmarkConflictsDenseCSR(b *testing.B) {
+	benchmarkConflicts(b, 0.5, func(g *Graph) Interface { return NewCSR(g) })
+}
+
+func BenchmarkConflictsDenseBitset(b *testing.B) {
+	benchmarkConflicts(b, 0.5, func(g *Graph) Interface {
+		bitset, _ := NewBitset(g)
+		return bitset
+	})
+}
@@ -0,0 +1,150 @@
+package graph
+
+// Bounds bracket the chromatic number of a graph: Lower is the size of a
+// clique found in it and Upper the colors of the best of a DSATUR coloring
+// and the degeneracy bound.
+type Bounds struct {
+	Lower      int
+	Upper      int
+	DSatur     int
+	Degeneracy int
+}
+
+// ColorBounds computes Bounds of g with fast heuristics.
+func ColorBounds(g Interface) Bounds {
+	bounds := Bounds{Lower: len(GreedyClique(g)), Degeneracy: Degeneracy(g)}
+	for _, color := range DSatur(g) {
+		bounds.DSatur = max(bounds.DSatur, color+1)
+	}
+	bounds.Upper = min(bounds.DSatur, bounds.Degeneracy+1)
+	bounds.Lower = min(bounds.Lower, bounds.Upper)
+	return bounds
+}
+
+// Pick returns the color count a solver should aim for: one fewer than the
+// heuristics used, since they already find a coloring with Upper, but never
+// below Lower.
+func (bounds Bounds) Pick() int {
+	return max(bounds.Lower, bounds.Upper-1, 1)
+}
+
+// DSatur colors g the Brélaz way: always the node whose neighbours have the
+// most distinct colors, ties going to the larger degree, with the smallest
+// color none of its neighbours has.
+func DSatur(g Interface) []int {
+	nodeCount := g.NodeCount()
+	colors := make([]int, nodeCount)
+	// taken marks the colors among the neighbours of every node; a node of
+	// degree d never needs more than d+1 colors.
+	taken := make([][]bool, nodeCount)
+	saturation := make([]int, nodeCount)
+	for i := range colors {
+		colors[i] = -1
+		taken[i] = make([]bool, g.Degree(i)+1)
+	}
+
+	for range nodeCount {
+		node := -1
+		for i := range nodeCount {
+			if colors[i] >= 0 {
+				continue
+			}
+			if node < 0 || saturation[i] > saturation[node] || (saturation[i] == saturation[node] && g.Degree(i) > g.Degree(node)) {
+				node = i
+			}
+		}
+		color := 0
+		for taken[node][color] {
+			color++
+		}
+		colors[node] = color
+		for _, neighbour := range g.Neighbors(node) {
+			if color < len(taken[neighbour]) && !taken[neighbour][color] {
+				taken[neighbour][color] = true
+				saturation[neighbour]++
+			}
+		}
+	}
+	return colors
+}
+
+// Degeneracy returns the largest d such that g has a subgraph whose nodes all
+// have degree d or more. Coloring the nodes smallest last needs at most d+1
+// colors.
+func Degeneracy(g Interface) int {
+	nodeCount := g.NodeCount()
+	degrees := Degrees(g)
+	// buckets holds the nodes left by their degree among the nodes left.
+	buckets := make([][]int, MaxDegree(g)+1)
+	for i, degree := range degrees {
+		buckets[degree] = append(buckets[degree], i)
+	}
+	removed := make([]bool, nodeCount)
+	degeneracy := 0
+	for degree, left := 0, nodeCount; left > 0; {
+		if len(buckets[degree]) == 0 {
+			degree++
+			continue
+		}
+		node := buckets[degree][len(buckets[degree])-1]
+		buckets[degree] = buckets[degree][:len(buckets[degree])-1]
+		if removed[node] || degrees[node] != degree {
+			continue
+		}
+		removed[node] = true
+		left--
+		degeneracy = max(degeneracy, degree)
+		for _, neighbour := range g.Neighbors(node) {
+			if !removed[neighbour] {
+				degrees[neighbour]--
+				buckets[degrees[neighbour]] = append(buckets[degrees[neighbour]], neighbour)
+			}
+		}
+		degree = max(degree-1, 0)
+	}
+	return degeneracy
+}
+
+// cliqueStarts is how many of the nodes of largest degree GreedyClique grows
+// a clique from.
+const cliqueStarts = 64
+
+// GreedyClique returns a clique of g, the largest of those grown from its
+// nodes of largest degree by adding the candidate of largest degree that is
+// adjacent to the whole clique so far.
+func GreedyClique(g Interface) []int {
+	order := make([]int, g.NodeCount())
+	for i := range order {
+		order[i] = i
+	}
+	sortByDegree(g, order)
+
+	var best []int
+	for _, start := range order[:min(len(order), cliqueStarts)] {
+		if g.Degree(start)+1 <= len(best) {
+			break
+		}
+		clique := []int{start}
+		candidates := append([]int(nil), g.Neighbors(start)...)
+		for len(candidates) > 0 && len(clique)+len(candidates) > len(best) {
+			next := candidates[0]
+			for _, candidate := range candidates[1:] {
+				if g.Degree(candidate) > g.Degree(next) {
+					next = candidate
+				}
+			}
+			clique = append(clique, next)
+			kept := candidates[:0]
+			for _, candidate := range candidates {
+				if candidate != next && g.HasEdge(candidate, next) {
+					kept = append(kept, candidate)
+				}
+			}
+			candidates = kept
+		}
+		if len(clique) > len(best) {
+			best = clique
+		}
+	}
+	return best
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"sort"
+)
+
+type ColoringComparison struct {
+	RawDifferences      []int
+	Mapping             map[int]int
+	RelabeledDifference []int
+	ConflictsOnlyA      [][2]int
+	ConflictsOnlyB      [][2]int
+	ConflictsBoth       [][2]int
+}
+
+// CompareColorings matches the color classes of a onto those of b by largest
+// overlap first, since two colorings that only differ by a renaming of colors
+// are the same solution.
+func CompareColorings(g *Graph, a []int, b []int) ColoringComparison {
+	comparison := ColoringComparison{Mapping: make(map[int]int)}
+
+	type pair struct{ a, b int }
+	overlap := make(map[pair]int)
+	for i := range a {
+		if a[i] != b[i] {
+			comparison.RawDifferences = append(comparison.RawDifferences, i)
+		}
+		overlap[pair{a[i], b[i]}]++
+	}
+
+	pairs := make([]pair, 0, len(overlap))
+	for p := range overlap {
+		pairs = append(pairs, p)
+	}
+	sort.Slice(pairs, func(i int, j int) bool {
+		if overlap[pairs[i]] != overlap[pairs[j]] {
+			return overlap[pairs[i]] > overlap[pairs[j]]
+		}
+		if pairs[i].a != pairs[j].a {
+			return pairs[i].a < pairs[j].a
+		}
+		return pairs[i].b < pairs[j].b
+	})
+	usedB := make(map[int]struct{})
+	for _, p := range pairs {
+		if _, mapped := comparison.Mapping[p.a]; mapped {
+			continue
+		}
+		if _, used := usedB[p.b]; used {
+			continue
+		}
+		comparison.Mapping[p.a] = p.b
+		usedB[p.b] = struct{}{}
+	}
+
+	for i := range a {
+		mapped, ok := comparison.Mapping[a[i]]
+		if !ok || mapped != b[i] {
+			comparison.RelabeledDifference = append(comparison.RelabeledDifference, i)
+		}
+	}
+
+	for i, neighbours := range g.AdjecencyList {
+		for _, j := range neighbours {
+			conflictA := a[i] == a[j]
+			conflictB := b[i] == b[j]
+			edge := [2]int{i, j}
+			switch {
+			case conflictA && conflictB:
+				comparison.ConflictsBoth = append(comparison.ConflictsBoth, edge)
+			case conflictA:
+				comparison.ConflictsOnlyA = append(comparison.ConflictsOnlyA, edge)
+			case conflictB:
+				comparison.ConflictsOnlyB = append(comparison.ConflictsOnlyB, edge)
+			}
+		}
+	}
+
+	return comparison
+}
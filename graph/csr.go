@@ -0,0 +1,113 @@
+package graph
+
+// CSR is a read-only graph in compressed sparse row form: the Neighbors of
+// all nodes lie in one slice and their Edges in another, each cut up by
+// offsets, rather than in a slice per node as in Graph. Walking every edge
+// then reads memory in order, which is what counting conflicts does for
+// every chromosome.
+type CSR struct {
+	// neighbours[neighbourOffsets[i]:neighbourOffsets[i+1]] are the
+	// neighbours of node i, and likewise for edges and their weights.
+	neighbourOffsets []int
+	neighbours       []int
+	edgeOffsets      []int
+	edges            []int
+	weights          []int
+}
+
+var _ Interface = (*CSR)(nil)
+var _ Weighted = (*CSR)(nil)
+var _ ConflictCounter = (*CSR)(nil)
+
+// NewCSR copies g into a CSR, keeping the order of its Neighbors, Edges and
+// edge weights, so that both hash alike.
+func NewCSR(g Interface) *CSR {
+	nodeCount := g.NodeCount()
+	csr := &CSR{
+		neighbourOffsets: make([]int, nodeCount+1),
+		edgeOffsets:      make([]int, nodeCount+1),
+	}
+	for i := 0; i < nodeCount; i++ {
+		csr.neighbourOffsets[i+1] = csr.neighbourOffsets[i] + len(g.Neighbors(i))
+		csr.edgeOffsets[i+1] = csr.edgeOffsets[i] + len(g.Edges(i))
+	}
+	csr.neighbours = make([]int, 0, csr.neighbourOffsets[nodeCount])
+	csr.edges = make([]int, 0, csr.edgeOffsets[nodeCount])
+	for i := 0; i < nodeCount; i++ {
+		csr.neighbours = append(csr.neighbours, g.Neighbors(i)...)
+		csr.edges = append(csr.edges, g.Edges(i)...)
+	}
+
+	weighted, ok := g.(Weighted)
+	if !ok {
+		return csr
+	}
+	for i := 0; i < nodeCount; i++ {
+		weights := weighted.EdgeWeights(i)
+		if weights == nil {
+			continue
+		}
+		if csr.weights == nil {
+			csr.weights = make([]int, len(csr.edges))
+			for k := range csr.weights {
+				csr.weights[k] = 1
+			}
+		}
+		copy(csr.weights[csr.edgeOffsets[i]:csr.edgeOffsets[i+1]], weights)
+	}
+	return csr
+}
+
+func (csr *CSR) NodeCount() int {
+	return len(csr.edgeOffsets) - 1
+}
+
+func (csr *CSR) Neighbors(node int) []int {
+	start, end := csr.neighbourOffsets[node], csr.neighbourOffsets[node+1]
+	return csr.neighbours[start:end:end]
+}
+
+func (csr *CSR) Edges(node int) []int {
+	start, end := csr.edgeOffsets[node], csr.edgeOffsets[node+1]
+	return csr.edges[start:end:end]
+}
+
+func (csr *CSR) EdgeWeights(node int) []int {
+	if csr.weights == nil {
+		return nil
+	}
+	start, end := csr.edgeOffsets[node], csr.edgeOffsets[node+1]
+	return csr.weights[start:end:end]
+}
+
+func (csr *CSR) HasEdge(u int, v int) bool {
+	if csr.Degree(u) > csr.Degree(v) {
+		u, v = v, u
+	}
+	for _, j := range csr.Neighbors(u) {
+		if j == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (csr *CSR) Degree(node int) int {
+	return csr.neighbourOffsets[node+1] - csr.neighbourOffsets[node]
+}
+
+// CountConflicts is CountConflicts for a CSR, walking its edges in one pass.
+func (csr *CSR) CountConflicts(coloring []int) int {
+	conflicts := 0
+	start := 0
+	for i, end := range csr.edgeOffsets[1:] {
+		color := coloring[i]
+		for _, j := range csr.edges[start:end] {
+			if coloring[j] == color {
+				conflicts++
+			}
+		}
+		start = end
+	}
+	return conflicts
+}
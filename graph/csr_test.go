@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// testGraphs are the graphs the compact representations are checked against,
+// covering the corners of Graph: self-loops, edges listed twice or from both
+// ends, weights and isolated nodes.
+func testGraphs() map[string]*Graph {
+	rng := rand.New(rand.NewSource(1))
+	sparse := NewRandomGraph(rng, 60, 0.05)
+	dense := NewRandomGraph(rng, 70, 0.6)
+	wide := NewRandomGraph(rng, 150, 0.3)
+
+	loops := New(5)
+	loops.AddEdge(0, 0)
+	loops.AddEdge(0, 1)
+	loops.AddEdge(3, 3)
+
+	twice := New(4)
+	twice.AddEdge(0, 1)
+	twice.AddEdge(1, 0)
+	twice.AddEdge(2, 3)
+	twice.AddEdge(2, 3)
+
+	weighted := New(4)
+	weighted.AddEdge(0, 1)
+	weighted.AddWeightedEdge(1, 2, 3)
+	weighted.AddWeightedEdge(3, 0, 2)
+
+	return map[string]*Graph{
+		"empty":    New(0),
+		"isolated": New(3),
+		"loops":    loops,
+		"twice":    twice,
+		"weighted": weighted,
+		"sparse":   &sparse,
+		"dense":    &dense,
+		"wide":     &wide,
+	}
+}
+
+// plainConflicts counts conflicts edge by edge, as Graph stores them.
+func plainConflicts(g *Graph, coloring []int) int {
+	conflicts := 0
+	for i, edges := range g.AdjecencyList {
+		for _, j := range edges {
+			if coloring[i] == coloring[j] {
+				conflicts++
+			}
+		}
+	}
+	return conflicts
+}
+
+// testColorings returns random colorings of g with up to colors colors.
+func testColorings(rng *rand.Rand, g *Graph, colors int) [][]int {
+	var colorings [][]int
+	for range 20 {
+		coloring := make([]int, g.NodeCount())
+		for i := range coloring {
+			coloring[i] = rng.Intn(colors)
+		}
+		colorings = append(colorings, coloring)
+	}
+	return colorings
+}
+
+func TestCSRMatchesGraph(t *testing.T) {
+	for name, g := range testGraphs() {
+		t.Run(name, func(t *testing.T) {
+			csr := NewCSR(g)
+			if csr.NodeCount() != g.NodeCount() {
+				t.Fatalf("NodeCount() = %d, want %d", csr.NodeCount(), g.NodeCount())
+			}
+			for i := 0; i < g.NodeCount(); i++ {
+				if !slices.Equal(csr.Neighbors(i), g.Neighbors(i)) {
+					t.Errorf("Neighbors(%d) = %v, want %v", i, csr.Neighbors(i), g.Neighbors(i))
+				}
+				if !slices.Equal(csr.Edges(i), g.Edges(i)) {
+					t.Errorf("Edges(%d) = %v, want %v", i, csr.Edges(i), g.Edges(i))
+				}
+				if !slices.Equal(csr.EdgeWeights(i), g.EdgeWeights(i)) {
+					t.Errorf("EdgeWeights(%d) = %v, want %v", i, csr.EdgeWeights(i), g.EdgeWeights(i))
+				}
+				if csr.Degree(i) != g.Degree(i) {
+					t.Errorf("Degree(%d) = %d, want %d", i, csr.Degree(i), g.Degree(i))
+				}
+				for j := 0; j < g.NodeCount(); j++ {
+					if csr.HasEdge(i, j) != g.HasEdge(i, j) {
+						t.Errorf("HasEdge(%d, %d) = %v, want %v", i, j, csr.HasEdge(i, j), g.HasEdge(i, j))
+					}
+				}
+			}
+			if Hash(csr) != Hash(g) {
+				t.Errorf("Hash() = %s, want %s", Hash(csr), Hash(g))
+			}
+
+			rng := rand.New(rand.NewSource(2))
+			for _, coloring := range testColorings(rng, g, 4) {
+				if got, want := csr.CountConflicts(coloring), plainConflicts(g, coloring); got != want {
+					t.Fatalf("CountConflicts(%v) = %d, want %d", coloring, got, want)
+				}
+			}
+		})
+	}
+}
+
+// The slices a CSR hands out must not let appending to one overwrite the
+// next node's.
+func TestCSRSlicesAreCapped(t *testing.T) {
+	g := New(2)
+	g.AddEdge(0, 1)
+	csr := NewCSR(g)
+	_ = append(csr.Neighbors(0), 7)
+	_ = append(csr.Edges(0), 7)
+	if !slices.Equal(csr.Neighbors(1), []int{0}) {
+		t.Errorf("Neighbors(1) = %v after appending to Neighbors(0)", csr.Neighbors(1))
+	}
+}
+
+// benchmarkConflicts counts the conflicts of random colorings of a random
+// graph of the given density on g as representation makes it.
+func benchmarkConflicts(b *testing.B, density float32, representation func(g *Graph) Interface) {
+	rng := rand.New(rand.NewSource(1))
+	lists := NewRandomGraph(rng, 1000, density)
+	g := representation(&lists)
+	colorings := testColorings(rng, &lists, 20)
+	for i := 0; b.Loop(); i++ {
+		CountConflicts(g, colorings[i%len(colorings)])
+	}
+}
+
+func BenchmarkConflictsSparseGraph(b *testing.B) {
+	benchmarkConflicts(b, 0.01, func(g *Graph) Interface { return g })
+}
+
+func BenchmarkConflictsSparseCSR(b *testing.B) {
+	benchmarkConflicts(b, 0.01, func(g *Graph) Interface { return NewCSR(g) })
+}
@@ -0,0 +1,142 @@
+// Package graph holds the undirected graph and coloring helpers shared by the
+// solver, the file formats and the visualizations.
+//
+// Apart from AddEdge and AddWeightedEdge no Graph method modifies the graph,
+// so goroutines may share a graph that is no longer being built or recolored.
+package graph
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Graph is built with New and AddEdge. AdjecencyList holds every edge once, in
+// the list of the node it was added from, and is what Edges returns; the
+// neighbours of every node, as Neighbors returns them, are kept alongside.
+// Weights, once an edge of a weight other than 1 is added, holds the weight
+// of every edge at its place in AdjecencyList.
+type Graph struct {
+	AdjecencyList [][]int
+	Colors        []int
+	Weights       [][]int `json:",omitempty"`
+
+	neighbours [][]int
+}
+
+// New returns a graph with nodeCount nodes, no edges and every node colored 0.
+func New(nodeCount int) *Graph {
+	return &Graph{
+		AdjecencyList: make([][]int, nodeCount),
+		Colors:        make([]int, nodeCount),
+		neighbours:    make([][]int, nodeCount),
+	}
+}
+
+// AddEdge connects nodes u and v, numbered from 0, storing the edge in the
+// adjacency list of u. Adding an edge twice stores it twice.
+func (g *Graph) AddEdge(u int, v int) {
+	g.AddWeightedEdge(u, v, 1)
+}
+
+// AddWeightedEdge is AddEdge for an edge of the given weight.
+func (g *Graph) AddWeightedEdge(u int, v int, weight int) {
+	if g.Weights == nil && weight != 1 {
+		g.Weights = make([][]int, len(g.AdjecencyList))
+		for i, edges := range g.AdjecencyList {
+			g.Weights[i] = make([]int, len(edges))
+			for k := range edges {
+				g.Weights[i][k] = 1
+			}
+		}
+	}
+	if g.Weights != nil {
+		g.Weights[u] = append(g.Weights[u], weight)
+	}
+	g.AdjecencyList[u] = append(g.AdjecencyList[u], v)
+	g.neighbours[u] = append(g.neighbours[u], v)
+	if u != v {
+		g.neighbours[v] = append(g.neighbours[v], u)
+	}
+}
+
+func NewRandomGraph(rng *rand.Rand, nodeCount int, prob float32) Graph {
+	g := New(nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		for j := i + 1; j < nodeCount; j++ {
+			if rng.Float32() < prob {
+				g.AddEdge(i, j)
+			}
+		}
+	}
+
+	return *g
+}
+
+func (g *Graph) Hash() string {
+	return Hash(g)
+}
+
+func (g *Graph) NodeCount() int {
+	return len(g.AdjecencyList)
+}
+
+func (g *Graph) Conflicts() int {
+	conflicts := 0
+	for i, neighbours := range g.AdjecencyList {
+		for _, j := range neighbours {
+			if g.Colors[i] == g.Colors[j] {
+				conflicts++
+			}
+		}
+	}
+	return conflicts
+}
+
+func (g *Graph) UsedColors() []int {
+	used := make(map[int]struct{})
+	for _, color := range g.Colors {
+		used[color] = struct{}{}
+	}
+
+	colors := make([]int, 0, len(used))
+	for color := range used {
+		colors = append(colors, color)
+	}
+	sort.Ints(colors)
+	return colors
+}
+
+func (g *Graph) PaletteSize() int {
+	size := 0
+	for _, color := range g.Colors {
+		if color+1 > size {
+			size = color + 1
+		}
+	}
+	return size
+}
+
+func (g *Graph) ColorsUsed() int {
+	return len(g.UsedColors())
+}
+
+func (g *Graph) Degrees() []int {
+	return Degrees(g)
+}
+
+func (g *Graph) MaxDegree() int {
+	return MaxDegree(g)
+}
+
+func (g *Graph) NodeConflicts() []int {
+	conflicts := make([]int, g.NodeCount())
+	for i, neighbours := range g.AdjecencyList {
+		for _, j := range neighbours {
+			if g.Colors[i] == g.Colors[j] {
+				conflicts[i]++
+				conflicts[j]++
+			}
+		}
+	}
+	return conflicts
+}
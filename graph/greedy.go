@@ -0,0 +1,46 @@
+package graph
+
+import "sort"
+
+// Greedy colors g the Welsh-Powell way: nodes in order of decreasing degree,
+// each with the smallest color none of its neighbours has. The coloring is
+// proper and uses at most one color more than the maximum degree.
+func Greedy(g Interface) []int {
+	order := make([]int, g.NodeCount())
+	for i := range order {
+		order[i] = i
+	}
+	sortByDegree(g, order)
+
+	colors := make([]int, g.NodeCount())
+	for i := range colors {
+		colors[i] = -1
+	}
+	taken := make([]bool, g.NodeCount()+1)
+	for _, node := range order {
+		for _, neighbour := range g.Neighbors(node) {
+			if colors[neighbour] >= 0 {
+				taken[colors[neighbour]] = true
+			}
+		}
+		color := 0
+		for taken[color] {
+			color++
+		}
+		colors[node] = color
+		for _, neighbour := range g.Neighbors(node) {
+			if colors[neighbour] >= 0 {
+				taken[colors[neighbour]] = false
+			}
+		}
+	}
+	return colors
+}
+
+// sortByDegree sorts nodes by decreasing degree in g, keeping the order of
+// nodes of equal degree.
+func sortByDegree(g Interface, nodes []int) {
+	sort.SliceStable(nodes, func(a, b int) bool {
+		return g.Degree(nodes[a]) > g.Degree(nodes[b])
+	})
+}
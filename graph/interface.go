@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"iter"
+)
+
+// Interface is the read-only view of a graph the solver needs, so that other
+// representations (CSR, bitsets, memory-mapped files) can be solved without
+// copying them into a Graph. Nodes are numbered from 0 and the graph is
+// undirected. Neighbors lists every node adjacent to node; Edges lists the
+// other ends of the edges stored at node, so that going through the Edges of
+// every node visits every edge exactly once, as counting conflicts needs.
+// Neither slice may be modified.
+type Interface interface {
+	NodeCount() int
+	Neighbors(node int) []int
+	Edges(node int) []int
+	HasEdge(u int, v int) bool
+	Degree(node int) int
+}
+
+var _ Interface = (*Graph)(nil)
+
+// Weighted graphs give their edges weights, which bandwidth coloring needs
+// the colors of their ends to differ by. EdgeWeights lists the weights of the
+// Edges of node in the same order, or is nil if they all weigh 1.
+type Weighted interface {
+	EdgeWeights(node int) []int
+}
+
+var _ Weighted = (*Graph)(nil)
+
+// ConflictCounter is implemented by representations that count the conflicts
+// of a coloring, as CountConflicts does, faster than by walking their Edges.
+type ConflictCounter interface {
+	CountConflicts(coloring []int) int
+}
+
+func (g *Graph) Neighbors(node int) []int {
+	return g.neighbours[node]
+}
+
+func (g *Graph) Edges(node int) []int {
+	return g.AdjecencyList[node]
+}
+
+func (g *Graph) EdgeWeights(node int) []int {
+	if g.Weights == nil {
+		return nil
+	}
+	return g.Weights[node]
+}
+
+func (g *Graph) HasEdge(u int, v int) bool {
+	if len(g.neighbours[u]) > len(g.neighbours[v]) {
+		u, v = v, u
+	}
+	for _, j := range g.neighbours[u] {
+		if j == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Graph) Degree(node int) int {
+	return len(g.neighbours[node])
+}
+
+// AllEdges iterates over every edge of g once.
+func AllEdges(g Interface) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for i := 0; i < g.NodeCount(); i++ {
+			for _, j := range g.Edges(i) {
+				if !yield(i, j) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Hash identifies the edges of g, listed in Edges order, and their weights if
+// any differ from 1; checkpoints use it to recognise the graph they were
+// taken on.
+func Hash(g Interface) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\n", g.NodeCount())
+	for i, j := range AllEdges(g) {
+		fmt.Fprintf(hash, "%d %d\n", i, j)
+	}
+	if weighted, ok := g.(Weighted); ok {
+		for i := 0; i < g.NodeCount(); i++ {
+			for _, weight := range weighted.EdgeWeights(i) {
+				fmt.Fprintf(hash, "%d\n", weight)
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EdgeWeight returns the weight of the k-th of the Edges of node, 1 unless g
+// is Weighted.
+func EdgeWeight(g Interface, node int, k int) int {
+	if weighted, ok := g.(Weighted); ok {
+		if weights := weighted.EdgeWeights(node); weights != nil {
+			return weights[k]
+		}
+	}
+	return 1
+}
+
+func Degrees(g Interface) []int {
+	degrees := make([]int, g.NodeCount())
+	for i := range degrees {
+		degrees[i] = g.Degree(i)
+	}
+	return degrees
+}
+
+func MaxDegree(g Interface) int {
+	maxDegree := 0
+	for _, degree := range Degrees(g) {
+		if degree > maxDegree {
+			maxDegree = degree
+		}
+	}
+	return maxDegree
+}
+
+// CountConflicts returns the number of edges of g whose ends coloring gives
+// the same color.
+func CountConflicts(g Interface, coloring []int) int {
+	if counter, ok := g.(ConflictCounter); ok {
+		return counter.CountConflicts(coloring)
+	}
+	conflicts := 0
+	for i, j := range AllEdges(g) {
+		if coloring[i] == coloring[j] {
+			conflicts++
+		}
+	}
+	return conflicts
+}
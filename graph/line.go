@@ -0,0 +1,34 @@
+package graph
+
+// LineGraph returns the line graph of g, whose nodes are the edges of g in
+// AllEdges order and are adjacent where those edges share an end, and the
+// ends of the edge every node stands for. Coloring the line graph colors the
+// edges of g so that edges meeting at a node differ.
+func LineGraph(g Interface) (*Graph, [][2]int) {
+	var edges [][2]int
+	incident := make([][]int, g.NodeCount())
+	for i, j := range AllEdges(g) {
+		incident[i] = append(incident[i], len(edges))
+		if i != j {
+			incident[j] = append(incident[j], len(edges))
+		}
+		edges = append(edges, [2]int{i, j})
+	}
+
+	line := New(len(edges))
+	for node, meeting := range incident {
+		for a, e := range meeting {
+			for _, f := range meeting[a+1:] {
+				// Edges listed twice in g meet at both their ends but are
+				// connected once, at the smaller end.
+				u, v := edges[f][0], edges[f][1]
+				parallel := edges[e] == [2]int{u, v} || edges[e] == [2]int{v, u}
+				if parallel && node != min(u, v) {
+					continue
+				}
+				line.AddEdge(e, f)
+			}
+		}
+	}
+	return line, edges
+}
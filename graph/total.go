@@ -0,0 +1,29 @@
+package graph
+
+// TotalGraph returns the total graph of g, whose nodes are the nodes of g
+// followed by its edges in AllEdges order, with the nodes adjacent as in g,
+// the edges adjacent where they share an end, as in LineGraph, and every edge
+// adjacent to its ends, and the ends of the edge every node after the first
+// g.NodeCount() stands for. Coloring the total graph colors the nodes and
+// edges of g so that adjacent nodes, edges meeting at a node and edges and
+// their ends differ.
+func TotalGraph(g Interface) (*Graph, [][2]int) {
+	line, edges := LineGraph(g)
+	nodeCount := g.NodeCount()
+	total := New(nodeCount + len(edges))
+	for i, j := range AllEdges(g) {
+		total.AddEdge(i, j)
+	}
+	for e, others := range line.AdjecencyList {
+		for _, f := range others {
+			total.AddEdge(nodeCount+e, nodeCount+f)
+		}
+	}
+	for e, ends := range edges {
+		total.AddEdge(ends[0], nodeCount+e)
+		if ends[0] != ends[1] {
+			total.AddEdge(ends[1], nodeCount+e)
+		}
+	}
+	return total, edges
+}
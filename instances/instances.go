@@ -0,0 +1,39 @@
+// Package instances embeds small graphs with known chromatic numbers, to check
+// that a build of the solver still colors them optimally.
+package instances
+
+import (
+	"embed"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+//go:embed *.col
+var files embed.FS
+
+// Instance is an embedded graph that cannot be colored with fewer than
+// Chromatic colors, but can with that many.
+type Instance struct {
+	Name      string
+	Chromatic int
+}
+
+// Known lists the embedded instances from the easiest to the hardest.
+var Known = []Instance{
+	{"petersen", 3},
+	{"myciel4", 5},
+	{"queen5_5", 5},
+	{"planted3_90", 3},
+	{"queen6_6", 7},
+}
+
+// Graph parses the instance.
+func (instance Instance) Graph() (*graph.Graph, error) {
+	file, err := files.Open(instance.Name + ".col")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return encoding.ReadDIMACS(file)
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/server/pb"
+)
+
+// RegisterGRPC serves the jobs of server as the ColoringService of
+// coloring.proto on registrar.
+func (server *Server) RegisterGRPC(registrar grpc.ServiceRegistrar) {
+	pb.RegisterColoringServiceServer(registrar, grpcService{server: server})
+}
+
+type grpcService struct {
+	pb.UnimplementedColoringServiceServer
+	server *Server
+}
+
+// authenticate returns ctx for the tenant whose API key is in the
+// authorization ("Bearer <key>") or x-api-key metadata of the call.
+func (service grpcService) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := ""
+	for _, value := range md.Get("authorization") {
+		key, _ = strings.CutPrefix(value, "Bearer ")
+	}
+	if key == "" && len(md.Get("x-api-key")) > 0 {
+		key = md.Get("x-api-key")[0]
+	}
+	ctx, err := service.server.authenticate(ctx, key)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return ctx, nil
+}
+
+func (service grpcService) SubmitJob(ctx context.Context, request *pb.SubmitJobRequest) (*pb.JobStatus, error) {
+	ctx, err := service.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobRequest := Request{
+		Name:     request.GetName(),
+		GraphURI: request.GetGraphUri(),
+		Config:   configFromProto(request.GetConfig()),
+		Output:   request.GetOutput(),
+		Callback: request.GetCallback(),
+	}
+	if jobRequest.GraphURI != "" {
+		if request.GetGraph() != nil {
+			return nil, status.Error(codes.InvalidArgument, "give either graph or graph_uri, not both")
+		}
+		jobStatus, err := service.server.Submit(ctx, jobRequest)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return statusToProto(jobStatus), nil
+	}
+
+	g, err := graphFromProto(request.GetGraph())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	jobStatus, err := service.server.SubmitGraph(ctx, g, jobRequest)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return statusToProto(jobStatus), nil
+}
+
+func (service grpcService) StreamProgress(request *pb.JobRequest, stream grpc.ServerStreamingServer[pb.JobStatus]) error {
+	ctx, err := service.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	err = service.server.Watch(ctx, request.GetId(), func(jobStatus Status) error {
+		return stream.Send(statusToProto(jobStatus))
+	})
+	return grpcError(err)
+}
+
+func (service grpcService) GetSolution(ctx context.Context, request *pb.JobRequest) (*pb.Solution, error) {
+	ctx, err := service.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	solution, jobStatus, err := service.server.Solution(ctx, request.GetId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if solution == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "job is %s", jobStatus.State)
+	}
+	coloring := make([]int64, len(solution.Coloring))
+	for i, color := range solution.Coloring {
+		coloring[i] = int64(color)
+	}
+	return &pb.Solution{
+		Coloring:    coloring,
+		Score:       int64(solution.Score),
+		Config:      configToProto(solution.Metadata.Config),
+		Generations: int64(solution.Metadata.Generations),
+		Evaluations: int64(solution.Metadata.Evaluations),
+		Elapsed:     durationpb.New(solution.Metadata.Elapsed),
+	}, nil
+}
+
+func (service grpcService) Cancel(ctx context.Context, request *pb.JobRequest) (*pb.JobStatus, error) {
+	ctx, err := service.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobStatus, err := service.server.Cancel(ctx, request.GetId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return statusToProto(jobStatus), nil
+}
+
+func grpcError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return err
+}
+
+func graphFromProto(message *pb.Graph) (*graph.Graph, error) {
+	nodeCount := message.GetNodeCount()
+	if nodeCount < 0 || nodeCount > math.MaxInt {
+		return nil, fmt.Errorf("graph: node count %d is outside of 0..%d", nodeCount, math.MaxInt)
+	}
+	edges := message.GetEdges()
+	for _, edge := range edges {
+		u, v := edge.GetU(), edge.GetV()
+		if u < 0 || u >= nodeCount || v < 0 || v >= nodeCount {
+			return nil, fmt.Errorf("graph: edge %d %d is outside of the %d nodes", u, v, nodeCount)
+		}
+	}
+
+	// Like DIMACS files, messages may list an edge in both directions, which
+	// would count its conflicts twice. Duplicates are found by sorting, as
+	// ReadDIMACS does, and only the first of them is added.
+	key := func(edge *pb.Edge) [2]int64 {
+		u, v := edge.GetU(), edge.GetV()
+		return [2]int64{min(u, v), max(u, v)}
+	}
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		first, second := key(edges[a]), key(edges[b])
+		return cmp.Or(cmp.Compare(first[0], second[0]), cmp.Compare(first[1], second[1]))
+	})
+	duplicate := make([]bool, len(edges))
+	for i := 1; i < len(order); i++ {
+		duplicate[order[i]] = key(edges[order[i]]) == key(edges[order[i-1]])
+	}
+
+	g := graph.New(int(nodeCount))
+	for i, edge := range edges {
+		if !duplicate[i] {
+			g.AddEdge(int(edge.GetU()), int(edge.GetV()))
+		}
+	}
+	return g, nil
+}
+
+func configFromProto(message *pb.Config) ga.SolverConfig {
+	return ga.SolverConfig{
+		Colors:           int(message.GetColors()),
+		Iterations:       int(message.GetIterations()),
+		PopSize:          int(message.GetPopSize()),
+		Seed:             message.GetSeed(),
+		CheckpointEvery:  int(message.GetCheckpointEvery()),
+		MutationRate:     message.GetMutationRate(),
+		CrossoverRate:    message.GetCrossoverRate(),
+		AdaptMutation:    message.GetAdaptMutation(),
+		AdaptCrossover:   message.GetAdaptCrossover(),
+		MutationPolicy:   message.GetMutationPolicy(),
+		Elitism:          int(message.GetElitism()),
+		TabuIterations:   int(message.GetTabuIterations()),
+		StagnationLimit:  int(message.GetStagnationLimit()),
+		StagnationAction: message.GetStagnationAction(),
+		StagnationShare:  message.GetStagnationShare(),
+		MaxTime:          message.GetMaxTime(),
+		TargetScore:      int(message.GetTargetScore()),
+		Truncation:       message.GetTruncation(),
+		FitnessCache:     message.GetFitnessCache(),
+		Deduplicate:      message.GetDeduplicate(),
+		SteadyState:      message.GetSteadyState(),
+		Crowding:         int(message.GetCrowding()),
+		Repair:           message.GetRepair(),
+		OperatorNames: ga.OperatorNames{
+			Selector:  message.GetSelector(),
+			Crossover: message.GetCrossover(),
+			Mutator:   message.GetMutator(),
+			Fitness:   message.GetFitness(),
+		},
+	}
+}
+
+func configToProto(config ga.SolverConfig) *pb.Config {
+	return &pb.Config{
+		Colors:           int64(config.Colors),
+		Iterations:       int32(config.Iterations),
+		PopSize:          int32(config.PopSize),
+		Seed:             config.Seed,
+		CheckpointEvery:  int32(config.CheckpointEvery),
+		MutationRate:     config.MutationRate,
+		CrossoverRate:    config.CrossoverRate,
+		AdaptMutation:    config.AdaptMutation,
+		AdaptCrossover:   config.AdaptCrossover,
+		MutationPolicy:   config.MutationPolicy,
+		Elitism:          int32(config.Elitism),
+		TabuIterations:   int32(config.TabuIterations),
+		StagnationLimit:  int32(config.StagnationLimit),
+		StagnationAction: config.StagnationAction,
+		StagnationShare:  config.StagnationShare,
+		MaxTime:          config.MaxTime,
+		TargetScore:      int64(config.TargetScore),
+		Truncation:       config.Truncation,
+		FitnessCache:     config.FitnessCache,
+		Deduplicate:      config.Deduplicate,
+		SteadyState:      config.SteadyState,
+		Crowding:         int32(config.Crowding),
+		Repair:           config.Repair,
+		Selector:         config.Selector,
+		Crossover:        config.Crossover,
+		Mutator:          config.Mutator,
+		Fitness:          config.Fitness,
+	}
+}
+
+var protoStates = map[State]pb.State{
+	Queued:    pb.State_STATE_QUEUED,
+	Running:   pb.State_STATE_RUNNING,
+	Done:      pb.State_STATE_DONE,
+	Cancelled: pb.State_STATE_CANCELLED,
+	Failed:    pb.State_STATE_FAILED,
+}
+
+func statusToProto(jobStatus Status) *pb.JobStatus {
+	protoStatus := &pb.JobStatus{
+		Id:          jobStatus.ID,
+		Name:        jobStatus.Name,
+		State:       protoStates[jobStatus.State],
+		Config:      configToProto(jobStatus.Config),
+		Nodes:       int64(jobStatus.Nodes),
+		Generation:  int64(jobStatus.Generation),
+		BestScore:   int64(jobStatus.BestScore),
+		Elapsed:     durationpb.New(jobStatus.Elapsed),
+		SubmittedAt: timestamppb.New(jobStatus.SubmittedAt),
+		Attempts:    int32(jobStatus.Attempts),
+		Error:       jobStatus.Error,
+	}
+	if !jobStatus.FinishedAt.IsZero() {
+		protoStatus.FinishedAt = timestamppb.New(jobStatus.FinishedAt)
+	}
+	return protoStatus
+}
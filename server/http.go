@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const maxRequestBytes = 64 << 20
+
+// Handler serves the job API:
+//
+//	POST   /jobs               submit a Request, responds with its Status
+//	GET    /jobs               list the status of every job
+//	GET    /jobs/{id}          poll the status and live best score of a job
+//	GET    /jobs/{id}/events   stream the status as server-sent events until the job finishes
+//	GET    /jobs/{id}/solution fetch the solution of a finished job
+//	DELETE /jobs/{id}          cancel a job
+//
+// Once the server has tenants, every request needs an API key, given as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>".
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", server.handleSubmit)
+	mux.HandleFunc("GET /jobs", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, http.StatusOK, server.List(r.Context()))
+	})
+	mux.HandleFunc("GET /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		status, err := server.Status(r.Context(), r.PathValue("id"))
+		if err != nil {
+			server.writeError(w, err)
+			return
+		}
+		server.writeJSON(w, http.StatusOK, status)
+	})
+	mux.HandleFunc("GET /jobs/{id}/events", server.handleEvents)
+	mux.HandleFunc("GET /jobs/{id}/solution", server.handleSolution)
+	mux.HandleFunc("DELETE /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		status, err := server.Cancel(r.Context(), r.PathValue("id"))
+		if err != nil {
+			server.writeError(w, err)
+			return
+		}
+		server.writeJSON(w, http.StatusAccepted, status)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := server.authenticate(r.Context(), apiKey(r.Header))
+		if err != nil {
+			server.writeError(w, err)
+			return
+		}
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (server *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	request := Request{}
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&request)
+	if err != nil {
+		server.writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	status, err := server.Submit(r.Context(), request)
+	if err != nil {
+		server.writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+status.ID)
+	server.writeJSON(w, http.StatusAccepted, status)
+}
+
+func (server *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	_, err := server.Status(r.Context(), id)
+	if err != nil {
+		server.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	controller := http.NewResponseController(w)
+	err = server.Watch(r.Context(), id, func(status Status) error {
+		data, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+		if err != nil {
+			return err
+		}
+		return controller.Flush()
+	})
+	if err != nil && r.Context().Err() == nil {
+		server.Logger.Warn("failed to stream job events", "job", id, "err", err)
+	}
+}
+
+func (server *Server) handleSolution(w http.ResponseWriter, r *http.Request) {
+	solution, status, err := server.Solution(r.Context(), r.PathValue("id"))
+	if err != nil {
+		server.writeError(w, err)
+		return
+	}
+	if solution == nil {
+		server.writeJSON(w, http.StatusConflict, errorResponse{Error: "job is " + string(status.State)})
+		return
+	}
+	server.writeJSON(w, http.StatusOK, solution)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (server *Server) writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, ErrUnauthenticated):
+		code = http.StatusUnauthorized
+	}
+	server.writeJSON(w, code, errorResponse{Error: err.Error()})
+}
+
+func (server *Server) writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		server.Logger.Warn("failed to write response", "err", err)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+// QueueGroup is the NATS queue group servers consume jobs in, so that every
+// job is delivered to only one of them.
+const QueueGroup = "gen-alg-coloring"
+
+// Result is published when a job finishes. Solution is nil for jobs that were
+// cancelled before they started; Error is set for requests that were rejected.
+type Result struct {
+	Status   *Status                   `json:"status,omitempty"`
+	Solution *ga.GraphColoringSolution `json:"solution,omitempty"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+// ConsumeNATS submits every Request published on subject, authenticated like
+// HTTP requests by the Authorization or X-API-Key header of the message. A
+// request with a reply subject is answered with its Status, or a Result
+// holding the error if it was rejected. When a job taken from the queue
+// finishes, its Result is published on results.<job id>.
+func (server *Server) ConsumeNATS(conn *nats.Conn, subject string, results string) (*nats.Subscription, error) {
+	var mutex sync.Mutex
+	queued := map[string]bool{}
+	server.OnFinish(func(status Status, solution *ga.GraphColoringSolution) {
+		mutex.Lock()
+		fromQueue := queued[status.ID]
+		delete(queued, status.ID)
+		mutex.Unlock()
+		if fromQueue {
+			server.publish(conn, results+"."+status.ID, Result{Status: &status, Solution: solution})
+		}
+	})
+
+	return conn.QueueSubscribe(subject, QueueGroup, func(msg *nats.Msg) {
+		request := Request{}
+		err := json.Unmarshal(msg.Data, &request)
+		var status Status
+		var ctx context.Context
+		if err == nil {
+			ctx, err = server.authenticate(context.Background(), apiKey(http.Header(msg.Header)))
+		}
+		if err == nil {
+			// Hold the lock so that the job cannot finish before it is marked.
+			mutex.Lock()
+			status, err = server.Submit(ctx, request)
+			if err == nil {
+				queued[status.ID] = true
+			}
+			mutex.Unlock()
+		}
+		if err != nil {
+			server.Logger.Warn("rejected queued job", "subject", msg.Subject, "err", err)
+			if msg.Reply != "" {
+				server.publish(conn, msg.Reply, Result{Error: err.Error()})
+			}
+			return
+		}
+		if msg.Reply != "" {
+			server.publish(conn, msg.Reply, status)
+		}
+	})
+}
+
+func (server *Server) publish(conn *nats.Conn, subject string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = conn.Publish(subject, data)
+	}
+	if err != nil {
+		server.Logger.Warn("failed to publish", "subject", subject, "err", err)
+	}
+}
@@ -0,0 +1,963 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: coloring.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type State int32
+
+const (
+	State_STATE_UNSPECIFIED State = 0
+	State_STATE_QUEUED      State = 1
+	State_STATE_RUNNING     State = 2
+	State_STATE_DONE        State = 3
+	State_STATE_CANCELLED   State = 4
+	State_STATE_FAILED      State = 5
+)
+
+// Enum value maps for State.
+var (
+	State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "STATE_QUEUED",
+		2: "STATE_RUNNING",
+		3: "STATE_DONE",
+		4: "STATE_CANCELLED",
+		5: "STATE_FAILED",
+	}
+	State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"STATE_QUEUED":      1,
+		"STATE_RUNNING":     2,
+		"STATE_DONE":        3,
+		"STATE_CANCELLED":   4,
+		"STATE_FAILED":      5,
+	}
+)
+
+func (x State) Enum() *State {
+	p := new(State)
+	*p = x
+	return p
+}
+
+func (x State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (State) Descriptor() protoreflect.EnumDescriptor {
+	return file_coloring_proto_enumTypes[0].Descriptor()
+}
+
+func (State) Type() protoreflect.EnumType {
+	return &file_coloring_proto_enumTypes[0]
+}
+
+func (x State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use State.Descriptor instead.
+func (State) EnumDescriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{0}
+}
+
+// Graph is undirected with nodes numbered from 0; list every edge once.
+// Node, color and score fields are int64, which has the same wire encoding as
+// the int32 they were, so older clients keep working on graphs that fit it.
+type Graph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeCount     int64                  `protobuf:"varint,1,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	Edges         []*Edge                `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Graph) Reset() {
+	*x = Graph{}
+	mi := &file_coloring_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Graph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Graph) ProtoMessage() {}
+
+func (x *Graph) ProtoReflect() protoreflect.Message {
+	mi := &file_coloring_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Graph.ProtoReflect.Descriptor instead.
+func (*Graph) Descriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Graph) GetNodeCount() int64 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *Graph) GetEdges() []*Edge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+type Edge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	U             int64                  `protobuf:"varint,1,opt,name=u,proto3" json:"u,omitempty"`
+	V             int64                  `protobuf:"varint,2,opt,name=v,proto3" json:"v,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Edge) Reset() {
+	*x = Edge{}
+	mi := &file_coloring_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Edge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Edge) ProtoMessage() {}
+
+func (x *Edge) ProtoReflect() protoreflect.Message {
+	mi := &file_coloring_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Edge.ProtoReflect.Descriptor instead.
+func (*Edge) Descriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Edge) GetU() int64 {
+	if x != nil {
+		return x.U
+	}
+	return 0
+}
+
+func (x *Edge) GetV() int64 {
+	if x != nil {
+		return x.V
+	}
+	return 0
+}
+
+// Config mirrors ga.SolverConfig; zero fields keep the solver defaults.
+type Config struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Colors           int64                  `protobuf:"varint,1,opt,name=colors,proto3" json:"colors,omitempty"`
+	Iterations       int32                  `protobuf:"varint,2,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	PopSize          int32                  `protobuf:"varint,3,opt,name=pop_size,json=popSize,proto3" json:"pop_size,omitempty"`
+	Seed             int64                  `protobuf:"varint,4,opt,name=seed,proto3" json:"seed,omitempty"`
+	CheckpointEvery  int32                  `protobuf:"varint,5,opt,name=checkpoint_every,json=checkpointEvery,proto3" json:"checkpoint_every,omitempty"`
+	Selector         string                 `protobuf:"bytes,6,opt,name=selector,proto3" json:"selector,omitempty"`
+	Crossover        string                 `protobuf:"bytes,7,opt,name=crossover,proto3" json:"crossover,omitempty"`
+	Mutator          string                 `protobuf:"bytes,8,opt,name=mutator,proto3" json:"mutator,omitempty"`
+	Fitness          string                 `protobuf:"bytes,9,opt,name=fitness,proto3" json:"fitness,omitempty"`
+	MutationRate     float64                `protobuf:"fixed64,10,opt,name=mutation_rate,json=mutationRate,proto3" json:"mutation_rate,omitempty"`
+	CrossoverRate    float64                `protobuf:"fixed64,11,opt,name=crossover_rate,json=crossoverRate,proto3" json:"crossover_rate,omitempty"`
+	AdaptMutation    bool                   `protobuf:"varint,12,opt,name=adapt_mutation,json=adaptMutation,proto3" json:"adapt_mutation,omitempty"`
+	AdaptCrossover   bool                   `protobuf:"varint,13,opt,name=adapt_crossover,json=adaptCrossover,proto3" json:"adapt_crossover,omitempty"`
+	Elitism          int32                  `protobuf:"varint,14,opt,name=elitism,proto3" json:"elitism,omitempty"`
+	TabuIterations   int32                  `protobuf:"varint,15,opt,name=tabu_iterations,json=tabuIterations,proto3" json:"tabu_iterations,omitempty"`
+	StagnationLimit  int32                  `protobuf:"varint,16,opt,name=stagnation_limit,json=stagnationLimit,proto3" json:"stagnation_limit,omitempty"`
+	StagnationAction string                 `protobuf:"bytes,17,opt,name=stagnation_action,json=stagnationAction,proto3" json:"stagnation_action,omitempty"`
+	StagnationShare  float64                `protobuf:"fixed64,18,opt,name=stagnation_share,json=stagnationShare,proto3" json:"stagnation_share,omitempty"`
+	MaxTime          string                 `protobuf:"bytes,19,opt,name=max_time,json=maxTime,proto3" json:"max_time,omitempty"`
+	TargetScore      int64                  `protobuf:"varint,20,opt,name=target_score,json=targetScore,proto3" json:"target_score,omitempty"`
+	Truncation       string                 `protobuf:"bytes,21,opt,name=truncation,proto3" json:"truncation,omitempty"`
+	FitnessCache     bool                   `protobuf:"varint,22,opt,name=fitness_cache,json=fitnessCache,proto3" json:"fitness_cache,omitempty"`
+	Deduplicate      bool                   `protobuf:"varint,23,opt,name=deduplicate,proto3" json:"deduplicate,omitempty"`
+	SteadyState      bool                   `protobuf:"varint,24,opt,name=steady_state,json=steadyState,proto3" json:"steady_state,omitempty"`
+	MutationPolicy   string                 `protobuf:"bytes,25,opt,name=mutation_policy,json=mutationPolicy,proto3" json:"mutation_policy,omitempty"`
+	Crowding         int32                  `protobuf:"varint,26,opt,name=crowding,proto3" json:"crowding,omitempty"`
+	Repair           bool                   `protobuf:"varint,27,opt,name=repair,proto3" json:"repair,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_coloring_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_coloring_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Config) GetColors() int64 {
+	if x != nil {
+		return x.Colors
+	}
+	return 0
+}
+
+func (x *Config) GetIterations() int32 {
+	if x != nil {
+		return x.Iterations
+	}
+	return 0
+}
+
+func (x *Config) GetPopSize() int32 {
+	if x != nil {
+		return x.PopSize
+	}
+	return 0
+}
+
+func (x *Config) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *Config) GetCheckpointEvery() int32 {
+	if x != nil {
+		return x.CheckpointEvery
+	}
+	return 0
+}
+
+func (x *Config) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *Config) GetCrossover() string {
+	if x != nil {
+		return x.Crossover
+	}
+	return ""
+}
+
+func (x *Config) GetMutator() string {
+	if x != nil {
+		return x.Mutator
+	}
+	return ""
+}
+
+func (x *Config) GetFitness() string {
+	if x != nil {
+		return x.Fitness
+	}
+	return ""
+}
+
+func (x *Config) GetMutationRate() float64 {
+	if x != nil {
+		return x.MutationRate
+	}
+	return 0
+}
+
+func (x *Config) GetCrossoverRate() float64 {
+	if x != nil {
+		return x.CrossoverRate
+	}
+	return 0
+}
+
+func (x *Config) GetAdaptMutation() bool {
+	if x != nil {
+		return x.AdaptMutation
+	}
+	return false
+}
+
+func (x *Config) GetAdaptCrossover() bool {
+	if x != nil {
+		return x.AdaptCrossover
+	}
+	return false
+}
+
+func (x *Config) GetElitism() int32 {
+	if x != nil {
+		return x.Elitism
+	}
+	return 0
+}
+
+func (x *Config) GetTabuIterations() int32 {
+	if x != nil {
+		return x.TabuIterations
+	}
+	return 0
+}
+
+func (x *Config) GetStagnationLimit() int32 {
+	if x != nil {
+		return x.StagnationLimit
+	}
+	return 0
+}
+
+func (x *Config) GetStagnationAction() string {
+	if x != nil {
+		return x.StagnationAction
+	}
+	return ""
+}
+
+func (x *Config) GetStagnationShare() float64 {
+	if x != nil {
+		return x.StagnationShare
+	}
+	return 0
+}
+
+func (x *Config) GetMaxTime() string {
+	if x != nil {
+		return x.MaxTime
+	}
+	return ""
+}
+
+func (x *Config) GetTargetScore() int64 {
+	if x != nil {
+		return x.TargetScore
+	}
+	return 0
+}
+
+func (x *Config) GetTruncation() string {
+	if x != nil {
+		return x.Truncation
+	}
+	return ""
+}
+
+func (x *Config) GetFitnessCache() bool {
+	if x != nil {
+		return x.FitnessCache
+	}
+	return false
+}
+
+func (x *Config) GetDeduplicate() bool {
+	if x != nil {
+		return x.Deduplicate
+	}
+	return false
+}
+
+func (x *Config) GetSteadyState() bool {
+	if x != nil {
+		return x.SteadyState
+	}
+	return false
+}
+
+func (x *Config) GetMutationPolicy() string {
+	if x != nil {
+		return x.MutationPolicy
+	}
+	return ""
+}
+
+func (x *Config) GetCrowding() int32 {
+	if x != nil {
+		return x.Crowding
+	}
+	return 0
+}
+
+func (x *Config) GetRepair() bool {
+	if x != nil {
+		return x.Repair
+	}
+	return false
+}
+
+// SubmitJobRequest gives the graph either inline or as the s3:// or gs:// URI
+// of a DIMACS file.
+type SubmitJobRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Graph  *Graph                 `protobuf:"bytes,2,opt,name=graph,proto3" json:"graph,omitempty"`
+	Config *Config                `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	// callback is posted a JSON summary of the job once it has finished.
+	Callback string `protobuf:"bytes,4,opt,name=callback,proto3" json:"callback,omitempty"`
+	GraphUri string `protobuf:"bytes,5,opt,name=graph_uri,json=graphUri,proto3" json:"graph_uri,omitempty"`
+	// output is an s3:// or gs:// URI the solution is saved to.
+	Output        string `protobuf:"bytes,6,opt,name=output,proto3" json:"output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobRequest) Reset() {
+	*x = SubmitJobRequest{}
+	mi := &file_coloring_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobRequest) ProtoMessage() {}
+
+func (x *SubmitJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coloring_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobRequest.ProtoReflect.Descriptor instead.
+func (*SubmitJobRequest) Descriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubmitJobRequest) GetGraph() *Graph {
+	if x != nil {
+		return x.Graph
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetCallback() string {
+	if x != nil {
+		return x.Callback
+	}
+	return ""
+}
+
+func (x *SubmitJobRequest) GetGraphUri() string {
+	if x != nil {
+		return x.GraphUri
+	}
+	return ""
+}
+
+func (x *SubmitJobRequest) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+type JobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobRequest) Reset() {
+	*x = JobRequest{}
+	mi := &file_coloring_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobRequest) ProtoMessage() {}
+
+func (x *JobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coloring_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobRequest.ProtoReflect.Descriptor instead.
+func (*JobRequest) Descriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// JobStatus has best_score -1 until the first generation completes, and
+// finished_at unset until the job has finished.
+type JobStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	State         State                  `protobuf:"varint,3,opt,name=state,proto3,enum=coloring.v1.State" json:"state,omitempty"`
+	Config        *Config                `protobuf:"bytes,4,opt,name=config,proto3" json:"config,omitempty"`
+	Nodes         int64                  `protobuf:"varint,5,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	Generation    int64                  `protobuf:"varint,6,opt,name=generation,proto3" json:"generation,omitempty"`
+	BestScore     int64                  `protobuf:"varint,7,opt,name=best_score,json=bestScore,proto3" json:"best_score,omitempty"`
+	Elapsed       *durationpb.Duration   `protobuf:"bytes,8,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	SubmittedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=submitted_at,json=submittedAt,proto3" json:"submitted_at,omitempty"`
+	Attempts      int32                  `protobuf:"varint,10,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error         string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	mi := &file_coloring_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_coloring_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *JobStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JobStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobStatus) GetState() State {
+	if x != nil {
+		return x.State
+	}
+	return State_STATE_UNSPECIFIED
+}
+
+func (x *JobStatus) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *JobStatus) GetNodes() int64 {
+	if x != nil {
+		return x.Nodes
+	}
+	return 0
+}
+
+func (x *JobStatus) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *JobStatus) GetBestScore() int64 {
+	if x != nil {
+		return x.BestScore
+	}
+	return 0
+}
+
+func (x *JobStatus) GetElapsed() *durationpb.Duration {
+	if x != nil {
+		return x.Elapsed
+	}
+	return nil
+}
+
+func (x *JobStatus) GetSubmittedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubmittedAt
+	}
+	return nil
+}
+
+func (x *JobStatus) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *JobStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobStatus) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+type Solution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coloring      []int64                `protobuf:"varint,1,rep,packed,name=coloring,proto3" json:"coloring,omitempty"`
+	Score         int64                  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	Config        *Config                `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	Generations   int64                  `protobuf:"varint,4,opt,name=generations,proto3" json:"generations,omitempty"`
+	Evaluations   int64                  `protobuf:"varint,5,opt,name=evaluations,proto3" json:"evaluations,omitempty"`
+	Elapsed       *durationpb.Duration   `protobuf:"bytes,6,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Solution) Reset() {
+	*x = Solution{}
+	mi := &file_coloring_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Solution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Solution) ProtoMessage() {}
+
+func (x *Solution) ProtoReflect() protoreflect.Message {
+	mi := &file_coloring_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Solution.ProtoReflect.Descriptor instead.
+func (*Solution) Descriptor() ([]byte, []int) {
+	return file_coloring_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Solution) GetColoring() []int64 {
+	if x != nil {
+		return x.Coloring
+	}
+	return nil
+}
+
+func (x *Solution) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Solution) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Solution) GetGenerations() int64 {
+	if x != nil {
+		return x.Generations
+	}
+	return 0
+}
+
+func (x *Solution) GetEvaluations() int64 {
+	if x != nil {
+		return x.Evaluations
+	}
+	return 0
+}
+
+func (x *Solution) GetElapsed() *durationpb.Duration {
+	if x != nil {
+		return x.Elapsed
+	}
+	return nil
+}
+
+var File_coloring_proto protoreflect.FileDescriptor
+
+const file_coloring_proto_rawDesc = "" +
+	"\n" +
+	"\x0ecoloring.proto\x12\vcoloring.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"O\n" +
+	"\x05Graph\x12\x1d\n" +
+	"\n" +
+	"node_count\x18\x01 \x01(\x03R\tnodeCount\x12'\n" +
+	"\x05edges\x18\x02 \x03(\v2\x11.coloring.v1.EdgeR\x05edges\"\"\n" +
+	"\x04Edge\x12\f\n" +
+	"\x01u\x18\x01 \x01(\x03R\x01u\x12\f\n" +
+	"\x01v\x18\x02 \x01(\x03R\x01v\"\x8f\a\n" +
+	"\x06Config\x12\x16\n" +
+	"\x06colors\x18\x01 \x01(\x03R\x06colors\x12\x1e\n" +
+	"\n" +
+	"iterations\x18\x02 \x01(\x05R\n" +
+	"iterations\x12\x19\n" +
+	"\bpop_size\x18\x03 \x01(\x05R\apopSize\x12\x12\n" +
+	"\x04seed\x18\x04 \x01(\x03R\x04seed\x12)\n" +
+	"\x10checkpoint_every\x18\x05 \x01(\x05R\x0fcheckpointEvery\x12\x1a\n" +
+	"\bselector\x18\x06 \x01(\tR\bselector\x12\x1c\n" +
+	"\tcrossover\x18\a \x01(\tR\tcrossover\x12\x18\n" +
+	"\amutator\x18\b \x01(\tR\amutator\x12\x18\n" +
+	"\afitness\x18\t \x01(\tR\afitness\x12#\n" +
+	"\rmutation_rate\x18\n" +
+	" \x01(\x01R\fmutationRate\x12%\n" +
+	"\x0ecrossover_rate\x18\v \x01(\x01R\rcrossoverRate\x12%\n" +
+	"\x0eadapt_mutation\x18\f \x01(\bR\radaptMutation\x12'\n" +
+	"\x0fadapt_crossover\x18\r \x01(\bR\x0eadaptCrossover\x12\x18\n" +
+	"\aelitism\x18\x0e \x01(\x05R\aelitism\x12'\n" +
+	"\x0ftabu_iterations\x18\x0f \x01(\x05R\x0etabuIterations\x12)\n" +
+	"\x10stagnation_limit\x18\x10 \x01(\x05R\x0fstagnationLimit\x12+\n" +
+	"\x11stagnation_action\x18\x11 \x01(\tR\x10stagnationAction\x12)\n" +
+	"\x10stagnation_share\x18\x12 \x01(\x01R\x0fstagnationShare\x12\x19\n" +
+	"\bmax_time\x18\x13 \x01(\tR\amaxTime\x12!\n" +
+	"\ftarget_score\x18\x14 \x01(\x03R\vtargetScore\x12\x1e\n" +
+	"\n" +
+	"truncation\x18\x15 \x01(\tR\n" +
+	"truncation\x12#\n" +
+	"\rfitness_cache\x18\x16 \x01(\bR\ffitnessCache\x12 \n" +
+	"\vdeduplicate\x18\x17 \x01(\bR\vdeduplicate\x12!\n" +
+	"\fsteady_state\x18\x18 \x01(\bR\vsteadyState\x12'\n" +
+	"\x0fmutation_policy\x18\x19 \x01(\tR\x0emutationPolicy\x12\x1a\n" +
+	"\bcrowding\x18\x1a \x01(\x05R\bcrowding\x12\x16\n" +
+	"\x06repair\x18\x1b \x01(\bR\x06repair\"\xce\x01\n" +
+	"\x10SubmitJobRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12(\n" +
+	"\x05graph\x18\x02 \x01(\v2\x12.coloring.v1.GraphR\x05graph\x12+\n" +
+	"\x06config\x18\x03 \x01(\v2\x13.coloring.v1.ConfigR\x06config\x12\x1a\n" +
+	"\bcallback\x18\x04 \x01(\tR\bcallback\x12\x1b\n" +
+	"\tgraph_uri\x18\x05 \x01(\tR\bgraphUri\x12\x16\n" +
+	"\x06output\x18\x06 \x01(\tR\x06output\"\x1c\n" +
+	"\n" +
+	"JobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xbe\x03\n" +
+	"\tJobStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12(\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x12.coloring.v1.StateR\x05state\x12+\n" +
+	"\x06config\x18\x04 \x01(\v2\x13.coloring.v1.ConfigR\x06config\x12\x14\n" +
+	"\x05nodes\x18\x05 \x01(\x03R\x05nodes\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x06 \x01(\x03R\n" +
+	"generation\x12\x1d\n" +
+	"\n" +
+	"best_score\x18\a \x01(\x03R\tbestScore\x123\n" +
+	"\aelapsed\x18\b \x01(\v2\x19.google.protobuf.DurationR\aelapsed\x12=\n" +
+	"\fsubmitted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vsubmittedAt\x12\x1a\n" +
+	"\battempts\x18\n" +
+	" \x01(\x05R\battempts\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12;\n" +
+	"\vfinished_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\"\xe2\x01\n" +
+	"\bSolution\x12\x1a\n" +
+	"\bcoloring\x18\x01 \x03(\x03R\bcoloring\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x03R\x05score\x12+\n" +
+	"\x06config\x18\x03 \x01(\v2\x13.coloring.v1.ConfigR\x06config\x12 \n" +
+	"\vgenerations\x18\x04 \x01(\x03R\vgenerations\x12 \n" +
+	"\vevaluations\x18\x05 \x01(\x03R\vevaluations\x123\n" +
+	"\aelapsed\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\aelapsed*z\n" +
+	"\x05State\x12\x15\n" +
+	"\x11STATE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fSTATE_QUEUED\x10\x01\x12\x11\n" +
+	"\rSTATE_RUNNING\x10\x02\x12\x0e\n" +
+	"\n" +
+	"STATE_DONE\x10\x03\x12\x13\n" +
+	"\x0fSTATE_CANCELLED\x10\x04\x12\x10\n" +
+	"\fSTATE_FAILED\x10\x052\x94\x02\n" +
+	"\x0fColoringService\x12B\n" +
+	"\tSubmitJob\x12\x1d.coloring.v1.SubmitJobRequest\x1a\x16.coloring.v1.JobStatus\x12C\n" +
+	"\x0eStreamProgress\x12\x17.coloring.v1.JobRequest\x1a\x16.coloring.v1.JobStatus0\x01\x12=\n" +
+	"\vGetSolution\x12\x17.coloring.v1.JobRequest\x1a\x15.coloring.v1.Solution\x129\n" +
+	"\x06Cancel\x12\x17.coloring.v1.JobRequest\x1a\x16.coloring.v1.JobStatusB9Z7github.com/packedbread/gen-alg-graph-coloring/server/pbb\x06proto3"
+
+var (
+	file_coloring_proto_rawDescOnce sync.Once
+	file_coloring_proto_rawDescData []byte
+)
+
+func file_coloring_proto_rawDescGZIP() []byte {
+	file_coloring_proto_rawDescOnce.Do(func() {
+		file_coloring_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_coloring_proto_rawDesc), len(file_coloring_proto_rawDesc)))
+	})
+	return file_coloring_proto_rawDescData
+}
+
+var file_coloring_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_coloring_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_coloring_proto_goTypes = []any{
+	(State)(0),                    // 0: coloring.v1.State
+	(*Graph)(nil),                 // 1: coloring.v1.Graph
+	(*Edge)(nil),                  // 2: coloring.v1.Edge
+	(*Config)(nil),                // 3: coloring.v1.Config
+	(*SubmitJobRequest)(nil),      // 4: coloring.v1.SubmitJobRequest
+	(*JobRequest)(nil),            // 5: coloring.v1.JobRequest
+	(*JobStatus)(nil),             // 6: coloring.v1.JobStatus
+	(*Solution)(nil),              // 7: coloring.v1.Solution
+	(*durationpb.Duration)(nil),   // 8: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_coloring_proto_depIdxs = []int32{
+	2,  // 0: coloring.v1.Graph.edges:type_name -> coloring.v1.Edge
+	1,  // 1: coloring.v1.SubmitJobRequest.graph:type_name -> coloring.v1.Graph
+	3,  // 2: coloring.v1.SubmitJobRequest.config:type_name -> coloring.v1.Config
+	0,  // 3: coloring.v1.JobStatus.state:type_name -> coloring.v1.State
+	3,  // 4: coloring.v1.JobStatus.config:type_name -> coloring.v1.Config
+	8,  // 5: coloring.v1.JobStatus.elapsed:type_name -> google.protobuf.Duration
+	9,  // 6: coloring.v1.JobStatus.submitted_at:type_name -> google.protobuf.Timestamp
+	9,  // 7: coloring.v1.JobStatus.finished_at:type_name -> google.protobuf.Timestamp
+	3,  // 8: coloring.v1.Solution.config:type_name -> coloring.v1.Config
+	8,  // 9: coloring.v1.Solution.elapsed:type_name -> google.protobuf.Duration
+	4,  // 10: coloring.v1.ColoringService.SubmitJob:input_type -> coloring.v1.SubmitJobRequest
+	5,  // 11: coloring.v1.ColoringService.StreamProgress:input_type -> coloring.v1.JobRequest
+	5,  // 12: coloring.v1.ColoringService.GetSolution:input_type -> coloring.v1.JobRequest
+	5,  // 13: coloring.v1.ColoringService.Cancel:input_type -> coloring.v1.JobRequest
+	6,  // 14: coloring.v1.ColoringService.SubmitJob:output_type -> coloring.v1.JobStatus
+	6,  // 15: coloring.v1.ColoringService.StreamProgress:output_type -> coloring.v1.JobStatus
+	7,  // 16: coloring.v1.ColoringService.GetSolution:output_type -> coloring.v1.Solution
+	6,  // 17: coloring.v1.ColoringService.Cancel:output_type -> coloring.v1.JobStatus
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_coloring_proto_init() }
+func file_coloring_proto_init() {
+	if File_coloring_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_coloring_proto_rawDesc), len(file_coloring_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_coloring_proto_goTypes,
+		DependencyIndexes: file_coloring_proto_depIdxs,
+		EnumInfos:         file_coloring_proto_enumTypes,
+		MessageInfos:      file_coloring_proto_msgTypes,
+	}.Build()
+	File_coloring_proto = out.File
+	file_coloring_proto_goTypes = nil
+	file_coloring_proto_depIdxs = nil
+}
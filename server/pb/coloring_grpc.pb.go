@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: coloring.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ColoringService_SubmitJob_FullMethodName      = "/coloring.v1.ColoringService/SubmitJob"
+	ColoringService_StreamProgress_FullMethodName = "/coloring.v1.ColoringService/StreamProgress"
+	ColoringService_GetSolution_FullMethodName    = "/coloring.v1.ColoringService/GetSolution"
+	ColoringService_Cancel_FullMethodName         = "/coloring.v1.ColoringService/Cancel"
+)
+
+// ColoringServiceClient is the client API for ColoringService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ColoringService runs solver jobs; it mirrors the JSON HTTP API.
+type ColoringServiceClient interface {
+	SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	// StreamProgress sends the job status whenever it changes, skipping updates
+	// a slow client would miss, and ends once the job has finished.
+	StreamProgress(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobStatus], error)
+	GetSolution(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Solution, error)
+	Cancel(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+}
+
+type coloringServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewColoringServiceClient(cc grpc.ClientConnInterface) ColoringServiceClient {
+	return &coloringServiceClient{cc}
+}
+
+func (c *coloringServiceClient) SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, ColoringService_SubmitJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coloringServiceClient) StreamProgress(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ColoringService_ServiceDesc.Streams[0], ColoringService_StreamProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[JobRequest, JobStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ColoringService_StreamProgressClient = grpc.ServerStreamingClient[JobStatus]
+
+func (c *coloringServiceClient) GetSolution(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Solution, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Solution)
+	err := c.cc.Invoke(ctx, ColoringService_GetSolution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coloringServiceClient) Cancel(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, ColoringService_Cancel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ColoringServiceServer is the server API for ColoringService service.
+// All implementations must embed UnimplementedColoringServiceServer
+// for forward compatibility.
+//
+// ColoringService runs solver jobs; it mirrors the JSON HTTP API.
+type ColoringServiceServer interface {
+	SubmitJob(context.Context, *SubmitJobRequest) (*JobStatus, error)
+	// StreamProgress sends the job status whenever it changes, skipping updates
+	// a slow client would miss, and ends once the job has finished.
+	StreamProgress(*JobRequest, grpc.ServerStreamingServer[JobStatus]) error
+	GetSolution(context.Context, *JobRequest) (*Solution, error)
+	Cancel(context.Context, *JobRequest) (*JobStatus, error)
+	mustEmbedUnimplementedColoringServiceServer()
+}
+
+// UnimplementedColoringServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedColoringServiceServer struct{}
+
+func (UnimplementedColoringServiceServer) SubmitJob(context.Context, *SubmitJobRequest) (*JobStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedColoringServiceServer) StreamProgress(*JobRequest, grpc.ServerStreamingServer[JobStatus]) error {
+	return status.Error(codes.Unimplemented, "method StreamProgress not implemented")
+}
+func (UnimplementedColoringServiceServer) GetSolution(context.Context, *JobRequest) (*Solution, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSolution not implemented")
+}
+func (UnimplementedColoringServiceServer) Cancel(context.Context, *JobRequest) (*JobStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedColoringServiceServer) mustEmbedUnimplementedColoringServiceServer() {}
+func (UnimplementedColoringServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeColoringServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ColoringServiceServer will
+// result in compilation errors.
+type UnsafeColoringServiceServer interface {
+	mustEmbedUnimplementedColoringServiceServer()
+}
+
+func RegisterColoringServiceServer(s grpc.ServiceRegistrar, srv ColoringServiceServer) {
+	// If the following call panics, it indicates UnimplementedColoringServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ColoringService_ServiceDesc, srv)
+}
+
+func _ColoringService_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ColoringServiceServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ColoringService_SubmitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ColoringServiceServer).SubmitJob(ctx, req.(*SubmitJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ColoringService_StreamProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ColoringServiceServer).StreamProgress(m, &grpc.GenericServerStream[JobRequest, JobStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ColoringService_StreamProgressServer = grpc.ServerStreamingServer[JobStatus]
+
+func _ColoringService_GetSolution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ColoringServiceServer).GetSolution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ColoringService_GetSolution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ColoringServiceServer).GetSolution(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ColoringService_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ColoringServiceServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ColoringService_Cancel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ColoringServiceServer).Cancel(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ColoringService_ServiceDesc is the grpc.ServiceDesc for ColoringService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ColoringService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coloring.v1.ColoringService",
+	HandlerType: (*ColoringServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitJob",
+			Handler:    _ColoringService_SubmitJob_Handler,
+		},
+		{
+			MethodName: "GetSolution",
+			Handler:    _ColoringService_GetSolution_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _ColoringService_Cancel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamProgress",
+			Handler:       _ColoringService_StreamProgress_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "coloring.proto",
+}
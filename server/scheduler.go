@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+// scheduler hands out the slots of a server. A free slot goes to a waiting job
+// of the highest priority class, among those to the tenant with the fewest
+// running jobs and then to the one served least recently, and among its jobs
+// to the one submitted first. Tenants at their quota are skipped.
+type scheduler struct {
+	mutex   sync.Mutex
+	free    int
+	waiting []*waiter
+	running map[string]int
+	// served holds the number of the grant each tenant got last.
+	served map[string]int
+	grants int
+}
+
+type waiter struct {
+	tenant Tenant
+	ready  chan struct{}
+}
+
+func newScheduler(slots int) *scheduler {
+	return &scheduler{free: slots, running: map[string]int{}, served: map[string]int{}}
+}
+
+// acquire waits for a slot for a job of tenant, or until ctx is done.
+func (scheduler *scheduler) acquire(ctx context.Context, tenant Tenant) error {
+	waiter := &waiter{tenant: tenant, ready: make(chan struct{})}
+	scheduler.mutex.Lock()
+	scheduler.waiting = append(scheduler.waiting, waiter)
+	scheduler.dispatch()
+	scheduler.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	for i, other := range scheduler.waiting {
+		if other == waiter {
+			scheduler.waiting = append(scheduler.waiting[:i], scheduler.waiting[i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// The slot was granted while ctx was done; hand it on.
+	scheduler.releaseLocked(tenant)
+	return ctx.Err()
+}
+
+func (scheduler *scheduler) release(tenant Tenant) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	scheduler.releaseLocked(tenant)
+}
+
+func (scheduler *scheduler) releaseLocked(tenant Tenant) {
+	scheduler.free++
+	scheduler.running[tenant.Name]--
+	if scheduler.running[tenant.Name] == 0 {
+		delete(scheduler.running, tenant.Name)
+	}
+	scheduler.dispatch()
+}
+
+func (scheduler *scheduler) dispatch() {
+	for scheduler.free > 0 {
+		next := -1
+		for i, waiter := range scheduler.waiting {
+			running := scheduler.running[waiter.tenant.Name]
+			if waiter.tenant.MaxJobs > 0 && running >= waiter.tenant.MaxJobs {
+				continue
+			}
+			if next < 0 || scheduler.before(waiter, scheduler.waiting[next]) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+
+		waiter := scheduler.waiting[next]
+		scheduler.waiting = append(scheduler.waiting[:next], scheduler.waiting[next+1:]...)
+		scheduler.free--
+		scheduler.running[waiter.tenant.Name]++
+		scheduler.grants++
+		scheduler.served[waiter.tenant.Name] = scheduler.grants
+		close(waiter.ready)
+	}
+}
+
+// before reports whether a should get a slot before b, which was submitted
+// earlier.
+func (scheduler *scheduler) before(a *waiter, b *waiter) bool {
+	if a.tenant.Priority != b.tenant.Priority {
+		return a.tenant.Priority > b.tenant.Priority
+	}
+	if scheduler.running[a.tenant.Name] != scheduler.running[b.tenant.Name] {
+		return scheduler.running[a.tenant.Name] < scheduler.running[b.tenant.Name]
+	}
+	return scheduler.served[a.tenant.Name] < scheduler.served[b.tenant.Name]
+}
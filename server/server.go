@@ -0,0 +1,639 @@
+// Package server runs solver jobs in the background and exposes them over a
+// JSON HTTP API and a gRPC service, so that other systems can use the solver
+// as a service.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/packedbread/gen-alg-graph-coloring/encoding"
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+var tracer = otel.Tracer("github.com/packedbread/gen-alg-graph-coloring/server")
+
+type State string
+
+const (
+	Queued    State = "queued"
+	Running   State = "running"
+	Done      State = "done"
+	Cancelled State = "cancelled"
+	Failed    State = "failed"
+)
+
+// Request submits a graph in DIMACS format, given inline or as the s3:// or
+// gs:// URI of a file. Zero config fields keep the solver defaults. If Output
+// is set, the solution is saved to that s3:// or gs:// URI once the job has
+// finished, and if Callback is set, a Summary is posted to it after that.
+// Tenants may only use the storage and callback hosts of their Tenant.
+type Request struct {
+	Name     string          `json:"name,omitempty"`
+	DIMACS   string          `json:"dimacs,omitempty"`
+	GraphURI string          `json:"graph_uri,omitempty"`
+	Config   ga.SolverConfig `json:"config"`
+	Output   string          `json:"output,omitempty"`
+	Callback string          `json:"callback,omitempty"`
+}
+
+// Status is a snapshot of a job. BestScore is -1 until the first generation
+// completes. Error says why the last attempt of the job failed.
+type Status struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name,omitempty"`
+	State       State           `json:"state"`
+	Config      ga.SolverConfig `json:"config"`
+	Nodes       int             `json:"nodes"`
+	Generation  int             `json:"generation"`
+	BestScore   int             `json:"best_score"`
+	Elapsed     time.Duration   `json:"elapsed"`
+	Attempts    int             `json:"attempts"`
+	Error       string          `json:"error,omitempty"`
+	Tenant      string          `json:"tenant,omitempty"`
+	Output      string          `json:"output,omitempty"`
+	Callback    string          `json:"callback,omitempty"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	FinishedAt  time.Time       `json:"finished_at,omitzero"`
+}
+
+func (state State) Finished() bool {
+	return state == Done || state == Cancelled || state == Failed
+}
+
+// Limits caps the jobs a server accepts. Zero fields are unlimited.
+type Limits struct {
+	MaxNodes      int
+	MaxIterations int
+	MaxPopSize    int
+	// MaxDuration stops jobs that run for longer; they fail with the best
+	// solution found so far and are not retried.
+	MaxDuration time.Duration
+}
+
+func (limits Limits) check(nodes int, config ga.SolverConfig) error {
+	var errs []error
+	if limits.MaxNodes > 0 && nodes > limits.MaxNodes {
+		errs = append(errs, fmt.Errorf("graph has %d nodes, the limit is %d", nodes, limits.MaxNodes))
+	}
+	if limits.MaxIterations > 0 && config.Iterations > limits.MaxIterations {
+		errs = append(errs, fmt.Errorf("iterations %d are over the limit of %d", config.Iterations, limits.MaxIterations))
+	}
+	if limits.MaxPopSize > 0 && config.PopSize > limits.MaxPopSize {
+		errs = append(errs, fmt.Errorf("popsize %d is over the limit of %d", config.PopSize, limits.MaxPopSize))
+	}
+	return errors.Join(errs...)
+}
+
+var errTimeLimit = errors.New("time limit exceeded")
+
+type job struct {
+	graph  graph.Interface
+	config ga.SolverConfig
+	// dimacs is the graph as it is kept in the store.
+	dimacs string
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex    sync.Mutex
+	status   Status
+	solution *ga.GraphColoringSolution
+	link     trace.Link
+	// updated is closed and replaced whenever status changes.
+	updated chan struct{}
+}
+
+func (job *job) snapshot() Status {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	return job.status
+}
+
+func (job *job) update(change func(status *Status)) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	change(&job.status)
+	close(job.updated)
+	job.updated = make(chan struct{})
+}
+
+func (job *job) Notify(event ga.Event) {
+	generation, ok := event.(ga.GenerationCompleted)
+	if !ok {
+		return
+	}
+	job.update(func(status *Status) {
+		status.Generation = generation.Stats.Generation + 1
+		status.BestScore = generation.Stats.Best
+		status.Elapsed = generation.Stats.Elapsed
+	})
+}
+
+// Server keeps every submitted job in memory, and in a Store if it has one,
+// and runs at most Parallelism of them at once; the rest wait for the
+// scheduler.
+type Server struct {
+	Logger *slog.Logger
+	Limits Limits
+	// BaseURL is prepended to the links in callback summaries, which are
+	// relative to the HTTP API without it.
+	BaseURL string
+	// MaxAttempts is how often a job is run before it is given up on when the
+	// solver panics or the server stops while running it.
+	MaxAttempts int
+
+	mutex     sync.Mutex
+	jobs      map[string]*job
+	order     []string
+	scheduler *scheduler
+	listeners []FinishFunc
+	store     *Store
+	// The tenants are nil while the server is open to everyone.
+	tenantsByKey  map[string]Tenant
+	tenantsByName map[string]Tenant
+}
+
+// FinishFunc is called once a job has finished, with the solution found so far
+// or nil if the job never got to run.
+type FinishFunc func(status Status, solution *ga.GraphColoringSolution)
+
+// OnFinish adds listener to the functions called whenever a job finishes.
+// Listeners run on the goroutine of the job, one after another.
+func (server *Server) OnFinish(listener FinishFunc) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.listeners = append(server.listeners, listener)
+}
+
+func (server *Server) finished(job *job) {
+	server.mutex.Lock()
+	listeners := server.listeners
+	server.mutex.Unlock()
+
+	job.mutex.Lock()
+	status, solution := job.status, job.solution
+	job.mutex.Unlock()
+	for _, listener := range listeners {
+		listener(status, solution)
+	}
+}
+
+// New returns a server running up to parallelism jobs at once, or one per CPU
+// if parallelism is below 1. A nil logger logs to slog.Default().
+func New(parallelism int, logger *slog.Logger) *Server {
+	if parallelism < 1 {
+		parallelism = runtime.NumCPU()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	server := &Server{
+		Logger:      logger,
+		MaxAttempts: 3,
+		jobs:        map[string]*job{},
+		scheduler:   newScheduler(parallelism),
+	}
+	server.OnFinish(server.saveOutput)
+	server.OnFinish(server.callBack)
+	return server
+}
+
+// UseStore keeps the jobs of the server in store from now on and takes over the
+// jobs store already holds: finished ones can be queried again and unfinished
+// ones are queued again, a run cut short by a restart counting as an attempt.
+// Call it before submitting any job.
+func (server *Server) UseStore(store *Store) error {
+	stored, err := store.load()
+	if err != nil {
+		return err
+	}
+	server.store = store
+	for _, entry := range stored {
+		g, err := encoding.ReadDIMACS(strings.NewReader(entry.DIMACS))
+		if err != nil {
+			return fmt.Errorf("job %s: %w", entry.Status.ID, err)
+		}
+		job := newJob(entry.Status, g)
+		job.dimacs = entry.DIMACS
+		job.solution = entry.Solution
+		server.add(job)
+		if entry.Status.State.Finished() {
+			continue
+		}
+
+		logger := server.Logger.With("job", entry.Status.ID)
+		if entry.Status.Attempts >= server.MaxAttempts {
+			job.update(func(status *Status) {
+				status.State = Failed
+				status.Error = "interrupted too often"
+				status.FinishedAt = time.Now()
+			})
+			logger.Warn("giving up on interrupted job", "attempts", entry.Status.Attempts)
+			server.persist(job)
+			continue
+		}
+		job.update(func(status *Status) {
+			status.State = Queued
+		})
+		logger.Info("job resumed", "name", entry.Status.Name, "attempts", entry.Status.Attempts)
+		go server.run(job, logger)
+	}
+	return nil
+}
+
+func newJob(status Status, g graph.Interface) *job {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &job{
+		graph:   g,
+		config:  status.Config,
+		ctx:     ctx,
+		cancel:  cancel,
+		status:  status,
+		updated: make(chan struct{}),
+	}
+}
+
+func (server *Server) add(job *job) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.jobs[job.status.ID] = job
+	server.order = append(server.order, job.status.ID)
+}
+
+// persist saves job to the store of the server, if it has one.
+func (server *Server) persist(job *job) {
+	if server.store == nil {
+		return
+	}
+	job.mutex.Lock()
+	stored := storedJob{Status: job.status, DIMACS: job.dimacs, Solution: job.solution}
+	job.mutex.Unlock()
+	err := server.store.save(stored)
+	if err != nil {
+		server.Logger.Warn("failed to store job", "job", stored.Status.ID, "err", err)
+	}
+}
+
+// ErrNotFound is returned for job IDs the server does not know.
+var ErrNotFound = errors.New("job not found")
+
+func (server *Server) Submit(ctx context.Context, request Request) (Status, error) {
+	if tenant, ok := tenantFrom(ctx); ok && request.GraphURI != "" {
+		err := tenant.checkStorage("graph", request.GraphURI)
+		if err != nil {
+			return Status{}, err
+		}
+	}
+	loadCtx, span := tracer.Start(ctx, "load graph", trace.WithAttributes(
+		attribute.Int("graph.bytes", len(request.DIMACS)),
+		attribute.String("graph.uri", request.GraphURI),
+	))
+	g, err := loadGraph(loadCtx, request)
+	span.End()
+	if err != nil {
+		return Status{}, fmt.Errorf("graph: %w", err)
+	}
+	return server.SubmitGraph(ctx, g, request)
+}
+
+func loadGraph(ctx context.Context, request Request) (*graph.Graph, error) {
+	if request.GraphURI == "" {
+		return encoding.ReadDIMACS(strings.NewReader(request.DIMACS))
+	}
+	if request.DIMACS != "" {
+		return nil, errors.New("give either dimacs or graph_uri, not both")
+	}
+	// Only object storage, so that clients cannot read files of the server.
+	if !storage.IsRemote(request.GraphURI) {
+		return nil, fmt.Errorf("%q is not an s3:// or gs:// URI", request.GraphURI)
+	}
+	data, err := storage.ReadFile(ctx, request.GraphURI)
+	if err != nil {
+		return nil, err
+	}
+	return encoding.ReadDIMACS(bytes.NewReader(data))
+}
+
+// saveOutput saves the solution of a finished job to its output, if it has
+// both.
+func (server *Server) saveOutput(status Status, solution *ga.GraphColoringSolution) {
+	if status.Output == "" || solution == nil {
+		return
+	}
+	err := encoding.SaveSolution(status.Output, solution)
+	if err != nil {
+		server.Logger.Warn("failed to save solution", "job", status.ID, "output", status.Output, "err", err)
+	}
+}
+
+// SubmitGraph queues a job solving g, which must not change until the job
+// has finished, with the other settings of request; its graph fields are
+// ignored. The job is traced separately from ctx, which it outlives, and
+// links back to it.
+func (server *Server) SubmitGraph(ctx context.Context, g graph.Interface, request Request) (Status, error) {
+	if g.NodeCount() == 0 {
+		return Status{}, errors.New("graph: no problem line or no nodes")
+	}
+	tenant, hasTenant := tenantFrom(ctx)
+	err := checkCallback(request.Callback, tenant, hasTenant)
+	if err != nil {
+		return Status{}, err
+	}
+	if request.Output != "" && !storage.IsRemote(request.Output) {
+		return Status{}, fmt.Errorf("output: %q is not an s3:// or gs:// URI", request.Output)
+	}
+	if request.Output != "" && hasTenant {
+		err = tenant.checkStorage("output", request.Output)
+		if err != nil {
+			return Status{}, err
+		}
+	}
+	options, err := request.Config.Options()
+	if err != nil {
+		return Status{}, err
+	}
+
+	// The solver resolves the defaults, so that retries repeat the same run,
+	// and compacts the graph, which the job keeps for its attempts.
+	resolved := ga.NewSolver(g, options...)
+	config := resolved.Config()
+	err = server.Limits.check(g.NodeCount(), config)
+	if err != nil {
+		return Status{}, err
+	}
+
+	// Random IDs stay unique across the servers that share a job queue.
+	idBytes := make([]byte, 8)
+	_, err = rand.Read(idBytes)
+	if err != nil {
+		return Status{}, err
+	}
+	id := hex.EncodeToString(idBytes)
+
+	job := newJob(Status{
+		ID:          id,
+		Name:        request.Name,
+		State:       Queued,
+		Config:      config,
+		Nodes:       g.NodeCount(),
+		BestScore:   -1,
+		Tenant:      tenant.Name,
+		Output:      request.Output,
+		Callback:    request.Callback,
+		SubmittedAt: time.Now(),
+	}, resolved.Graph)
+	job.link = trace.LinkFromContext(ctx)
+	if server.store != nil {
+		dimacs := strings.Builder{}
+		err = encoding.WriteDIMACS(&dimacs, g)
+		if err == nil {
+			job.dimacs = dimacs.String()
+			err = server.store.save(storedJob{Status: job.status, DIMACS: job.dimacs})
+		}
+		if err != nil {
+			return Status{}, fmt.Errorf("store: %w", err)
+		}
+	}
+	server.add(job)
+
+	logger := server.Logger.With("job", id)
+	logger.Info("job submitted", "name", request.Name, "tenant", tenant.Name, "nodes", g.NodeCount())
+	go server.run(job, logger)
+	return job.snapshot(), nil
+}
+
+func (server *Server) run(job *job, logger *slog.Logger) {
+	defer job.cancel()
+	var solution *ga.GraphColoringSolution
+	var err error
+	tenant := server.tenant(job.snapshot().Tenant)
+	for {
+		err = server.scheduler.acquire(job.ctx, tenant)
+		if err != nil {
+			job.update(func(status *Status) {
+				status.State = Cancelled
+				status.FinishedAt = time.Now()
+			})
+			logger.Info("job cancelled before it ran")
+			server.persist(job)
+			server.finished(job)
+			return
+		}
+
+		job.update(func(status *Status) {
+			status.State = Running
+			status.Attempts++
+		})
+		server.persist(job)
+		solution, err = server.attempt(job, logger)
+		server.scheduler.release(tenant)
+
+		attempts := job.snapshot().Attempts
+		if err == nil || errors.Is(err, errTimeLimit) || job.ctx.Err() != nil || attempts >= server.MaxAttempts {
+			break
+		}
+		logger.Warn("job failed, retrying", "attempt", attempts, "err", err)
+		job.update(func(status *Status) {
+			status.State = Queued
+			status.Error = err.Error()
+		})
+		server.persist(job)
+	}
+
+	state := Done
+	if job.ctx.Err() != nil {
+		state = Cancelled
+	} else if err != nil {
+		state = Failed
+	}
+	job.update(func(status *Status) {
+		job.solution = solution
+		status.State = state
+		status.Error = ""
+		if err != nil {
+			status.Error = err.Error()
+		}
+		if solution != nil {
+			status.Generation = solution.Metadata.Generations
+			status.BestScore = solution.Score
+			status.Elapsed = solution.Metadata.Elapsed
+		}
+		status.FinishedAt = time.Now()
+	})
+	status := job.snapshot()
+	logger.Info("job finished", "state", state, "score", status.BestScore, "generations", status.Generation, "err", status.Error)
+	server.persist(job)
+	server.finished(job)
+}
+
+// attempt runs the solver of job once. A panic of the solver is returned as an
+// error, without a solution.
+func (server *Server) attempt(job *job, logger *slog.Logger) (solution *ga.GraphColoringSolution, err error) {
+	options, err := job.config.Options()
+	if err != nil {
+		return nil, err
+	}
+	solver := ga.NewSolver(job.graph, append(options, ga.WithLogger(logger))...)
+	solver.Subscribe(job)
+
+	ctx := job.ctx
+	if server.Limits.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, server.Limits.MaxDuration)
+		defer cancel()
+	}
+	spanOptions := []trace.SpanStartOption{trace.WithAttributes(
+		attribute.String("job.id", job.status.ID),
+		attribute.String("job.name", job.status.Name),
+		attribute.Int("job.attempt", job.snapshot().Attempts),
+	)}
+	if job.link.SpanContext.IsValid() {
+		spanOptions = append(spanOptions, trace.WithLinks(job.link))
+	}
+	ctx, span := tracer.Start(ctx, "job", spanOptions...)
+	defer span.End()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			solution, err = nil, fmt.Errorf("solver panicked: %v", recovered)
+		}
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+
+	result := solver.SolveContext(ctx)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && job.ctx.Err() == nil {
+		err = errTimeLimit
+	}
+	return &result, err
+}
+
+// lookup returns the job with id if the request with ctx may see it.
+func (server *Server) lookup(ctx context.Context, id string) (*job, error) {
+	server.mutex.Lock()
+	job, exists := server.jobs[id]
+	server.mutex.Unlock()
+	if !exists || !visible(ctx, job.snapshot()) {
+		return nil, ErrNotFound
+	}
+	return job, nil
+}
+
+func (server *Server) Status(ctx context.Context, id string) (Status, error) {
+	job, err := server.lookup(ctx, id)
+	if err != nil {
+		return Status{}, err
+	}
+	return job.snapshot(), nil
+}
+
+// List returns the status of every job the request with ctx may see, in
+// submission order.
+func (server *Server) List(ctx context.Context) []Status {
+	server.mutex.Lock()
+	jobs := make([]*job, len(server.order))
+	for i, id := range server.order {
+		jobs[i] = server.jobs[id]
+	}
+	server.mutex.Unlock()
+
+	statuses := make([]Status, 0, len(jobs))
+	for _, job := range jobs {
+		status := job.snapshot()
+		if visible(ctx, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// Watch calls fn with the status of a job and again whenever it changes, until
+// the job has finished, fn fails or ctx is done. Changes that happen while fn
+// runs are coalesced into the next call.
+func (server *Server) Watch(ctx context.Context, id string, fn func(status Status) error) error {
+	job, err := server.lookup(ctx, id)
+	if err != nil {
+		return err
+	}
+	for {
+		job.mutex.Lock()
+		status := job.status
+		updated := job.updated
+		job.mutex.Unlock()
+
+		err := fn(status)
+		if err != nil || status.State.Finished() {
+			return err
+		}
+		select {
+		case <-updated:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Solution returns the final solution of a job, or nil while it has not
+// finished. Jobs cancelled while running keep the best solution found so far.
+func (server *Server) Solution(ctx context.Context, id string) (*ga.GraphColoringSolution, Status, error) {
+	job, err := server.lookup(ctx, id)
+	if err != nil {
+		return nil, Status{}, err
+	}
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	return job.solution, job.status, nil
+}
+
+// Cancel stops a queued or running job; finished jobs are left as they are.
+func (server *Server) Cancel(ctx context.Context, id string) (Status, error) {
+	job, err := server.lookup(ctx, id)
+	if err != nil {
+		return Status{}, err
+	}
+	job.cancel()
+	return job.snapshot(), nil
+}
+
+// Collect forgets the jobs that finished before the given time, also removing
+// them from the store, and returns how many there were.
+func (server *Server) Collect(before time.Time) (int, error) {
+	var expired []string
+	for _, status := range server.List(context.Background()) {
+		if status.State.Finished() && status.FinishedAt.Before(before) {
+			expired = append(expired, status.ID)
+		}
+	}
+	for i, id := range expired {
+		if server.store != nil {
+			err := server.store.delete(id)
+			if err != nil {
+				return i, err
+			}
+		}
+		server.mutex.Lock()
+		delete(server.jobs, id)
+		server.order = slices.DeleteFunc(server.order, func(other string) bool {
+			return other == id
+		})
+		server.mutex.Unlock()
+	}
+	return len(expired), nil
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+const jobsSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id          TEXT PRIMARY KEY,
+	state       TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	dimacs      TEXT NOT NULL,
+	solution    TEXT,
+	finished_at TEXT
+)`
+
+// Store keeps jobs in an SQLite database so that a server can pick them up
+// again after a restart.
+type Store struct {
+	db *sql.DB
+}
+
+type storedJob struct {
+	Status   Status
+	DIMACS   string
+	Solution *ga.GraphColoringSolution
+}
+
+func OpenStore(filename string) (*Store, error) {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, err
+	}
+	// Jobs are saved from many goroutines; one connection avoids SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(jobsSchema)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (store *Store) Close() error {
+	return store.db.Close()
+}
+
+// save inserts a job, or updates its status and solution if it is stored
+// already. The graph is only written on insert.
+func (store *Store) save(job storedJob) error {
+	status, err := json.Marshal(job.Status)
+	if err != nil {
+		return err
+	}
+	var solution, finishedAt sql.NullString
+	if job.Solution != nil {
+		data, err := json.Marshal(job.Solution)
+		if err != nil {
+			return err
+		}
+		solution = sql.NullString{String: string(data), Valid: true}
+	}
+	if !job.Status.FinishedAt.IsZero() {
+		finishedAt = sql.NullString{String: job.Status.FinishedAt.UTC().Format(time.RFC3339), Valid: true}
+	}
+
+	_, err = store.db.Exec(
+		`INSERT INTO jobs (id, state, status, dimacs, solution, finished_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			state = excluded.state,
+			status = excluded.status,
+			solution = excluded.solution,
+			finished_at = excluded.finished_at`,
+		job.Status.ID,
+		string(job.Status.State),
+		string(status),
+		job.DIMACS,
+		solution,
+		finishedAt,
+	)
+	return err
+}
+
+// load returns every stored job in submission order.
+func (store *Store) load() ([]storedJob, error) {
+	rows, err := store.db.Query(`SELECT status, dimacs, solution FROM jobs`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []storedJob
+	for rows.Next() {
+		var status string
+		var solution sql.NullString
+		job := storedJob{}
+		err = rows.Scan(&status, &job.DIMACS, &solution)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(status), &job.Status)
+		if err != nil {
+			return nil, err
+		}
+		if solution.Valid {
+			job.Solution = &ga.GraphColoringSolution{}
+			err = json.Unmarshal([]byte(solution.String), job.Solution)
+			if err != nil {
+				return nil, err
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(jobs, func(a, b storedJob) int {
+		return a.Status.SubmittedAt.Compare(b.Status.SubmittedAt)
+	})
+	return jobs, nil
+}
+
+func (store *Store) delete(id string) error {
+	_, err := store.db.Exec(`DELETE FROM jobs WHERE id = ?`, id)
+	return err
+}
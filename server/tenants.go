@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/storage"
+)
+
+// Tenant is a client of a shared server, identified by its API key. Jobs of
+// a higher Priority run first; MaxJobs caps how many jobs of the tenant run at
+// once, 0 meaning no cap.
+//
+// As all tenants share the credentials of the server, the graph_uri and
+// output of their jobs must start with one of the Storage prefixes, such as
+// "s3://bucket/tenant/", and their callbacks must go to one of the
+// CallbackHosts, and never to a loopback, private or link-local address.
+// Without any a tenant cannot use object storage or callbacks.
+type Tenant struct {
+	Name          string   `json:"name"`
+	Key           string   `json:"key"`
+	Priority      int      `json:"priority,omitempty"`
+	MaxJobs       int      `json:"max_jobs,omitempty"`
+	Storage       []string `json:"storage,omitempty"`
+	CallbackHosts []string `json:"callback_hosts,omitempty"`
+}
+
+// ErrUnauthenticated is returned for requests without a known API key while
+// the server has tenants.
+var ErrUnauthenticated = errors.New("missing or unknown API key")
+
+// LoadTenants reads a JSON list of tenants.
+func LoadTenants(filename string) ([]Tenant, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	tenants := []Tenant{}
+	err = json.Unmarshal(data, &tenants)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return tenants, nil
+}
+
+// SetTenants makes the server require the API key of one of tenants on every
+// request, and keeps the jobs of every tenant apart from the others. Call it
+// before serving any request.
+func (server *Server) SetTenants(tenants []Tenant) error {
+	byKey := map[string]Tenant{}
+	byName := map[string]Tenant{}
+	for _, tenant := range tenants {
+		if tenant.Name == "" || tenant.Key == "" {
+			return errors.New("tenants need a name and a key")
+		}
+		if _, exists := byKey[tenant.Key]; exists {
+			return fmt.Errorf("tenant %s reuses an API key", tenant.Name)
+		}
+		if _, exists := byName[tenant.Name]; exists {
+			return fmt.Errorf("tenant %s is listed twice", tenant.Name)
+		}
+		for _, prefix := range tenant.Storage {
+			if !storage.IsRemote(prefix) || !strings.HasSuffix(prefix, "/") {
+				return fmt.Errorf("tenant %s: storage %q is not an s3:// or gs:// URI ending in /", tenant.Name, prefix)
+			}
+		}
+		byKey[tenant.Key] = tenant
+		byName[tenant.Name] = tenant
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.tenantsByKey = byKey
+	server.tenantsByName = byName
+	return nil
+}
+
+type tenantKey struct{}
+
+// WithTenant returns ctx for requests on behalf of tenant.
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func tenantFrom(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
+
+// authenticate returns ctx for the tenant with the given API key, or ctx
+// itself if the server has no tenants.
+func (server *Server) authenticate(ctx context.Context, key string) (context.Context, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.tenantsByKey == nil {
+		return ctx, nil
+	}
+	tenant, ok := server.tenantsByKey[key]
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return WithTenant(ctx, tenant), nil
+}
+
+// checkStorage returns an error unless uri lies under one of the Storage
+// prefixes of tenant. field names the request field uri is from.
+func (tenant Tenant) checkStorage(field string, uri string) error {
+	allowed := slices.ContainsFunc(tenant.Storage, func(prefix string) bool {
+		return strings.HasPrefix(uri, prefix)
+	})
+	if !allowed || slices.Contains(strings.Split(uri, "/"), "..") {
+		return fmt.Errorf("%s: %q is outside the storage of tenant %s", field, uri, tenant.Name)
+	}
+	return nil
+}
+
+// checkCallbackHost returns an error unless the host of callback, which
+// checkCallback has parsed, is one of the CallbackHosts of tenant.
+func (tenant Tenant) checkCallbackHost(callback *url.URL) error {
+	if !slices.Contains(tenant.CallbackHosts, callback.Hostname()) {
+		return fmt.Errorf("callback: host %q is not allowed for tenant %s", callback.Hostname(), tenant.Name)
+	}
+	return nil
+}
+
+// tenant returns the current settings of the tenant a job was submitted by.
+func (server *Server) tenant(name string) Tenant {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	tenant, ok := server.tenantsByName[name]
+	if !ok {
+		return Tenant{Name: name}
+	}
+	return tenant
+}
+
+// visible reports whether a request with ctx may see the job of status. Jobs
+// of one tenant are hidden from the others.
+func visible(ctx context.Context, status Status) bool {
+	tenant, ok := tenantFrom(ctx)
+	return !ok || tenant.Name == status.Tenant
+}
+
+func apiKey(header http.Header) string {
+	key, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if ok {
+		return key
+	}
+	return header.Get("X-API-Key")
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+const (
+	callbackAttempts = 3
+	callbackTimeout  = 10 * time.Second
+)
+
+// Summary is posted to the callback of a job once it has finished. Score is
+// -1 and Colors 0 for jobs that never got to run.
+type Summary struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name,omitempty"`
+	State   State         `json:"state"`
+	Score   int           `json:"score"`
+	Colors  int           `json:"colors"`
+	Elapsed time.Duration `json:"elapsed"`
+	Error   string        `json:"error,omitempty"`
+	Links   Links         `json:"links"`
+}
+
+// Links point to the artifacts of a job in the HTTP API, and to the solution
+// saved to the output of the job if it has one.
+type Links struct {
+	Status   string `json:"status"`
+	Solution string `json:"solution,omitempty"`
+	Output   string `json:"output,omitempty"`
+}
+
+// checkCallback returns an error unless callback is empty or an absolute
+// http(s) URL, on one of the CallbackHosts of tenant if there is one.
+func checkCallback(callback string, tenant Tenant, hasTenant bool) error {
+	if callback == "" {
+		return nil
+	}
+	parsed, err := url.Parse(callback)
+	if err != nil {
+		return fmt.Errorf("callback: %w", err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("callback: %q is not an absolute http(s) URL", callback)
+	}
+	if !hasTenant {
+		return nil
+	}
+	err = tenant.checkCallbackHost(parsed)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil && internal(ip) {
+		return fmt.Errorf("callback: %s is an internal address", ip)
+	}
+	return nil
+}
+
+// internal reports whether ip is an address callbacks of tenants must not
+// reach, as it belongs to the server or its network.
+func internal(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// tenantClient posts the callbacks of tenants. It checks every address it
+// connects to after the host name is resolved, so that a host that resolves
+// to an internal address is refused as well, and follows no redirects or
+// proxies, which could lead there too.
+var tenantClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: callbackTimeout,
+			Control: func(network string, address string, conn syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || internal(ip) {
+					return fmt.Errorf("callback: %s is an internal address", host)
+				}
+				return nil
+			},
+		}).DialContext,
+	},
+	CheckRedirect: func(request *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func (server *Server) summary(status Status, solution *ga.GraphColoringSolution) Summary {
+	summary := Summary{
+		ID:      status.ID,
+		Name:    status.Name,
+		State:   status.State,
+		Score:   status.BestScore,
+		Elapsed: status.Elapsed,
+		Error:   status.Error,
+		Links:   Links{Status: server.BaseURL + "/jobs/" + status.ID},
+	}
+	if solution != nil {
+		colors := map[int]bool{}
+		for _, color := range solution.Coloring {
+			colors[color] = true
+		}
+		summary.Colors = len(colors)
+		summary.Links.Solution = summary.Links.Status + "/solution"
+		summary.Links.Output = status.Output
+	}
+	return summary
+}
+
+// callBack posts the summary of a finished job to its callback in the
+// background, trying a few times with growing pauses before giving up.
+func (server *Server) callBack(status Status, solution *ga.GraphColoringSolution) {
+	if status.Callback == "" {
+		return
+	}
+	body, err := json.Marshal(server.summary(status, solution))
+	if err != nil {
+		server.Logger.Warn("failed to encode callback", "job", status.ID, "err", err)
+		return
+	}
+
+	go func() {
+		for attempt := 1; ; attempt++ {
+			client := http.DefaultClient
+			if status.Tenant != "" {
+				client = tenantClient
+			}
+			err := post(client, status.Callback, body)
+			if err == nil {
+				return
+			}
+			if attempt == callbackAttempts {
+				server.Logger.Warn("giving up on callback", "job", status.ID, "callback", status.Callback, "err", err)
+				return
+			}
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}()
+}
+
+func post(client *http.Client, callback string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, callback, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("callback answered %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Package storage reads and writes whole files that are either local paths or
+// objects in Amazon S3 (s3://bucket/key) or Google Cloud Storage
+// (gs://bucket/object), so that runs need no local disk.
+//
+// S3 credentials, region and endpoint come from the usual AWS environment and
+// config files; setting AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL also switches
+// to path-style addressing for S3-compatible stores. Google Cloud Storage uses
+// the application default credentials and honours STORAGE_EMULATOR_HOST.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	gcs "cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// IsRemote reports whether name is an object storage URI rather than a path.
+func IsRemote(name string) bool {
+	return strings.HasPrefix(name, "s3://") || strings.HasPrefix(name, "gs://")
+}
+
+func ReadFile(ctx context.Context, name string) ([]byte, error) {
+	if !IsRemote(name) {
+		return os.ReadFile(name)
+	}
+	scheme, bucket, key, err := parse(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	switch scheme {
+	case "s3":
+		client, err := s3Client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		object, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		body = object.Body
+	case "gs":
+		client, err := gcsClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		body, err = client.Bucket(bucket).Object(key).NewReader(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// WriteFile replaces the file or object name with data. Local files are
+// created with mode 0600.
+func WriteFile(ctx context.Context, name string, data []byte) error {
+	if !IsRemote(name) {
+		return os.WriteFile(name, data, 0600)
+	}
+	scheme, bucket, key, err := parse(name)
+	if err != nil {
+		return err
+	}
+
+	switch scheme {
+	case "s3":
+		client, err := s3Client(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = client.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(bucket), Key: aws.String(key), Body: bytes.NewReader(data)})
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	case "gs":
+		client, err := gcsClient(ctx)
+		if err != nil {
+			return err
+		}
+		writer := client.Bucket(bucket).Object(key).NewWriter(ctx)
+		// The data is in memory already, so upload it in a single request.
+		writer.ChunkSize = 0
+		_, err = writer.Write(data)
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func parse(name string) (scheme string, bucket string, key string, err error) {
+	scheme, rest, _ := strings.Cut(name, "://")
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", "", errors.New(name + ": want " + scheme + "://bucket/key")
+	}
+	return scheme, bucket, key, nil
+}
+
+// The clients are created on first use and shared, as they pool connections
+// and cache credentials.
+var (
+	s3Once    sync.Once
+	s3Shared  *s3.Client
+	s3Err     error
+	gcsOnce   sync.Once
+	gcsShared *gcs.Client
+	gcsErr    error
+)
+
+func s3Client(ctx context.Context) (*s3.Client, error) {
+	s3Once.Do(func() {
+		config, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			s3Err = err
+			return
+		}
+		pathStyle := os.Getenv("AWS_ENDPOINT_URL_S3") != "" || os.Getenv("AWS_ENDPOINT_URL") != ""
+		s3Shared = s3.NewFromConfig(config, func(options *s3.Options) {
+			options.UsePathStyle = pathStyle
+			// S3-compatible stores often send no checksums; do not log about it.
+			options.DisableLogOutputChecksumValidationSkipped = true
+		})
+	})
+	return s3Shared, s3Err
+}
+
+func gcsClient(ctx context.Context) (*gcs.Client, error) {
+	gcsOnce.Do(func() {
+		gcsShared, gcsErr = gcs.NewClient(context.WithoutCancel(ctx))
+	})
+	return gcsShared, gcsErr
+}
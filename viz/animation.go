@@ -0,0 +1,66 @@
+package viz
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+type AnimationRecorder struct {
+	Logger *slog.Logger
+
+	graph   *graph.Graph
+	dir     string
+	every   int
+	options GraphVizOptions
+}
+
+func NewAnimationRecorder(g *graph.Graph, dir string, every int, options GraphVizOptions) (*AnimationRecorder, error) {
+	if every < 1 {
+		every = 1
+	}
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnimationRecorder{
+		Logger:  slog.Default(),
+		graph:   g,
+		dir:     dir,
+		every:   every,
+		options: options,
+	}, nil
+}
+
+func (recorder *AnimationRecorder) Record(stats ga.GenerationStats, best ga.Chromosome) {
+	if stats.Generation%recorder.every != 0 && stats.Best != 0 {
+		return
+	}
+
+	frame := *recorder.graph
+	frame.Colors = best
+	options := recorder.options
+	if options.Name != "" {
+		options.Name += "\\n"
+	}
+	options.Name += fmt.Sprintf("generation %d", stats.Generation)
+
+	filename := filepath.Join(recorder.dir, fmt.Sprintf("frame-%06d.dot", stats.Generation))
+	err := SaveGraphViz(filename, &frame, options)
+	if err != nil {
+		recorder.Logger.Warn("failed to save animation frame", "file", filename, "err", err)
+	}
+}
+
+func (recorder *AnimationRecorder) Notify(event ga.Event) {
+	generation, ok := event.(ga.GenerationCompleted)
+	if ok {
+		recorder.Record(generation.Stats, generation.Best)
+	}
+}
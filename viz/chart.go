@@ -0,0 +1,136 @@
+package viz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+)
+
+const (
+	chartWidth     = 800
+	chartHeight    = 400
+	chartMargin    = 50
+	chartMaxPoints = 1000
+	chartTicks     = 5
+)
+
+type chartSeries struct {
+	name  string
+	color string
+	value func(stats ga.GenerationStats) float64
+}
+
+var convergenceSeries = []chartSeries{
+	{"worst", "#d62728", func(stats ga.GenerationStats) float64 { return float64(stats.Worst) }},
+	{"mean", "#1f77b4", func(stats ga.GenerationStats) float64 { return stats.Mean }},
+	{"best", "#2ca02c", func(stats ga.GenerationStats) float64 { return float64(stats.Best) }},
+}
+
+func sampleHistory(history ga.History, maxPoints int) ga.History {
+	if len(history) <= maxPoints {
+		return history
+	}
+
+	step := (len(history) + maxPoints - 1) / maxPoints
+	var sampled ga.History
+	for i := 0; i < len(history); i += step {
+		sampled = append(sampled, history[i])
+	}
+	if sampled[len(sampled)-1].Generation != history[len(history)-1].Generation {
+		sampled = append(sampled, history[len(history)-1])
+	}
+
+	return sampled
+}
+
+func SaveConvergenceChart(filename string, history ga.History) error {
+	var sb strings.Builder
+
+	plotWidth := float64(chartWidth - 2*chartMargin)
+	plotHeight := float64(chartHeight - 2*chartMargin)
+
+	maxGeneration := 1
+	maxScore := 1.0
+	for _, stats := range history {
+		if stats.Generation > maxGeneration {
+			maxGeneration = stats.Generation
+		}
+		if float64(stats.Worst) > maxScore {
+			maxScore = float64(stats.Worst)
+		}
+	}
+
+	x := func(generation int) float64 {
+		return chartMargin + plotWidth*float64(generation)/float64(maxGeneration)
+	}
+	y := func(score float64) float64 {
+		return chartHeight - chartMargin - plotHeight*score/maxScore
+	}
+
+	sb.WriteString(fmt.Sprintf(
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"12\">\n",
+		chartWidth, chartHeight,
+	))
+	sb.WriteString(fmt.Sprintf("\t<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", chartWidth, chartHeight))
+
+	for i := 0; i <= chartTicks; i++ {
+		generation := maxGeneration * i / chartTicks
+		score := maxScore * float64(i) / chartTicks
+		sb.WriteString(fmt.Sprintf(
+			"\t<line x1=\"%.1f\" y1=\"%d\" x2=\"%.1f\" y2=\"%d\" stroke=\"#eee\"/>\n",
+			x(generation), chartMargin, x(generation), chartHeight-chartMargin,
+		))
+		sb.WriteString(fmt.Sprintf(
+			"\t<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">%d</text>\n",
+			x(generation), chartHeight-chartMargin+15, generation,
+		))
+		sb.WriteString(fmt.Sprintf(
+			"\t<line x1=\"%d\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\" stroke=\"#eee\"/>\n",
+			chartMargin, y(score), chartWidth-chartMargin, y(score),
+		))
+		sb.WriteString(fmt.Sprintf(
+			"\t<text x=\"%d\" y=\"%.1f\" text-anchor=\"end\">%.4g</text>\n",
+			chartMargin-5, y(score)+4, score,
+		))
+	}
+	sb.WriteString(fmt.Sprintf(
+		"\t<rect x=\"%d\" y=\"%d\" width=\"%.0f\" height=\"%.0f\" fill=\"none\" stroke=\"black\"/>\n",
+		chartMargin, chartMargin, plotWidth, plotHeight,
+	))
+	sb.WriteString(fmt.Sprintf(
+		"\t<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">generation</text>\n",
+		chartWidth/2, chartHeight-10,
+	))
+	sb.WriteString(fmt.Sprintf(
+		"\t<text x=\"15\" y=\"%d\" text-anchor=\"middle\" transform=\"rotate(-90 15 %d)\">conflicts</text>\n",
+		chartHeight/2, chartHeight/2,
+	))
+
+	sampled := sampleHistory(history, chartMaxPoints)
+	for i, series := range convergenceSeries {
+		var points []string
+		for _, stats := range sampled {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(stats.Generation), y(series.value(stats))))
+		}
+		sb.WriteString(fmt.Sprintf(
+			"\t<polyline fill=\"none\" stroke=\"%s\" stroke-width=\"1.5\" points=\"%s\"/>\n",
+			series.color, strings.Join(points, " "),
+		))
+
+		legendY := chartMargin + 15 + 15*i
+		sb.WriteString(fmt.Sprintf(
+			"\t<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\" stroke-width=\"2\"/>\n",
+			chartWidth-chartMargin-80, legendY-4, chartWidth-chartMargin-60, legendY-4, series.color,
+		))
+		sb.WriteString(fmt.Sprintf(
+			"\t<text x=\"%d\" y=\"%d\">%s</text>\n",
+			chartWidth-chartMargin-55, legendY, series.name,
+		))
+	}
+
+	sb.WriteString("</svg>\n")
+
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}
@@ -0,0 +1,91 @@
+package viz
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/ga"
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+const compareColumnSize = 500
+
+func SaveComparisonSVG(filename string, g *graph.Graph, a ga.Chromosome, b ga.Chromosome, titleA string, titleB string, differences []int) error {
+	positions := CircularLayout(g.NodeCount())
+	extent := 0.0
+	for _, p := range positions {
+		extent = math.Max(extent, math.Max(math.Abs(p.X), math.Abs(p.Y)))
+	}
+	if extent == 0 {
+		extent = 1
+	}
+	scale := (compareColumnSize/2 - 30) / extent
+	radius := math.Max(2, math.Min(8, 600/float64(g.NodeCount()+1)))
+
+	differs := make(map[int]struct{})
+	for _, node := range differences {
+		differs[node] = struct{}{}
+	}
+
+	paletteSize := 0
+	for i := range a {
+		if a[i]+1 > paletteSize {
+			paletteSize = a[i] + 1
+		}
+		if b[i]+1 > paletteSize {
+			paletteSize = b[i] + 1
+		}
+	}
+	palette := HexColors(paletteSize)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"14\">\n",
+		2*compareColumnSize, compareColumnSize,
+	))
+	sb.WriteString(fmt.Sprintf("\t<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", 2*compareColumnSize, compareColumnSize))
+
+	for column, coloring := range []ga.Chromosome{a, b} {
+		offset := float64(column * compareColumnSize)
+		x := func(p Point) float64 { return offset + compareColumnSize/2 + p.X*scale }
+		y := func(p Point) float64 { return compareColumnSize/2 - p.Y*scale }
+
+		title := titleA
+		if column == 1 {
+			title = titleB
+		}
+		sb.WriteString(fmt.Sprintf(
+			"\t<text x=\"%.0f\" y=\"20\" text-anchor=\"middle\">%s</text>\n",
+			offset+compareColumnSize/2, title,
+		))
+
+		for i, neighbours := range g.AdjecencyList {
+			for _, j := range neighbours {
+				stroke, width := "#bbb", 0.5
+				if coloring[i] == coloring[j] {
+					stroke, width = "red", 2.5
+				}
+				sb.WriteString(fmt.Sprintf(
+					"\t<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+					x(positions[i]), y(positions[i]), x(positions[j]), y(positions[j]), stroke, width,
+				))
+			}
+		}
+		for i, p := range positions {
+			outline := ""
+			if _, ok := differs[i]; ok {
+				outline = " stroke=\"black\" stroke-width=\"2\""
+			}
+			sb.WriteString(fmt.Sprintf(
+				"\t<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"%s\"%s><title>node %d, color %d</title></circle>\n",
+				x(p), y(p), radius, palette[coloring[i]], outline, i, coloring[i],
+			))
+		}
+	}
+
+	sb.WriteString("</svg>\n")
+
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}
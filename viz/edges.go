@@ -0,0 +1,129 @@
+package viz
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// SaveEdgeColoringGraphViz draws an edge coloring of g, where coloring holds
+// the color of every edge listed in edges, as graph.LineGraph numbers them.
+// Edges sharing a color with another edge at one of their ends are drawn
+// dashed and thick.
+func SaveEdgeColoringGraphViz(filename string, g *graph.Graph, edges [][2]int, coloring []int, options GraphVizOptions) error {
+	return saveElementColoring(filename, g, nil, edges, coloring, options)
+}
+
+// SaveTotalColoringGraphViz draws a total coloring of g, where coloring holds
+// the colors of the nodes of g followed by those of the edges listed in edges,
+// as graph.TotalGraph numbers them. Nodes and edges that share a color with
+// a neighbour, an incident edge or an end are drawn thick.
+func SaveTotalColoringGraphViz(filename string, g *graph.Graph, edges [][2]int, coloring []int, options GraphVizOptions) error {
+	return saveElementColoring(filename, g, coloring[:g.NodeCount()], edges, coloring[g.NodeCount():], options)
+}
+
+// saveElementColoring draws g with edges colored as edgeColors says, and nodes
+// too if nodeColors is set.
+func saveElementColoring(filename string, g *graph.Graph, nodeColors []int, edges [][2]int, edgeColors []int, options GraphVizOptions) error {
+	// seen counts the edges of every color at every node, the node itself
+	// included if it is colored.
+	seen := make([]map[int]int, g.NodeCount())
+	for i := range seen {
+		seen[i] = map[int]int{}
+		if nodeColors != nil {
+			seen[i][nodeColors[i]]++
+		}
+	}
+	for e, ends := range edges {
+		seen[ends[0]][edgeColors[e]]++
+		if ends[0] != ends[1] {
+			seen[ends[1]][edgeColors[e]]++
+		}
+	}
+	conflicts := 0
+	for _, counts := range seen {
+		for _, count := range counts {
+			conflicts += count * (count - 1) / 2
+		}
+	}
+	conflicting := make([]bool, g.NodeCount())
+	if nodeColors != nil {
+		for i := range conflicting {
+			conflicting[i] = seen[i][nodeColors[i]] > 1
+		}
+		for _, ends := range edges {
+			if u, v := ends[0], ends[1]; u != v && nodeColors[u] == nodeColors[v] {
+				conflicts++
+				conflicting[u], conflicting[v] = true, true
+			}
+		}
+	}
+	var used []int
+	for _, color := range slices.Concat(nodeColors, edgeColors) {
+		if !slices.Contains(used, color) {
+			used = append(used, color)
+		}
+	}
+	slices.Sort(used)
+	paletteSize := 0
+	if len(used) > 0 {
+		paletteSize = used[len(used)-1] + 1
+	}
+	palette := NewPalette(paletteSize)
+
+	sb := strings.Builder{}
+	edgeAttributes := []string{}
+	if palette.Scheme != "" {
+		edgeAttributes = append(edgeAttributes, "colorscheme="+palette.Scheme)
+	}
+	if options.EdgePenWidth > 0 {
+		edgeAttributes = append(edgeAttributes, fmt.Sprintf("penwidth=%g", options.EdgePenWidth))
+	}
+	fmt.Fprintf(&sb, "graph {\n\tnode [%s]\n\tedge [%s]\n", options.nodeAttributes(Palette{}), strings.Join(edgeAttributes, ", "))
+	if options.Layout != "" {
+		fmt.Fprintf(&sb, "\tlayout=%s\n", options.Layout)
+	}
+	label := fmt.Sprintf("conflicts: %d, colors used: %d", conflicts, len(used))
+	if options.Name != "" {
+		label = options.Name + "\\n" + label
+	}
+	fmt.Fprintf(&sb, "\tlabel=\"%s\"\n\tlabelloc=t\n", label)
+
+	for i := 0; i < g.NodeCount(); i++ {
+		style := ""
+		if nodeColors != nil {
+			style = fmt.Sprintf(", style=filled, fillcolor=\"%s\"%s", palette.Colors[nodeColors[i]], schemeAttribute(palette))
+			if conflicting[i] {
+				style += fmt.Sprintf(", penwidth=%g", options.conflictPenWidth())
+			}
+		}
+		fmt.Fprintf(&sb, "\t%d [label=\"%d\"%s%s]\n", i, i, style, options.position(i))
+	}
+	for e, ends := range edges {
+		style := ""
+		if seen[ends[0]][edgeColors[e]] > 1 || seen[ends[1]][edgeColors[e]] > 1 {
+			style = fmt.Sprintf(", style=dashed, penwidth=%g", options.conflictPenWidth())
+		}
+		fmt.Fprintf(&sb, "\t%d -- %d [color=\"%s\"%s]\n", ends[0], ends[1], palette.Colors[edgeColors[e]], style)
+	}
+
+	if !options.HideLegend {
+		fmt.Fprintf(&sb, "\tsubgraph cluster_legend {\n\t\tlabel=\"legend\"\n\t\tnode [shape=box, style=filled%s]\n", schemeAttribute(palette))
+		for _, color := range used {
+			fmt.Fprintf(&sb, "\t\tlegend_%d [label=\"%d\", color=\"%s\"]\n", color, color, palette.Colors[color])
+		}
+		sb.WriteString("\t}\n")
+	}
+	sb.WriteString("}\n")
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}
+
+func schemeAttribute(palette Palette) string {
+	if palette.Scheme == "" {
+		return ""
+	}
+	return ", colorscheme=" + palette.Scheme
+}
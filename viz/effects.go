@@ -0,0 +1,108 @@
+package viz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	effectPanelWidth  = 260
+	effectPanelHeight = 220
+	effectPanelMargin = 45
+	effectColumns     = 3
+)
+
+// EffectPanel is the main effect plot of one parameter: the mean of the
+// objective at every level, in order.
+type EffectPanel struct {
+	Title  string
+	Labels []string
+	Values []float64
+}
+
+// SaveMainEffectsChart draws panels side by side on a shared y axis, with a
+// dashed line at the overall mean, so that steep panels mark the parameters
+// that matter.
+func SaveMainEffectsChart(filename string, panels []EffectPanel, mean float64, objective string) error {
+	var sb strings.Builder
+
+	low, high := mean, mean
+	for _, panel := range panels {
+		for _, value := range panel.Values {
+			low, high = min(low, value), max(high, value)
+		}
+	}
+	if high == low {
+		high = low + 1
+	}
+	columns := min(len(panels), effectColumns)
+	rows := (len(panels) + effectColumns - 1) / effectColumns
+	width := max(columns, 1) * effectPanelWidth
+	height := max(rows, 1) * effectPanelHeight
+
+	sb.WriteString(fmt.Sprintf(
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"11\">\n",
+		width, height,
+	))
+	sb.WriteString(fmt.Sprintf("\t<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height))
+
+	plotWidth := float64(effectPanelWidth - 2*effectPanelMargin)
+	plotHeight := float64(effectPanelHeight - 2*effectPanelMargin)
+	for i, panel := range panels {
+		left := float64(i%effectColumns*effectPanelWidth + effectPanelMargin)
+		top := float64(i/effectColumns*effectPanelHeight + effectPanelMargin)
+		x := func(level int) float64 {
+			if len(panel.Values) < 2 {
+				return left + plotWidth/2
+			}
+			return left + plotWidth*float64(level)/float64(len(panel.Values)-1)
+		}
+		y := func(value float64) float64 {
+			return top + plotHeight - plotHeight*(value-low)/(high-low)
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"\t<rect x=\"%.0f\" y=\"%.0f\" width=\"%.0f\" height=\"%.0f\" fill=\"none\" stroke=\"black\"/>\n",
+			left, top, plotWidth, plotHeight,
+		))
+		sb.WriteString(fmt.Sprintf(
+			"\t<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" font-weight=\"bold\">%s</text>\n",
+			left+plotWidth/2, top-10, panel.Title,
+		))
+		sb.WriteString(fmt.Sprintf(
+			"\t<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#999\" stroke-dasharray=\"4 3\"/>\n",
+			left, y(mean), left+plotWidth, y(mean),
+		))
+		if i%effectColumns == 0 {
+			for _, value := range []float64{low, high} {
+				sb.WriteString(fmt.Sprintf(
+					"\t<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\">%.4g</text>\n",
+					left-5, y(value)+4, value,
+				))
+			}
+			sb.WriteString(fmt.Sprintf(
+				"\t<text x=\"12\" y=\"%.1f\" text-anchor=\"middle\" transform=\"rotate(-90 12 %.1f)\">%s</text>\n",
+				top+plotHeight/2, top+plotHeight/2, objective,
+			))
+		}
+
+		var points []string
+		for level, value := range panel.Values {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(level), y(value)))
+			sb.WriteString(fmt.Sprintf("\t<circle cx=\"%.1f\" cy=\"%.1f\" r=\"3\" fill=\"#1f77b4\"/>\n", x(level), y(value)))
+			sb.WriteString(fmt.Sprintf(
+				"\t<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%s</text>\n",
+				x(level), top+plotHeight+15, panel.Labels[level],
+			))
+		}
+		sb.WriteString(fmt.Sprintf(
+			"\t<polyline fill=\"none\" stroke=\"#1f77b4\" stroke-width=\"1.5\" points=\"%s\"/>\n",
+			strings.Join(points, " "),
+		))
+	}
+
+	sb.WriteString("</svg>\n")
+
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}
@@ -0,0 +1,167 @@
+// Package viz renders graph colorings and solver progress as GraphViz, SVG and
+// TikZ pictures.
+package viz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+type GraphVizOptions struct {
+	Name           string
+	HideLegend     bool
+	Layout         string
+	NodeShape      string
+	NodeSize       float64
+	EdgePenWidth   float64
+	ClusterByColor bool
+	Positions      []Point
+}
+
+func (options GraphVizOptions) nodeAttributes(palette Palette) string {
+	var attributes []string
+	if palette.Scheme != "" {
+		attributes = append(attributes, "colorscheme="+palette.Scheme)
+	}
+	if options.NodeShape != "" {
+		attributes = append(attributes, "shape="+options.NodeShape)
+	}
+	if options.NodeSize > 0 {
+		attributes = append(attributes, fmt.Sprintf("width=%g, height=%g, fixedsize=true", options.NodeSize, options.NodeSize))
+	}
+	return strings.Join(attributes, ", ")
+}
+
+func (options GraphVizOptions) position(node int) string {
+	if node >= len(options.Positions) {
+		return ""
+	}
+	return fmt.Sprintf(", pos=\"%.3f,%.3f!\"", options.Positions[node].X, options.Positions[node].Y)
+}
+
+func (options GraphVizOptions) conflictPenWidth() float64 {
+	if options.EdgePenWidth > 0 {
+		return 3 * options.EdgePenWidth
+	}
+	return 3
+}
+
+func SaveGraphViz(filename string, g *graph.Graph, options GraphVizOptions) error {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	palette := NewPalette(g.PaletteSize())
+	_, err = file.WriteString(fmt.Sprintf("graph {\n\tnode [%s]\n", options.nodeAttributes(palette)))
+	if err != nil {
+		return err
+	}
+	if options.Layout != "" {
+		_, err = file.WriteString(fmt.Sprintf("\tlayout=%s\n", options.Layout))
+		if err != nil {
+			return err
+		}
+	}
+	if options.EdgePenWidth > 0 {
+		_, err = file.WriteString(fmt.Sprintf("\tedge [penwidth=%g]\n", options.EdgePenWidth))
+		if err != nil {
+			return err
+		}
+	}
+
+	label := fmt.Sprintf("conflicts: %d, colors used: %d", g.Conflicts(), g.ColorsUsed())
+	if options.Name != "" {
+		label = options.Name + "\\n" + label
+	}
+	_, err = file.WriteString(fmt.Sprintf("\tlabel=\"%s\"\n\tlabelloc=t\n", label))
+	if err != nil {
+		return err
+	}
+
+	nodeCount := g.NodeCount()
+	for i := 0; i < nodeCount; i++ {
+		for _, j := range g.AdjecencyList[i] {
+			style := ""
+			if g.Colors[i] == g.Colors[j] {
+				style = fmt.Sprintf(" [color=red, penwidth=%g]", options.conflictPenWidth())
+			}
+			_, err = file.WriteString(fmt.Sprintf("\t%d -- %d%s\n", i, j, style))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if options.ClusterByColor {
+		for _, color := range g.UsedColors() {
+			_, err = file.WriteString(fmt.Sprintf(
+				"\tsubgraph cluster_color_%d {\n\t\tlabel=\"color %d\"\n",
+				color,
+				color,
+			))
+			if err != nil {
+				return err
+			}
+			for i := 0; i < nodeCount; i++ {
+				if g.Colors[i] != color {
+					continue
+				}
+				_, err = file.WriteString(fmt.Sprintf(
+					"\t\t%d [style=filled, color=\"%s\"%s]\n",
+					i,
+					palette.Colors[color],
+					options.position(i),
+				))
+				if err != nil {
+					return err
+				}
+			}
+			_, err = file.WriteString("\t}\n")
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		for i := 0; i < nodeCount; i++ {
+			_, err = file.WriteString(fmt.Sprintf(
+				"\t%d [style=filled, color=\"%s\"%s]\n",
+				i,
+				palette.Colors[g.Colors[i]],
+				options.position(i),
+			))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !options.HideLegend {
+		_, err = file.WriteString("\tsubgraph cluster_legend {\n\t\tlabel=\"legend\"\n\t\tnode [shape=box, style=filled]\n")
+		if err != nil {
+			return err
+		}
+		for _, color := range g.UsedColors() {
+			_, err = file.WriteString(fmt.Sprintf(
+				"\t\tlegend_%d [label=\"%d\", color=\"%s\"]\n",
+				color,
+				color,
+				palette.Colors[color],
+			))
+			if err != nil {
+				return err
+			}
+		}
+		_, err = file.WriteString("\t}\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = file.WriteString("}\n")
+	return err
+}
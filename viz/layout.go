@@ -0,0 +1,27 @@
+package viz
+
+import (
+	"math"
+)
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+const layoutNodeSpacing = 0.6
+
+func CircularLayout(nodeCount int) []Point {
+	radius := math.Max(1, layoutNodeSpacing*float64(nodeCount)/(2*math.Pi))
+
+	positions := make([]Point, nodeCount)
+	for i := range positions {
+		angle := 2 * math.Pi * float64(i) / float64(nodeCount)
+		positions[i] = Point{
+			X: radius * math.Cos(angle),
+			Y: radius * math.Sin(angle),
+		}
+	}
+
+	return positions
+}
@@ -0,0 +1,100 @@
+package viz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+)
+
+var ColorList []string
+
+const StartingColor = 10
+
+func LoadColorList(filename string) error {
+	bytes, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(bytes, &ColorList)
+	return err
+}
+
+const graphVizSchemeSize = 8
+
+type Palette struct {
+	Scheme string
+	Colors []string
+}
+
+func NewPalette(count int) Palette {
+	if count <= graphVizSchemeSize {
+		palette := Palette{Scheme: "accent8"}
+		for i := 0; i < count; i++ {
+			palette.Colors = append(palette.Colors, strconv.Itoa(i+1))
+		}
+		return palette
+	}
+
+	if count <= len(ColorList)-StartingColor {
+		return Palette{Colors: ColorList[StartingColor : StartingColor+count]}
+	}
+
+	return Palette{Colors: DistinctColors(count)}
+}
+
+// DistinctColors spaces hues by the golden angle and cycles through a few
+// lightness/saturation bands, so neighbouring indices never look alike.
+func DistinctColors(count int) []string {
+	lightness := []float64{0.55, 0.35, 0.75}
+	saturation := []float64{0.70, 0.90, 0.50}
+
+	colors := make([]string, count)
+	for i := range colors {
+		hue := math.Mod(float64(i)*137.508, 360)
+		band := (i / 3) % len(lightness)
+		r, g, b := hslToRGB(hue, saturation[band], lightness[(i+band)%len(lightness)])
+		colors[i] = fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+
+	return colors
+}
+
+func hslToRGB(hue float64, saturation float64, lightness float64) (uint8, uint8, uint8) {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	sector := hue / 60
+	x := chroma * (1 - math.Abs(math.Mod(sector, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case sector < 1:
+		r, g, b = chroma, x, 0
+	case sector < 2:
+		r, g, b = x, chroma, 0
+	case sector < 3:
+		r, g, b = 0, chroma, x
+	case sector < 4:
+		r, g, b = 0, x, chroma
+	case sector < 5:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	m := lightness - chroma/2
+	channel := func(value float64) uint8 {
+		return uint8(math.Round(255 * (value + m)))
+	}
+	return channel(r), channel(g), channel(b)
+}
+
+var accent8Colors = []string{"#7fc97f", "#beaed4", "#fdc086", "#ffff99", "#386cb0", "#f0027f", "#bf5b17", "#666666"}
+
+func HexColors(count int) []string {
+	if count <= len(accent8Colors) {
+		return accent8Colors[:count]
+	}
+	return DistinctColors(count)
+}
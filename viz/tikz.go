@@ -0,0 +1,48 @@
+package viz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packedbread/gen-alg-graph-coloring/graph"
+)
+
+// TikZMaxNodes is the largest graph a TikZ picture stays readable for.
+const TikZMaxNodes = 200
+
+func SaveTikZ(filename string, g *graph.Graph) error {
+	var sb strings.Builder
+
+	sb.WriteString("% requires \\usepackage{tikz}\n")
+	sb.WriteString("\\begin{tikzpicture}[every node/.style={circle, draw, minimum size=5mm, inner sep=0pt, font=\\tiny}]\n")
+
+	palette := HexColors(g.PaletteSize())
+	for color, hex := range palette {
+		sb.WriteString(fmt.Sprintf(
+			"\t\\definecolor{color%d}{HTML}{%s}\n",
+			color, strings.ToUpper(strings.TrimPrefix(hex, "#")),
+		))
+	}
+
+	for i, p := range CircularLayout(g.NodeCount()) {
+		sb.WriteString(fmt.Sprintf(
+			"\t\\node[fill=color%d] (n%d) at (%.3f,%.3f) {%d};\n",
+			g.Colors[i], i, p.X, p.Y, i,
+		))
+	}
+
+	for i, neighbours := range g.AdjecencyList {
+		for _, j := range neighbours {
+			style := ""
+			if g.Colors[i] == g.Colors[j] {
+				style = "[red, very thick]"
+			}
+			sb.WriteString(fmt.Sprintf("\t\\draw%s (n%d) -- (n%d);\n", style, i, j))
+		}
+	}
+
+	sb.WriteString("\\end{tikzpicture}\n")
+
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}